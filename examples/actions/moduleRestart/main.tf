@@ -0,0 +1,54 @@
+terraform {
+  required_version = ">= 1.14"
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "Infinera#1"
+  host     = "sv-osgams2-dt"
+}
+
+// Actions run during apply without keeping state. Invoke them with
+//   terraform apply -invoke=action.ipm_module_restart.restart
+// or from the lifecycle of a resource.
+action "ipm_module_restart" "restart" {
+  config {
+    device_id           = "617a5eeb-675e-4704-8642-e770cc9c3023"
+    restart_type        = "warmStart"
+    wait_for_completion = true
+  }
+}
+
+action "ipm_ndu_adopt" "adopt" {
+  config {
+    device_id = "9ea2b1a5-6f67-4ba6-a34c-3a8b9ad42d7d"
+  }
+}
+
+action "ipm_ethernet_client_clear_lldp" "clear_lldp" {
+  config {
+    device_id     = "617a5eeb-675e-4704-8642-e770cc9c3023"
+    col_id        = "1"
+    flush_host_db = true
+  }
+}
+
+resource "ipm_module" "module" {
+  identifier = {
+    device_id = "617a5eeb-675e-4704-8642-e770cc9c3023"
+  }
+  config = {
+    m_vlan_mode = "enabled"
+  }
+  lifecycle {
+    action_trigger {
+      events  = [after_update]
+      actions = [action.ipm_ethernet_client_clear_lldp.clear_lldp]
+    }
+  }
+}
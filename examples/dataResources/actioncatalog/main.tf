@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "Infinera#1"
+  host     = "sv-osgams2-dt"
+}
+
+data "ipm_action_catalog" "module_actions" {
+  type = "Module"
+}
+
+output "module_actions" {
+  value = data.ipm_action_catalog.module_actions
+}
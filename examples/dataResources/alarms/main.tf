@@ -0,0 +1,36 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "infinera"
+  host     = "ipm-eval2.westus3.cloudapp.azure.com"
+}
+
+data "ipm_alarms" "critical" {
+  device_id  = "3c1e4b9e-3a1b-4c4e-6c5a-2f0e1d3b4a5c"
+  severities = ["critical"]
+}
+
+data "ipm_alarms" "los_last_day" {
+  resource        = "/modules/3c1e4b9e-3a1b-4c4e-6c5a-2f0e1d3b4a5c/linePtps/1"
+  probable_cause  = "LOS"
+  start_time      = "2026-10-18T00:00:00Z"
+  include_history = true
+}
+
+check "no_critical_alarms" {
+  assert {
+    condition     = data.ipm_alarms.critical.alarm_count == 0
+    error_message = "The module has critical alarms: ${join(", ", [for a in data.ipm_alarms.critical.alarms : a.probable_cause])}"
+  }
+}
+
+output "los_last_day" {
+  value = data.ipm_alarms.los_last_day.alarms
+}
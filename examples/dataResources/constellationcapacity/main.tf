@@ -0,0 +1,57 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "xr"
+  host     = "https://pt-xrivk824-dv"
+}
+
+data "ipm_constellation_capacity" "capacity" {
+  network_id = "8b31a576-3ad3-4e47-a5c8-764211f90165"
+}
+
+// reject the TC when the hub has not enough free DSCs
+resource "ipm_transport_capacity" "tc" {
+  config = {
+    name          = "TC1"
+    capacity_mode = "dscMode"
+  }
+  end_points = [
+    {
+      config = { capacity = 100,
+        selector = {
+          module_if_selector_by_module_name = {
+            module_client_if_aid = "XR-T1"
+            module_name          = "Test_HUB1"
+          }
+        }
+      }
+    },
+    {
+      config = { capacity = 100,
+        selector = {
+          module_if_selector_by_module_name = {
+            module_client_if_aid = "XR-T1"
+            module_name          = "Test_LEAF1"
+          }
+        }
+      }
+    }
+  ]
+  lifecycle {
+    precondition {
+      condition     = data.ipm_constellation_capacity.capacity.tx.free >= 4 && data.ipm_constellation_capacity.capacity.rx.free >= 4
+      error_message = "The hub has not enough free DSCs for 100G."
+    }
+  }
+}
+
+output "capacity" {
+  value = data.ipm_constellation_capacity.capacity
+}
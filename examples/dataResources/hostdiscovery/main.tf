@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "infinera"
+  host     = "ipm-eval4.westus3.cloudapp.azure.com"
+}
+
+data "ipm_host_discovery" "hub_neighbors" {
+  module_name_regex = "^XR HUB"
+}
+
+output "neighbors" {
+  value = [for n in data.ipm_host_discovery.hub_neighbors.neighbors : "${n.module_name} ${n.client_if_aid}: ${coalesce(n.sys_name, n.chassis_id)} port ${coalesce(n.port_id, n.port_source_mac)}"]
+}
@@ -0,0 +1,57 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "infinera"
+  host     = "ipm-eval2.westus3.cloudapp.azure.com"
+}
+
+variable "ndu_id" {
+  type    = string
+  default = "5ff66884-bf1b-4e77-8340-ec9d739c7ca8"
+}
+
+data "ipm_ndu_fan_unit" "fans" {
+  ndu_id = var.ndu_id
+}
+
+data "ipm_ndu_pem" "pems" {
+  ndu_id = var.ndu_id
+}
+
+data "ipm_ndu_leds" "leds" {
+  ndu_id = var.ndu_id
+}
+
+data "ipm_ndu_health" "ndu" {
+  ndu_id = var.ndu_id
+}
+
+check "ndu_health" {
+  assert {
+    condition     = data.ipm_ndu_health.ndu.verdict != "critical"
+    error_message = "NDU ${var.ndu_id} is critical: ${join("; ", data.ipm_ndu_health.ndu.issues)}"
+  }
+}
+
+output "ndu_health" {
+  value = data.ipm_ndu_health.ndu
+}
+
+output "fan_units" {
+  value = data.ipm_ndu_fan_unit.fans.fan_units
+}
+
+output "pems" {
+  value = data.ipm_ndu_pem.pems.pems
+}
+
+output "leds" {
+  value = data.ipm_ndu_leds.leds.leds
+}
@@ -0,0 +1,37 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "infinera"
+  host     = "ipm-eval2.westus3.cloudapp.azure.com"
+}
+
+variable "ndu_id" {
+  type    = string
+  default = "5ff66884-bf1b-4e77-8340-ec9d739c7ca8"
+}
+
+data "ipm_ndu_inventory" "ndu" {
+  ndu_id = var.ndu_id
+}
+
+check "ndu_hardware" {
+  assert {
+    condition     = data.ipm_ndu_inventory.ndu.verified
+    error_message = "NDU ${var.ndu_id} hardware is not the expected one: ${join("; ", [for m in data.ipm_ndu_inventory.ndu.mismatches : "port ${m.port_col_id} ${m.kind} ${m.reason}"])}"
+  }
+}
+
+output "slots" {
+  value = data.ipm_ndu_inventory.ndu.slots
+}
+
+output "mismatches" {
+  value = data.ipm_ndu_inventory.ndu.mismatches
+}
@@ -0,0 +1,41 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "infinera"
+  host     = "ipm-eval2.westus3.cloudapp.azure.com"
+}
+
+data "ipm_module_pm" "hub" {
+  objects = [
+    { type = "line_ptp", identifier = { device_id = "3c1e4b9e-3a1b-4c4e-6c5a-2f0e1d3b4a5c", col_id = "1" } },
+    { type = "carrier", identifier = { device_id = "3c1e4b9e-3a1b-4c4e-6c5a-2f0e1d3b4a5c", parent_col_id = "1", col_id = "1" } },
+    { type = "ethernet_client", identifier = { device_id = "3c1e4b9e-3a1b-4c4e-6c5a-2f0e1d3b4a5c", col_id = "1" } },
+  ]
+  granularity = "15min"
+  start_time  = "2026-10-18T00:00:00Z"
+}
+
+data "ipm_ndu_pm" "ndu" {
+  objects = [
+    { type = "edfa", identifier = { device_id = "5ff66884-bf1b-4e77-8340-ec9d739c7ca8", parent_col_id = "1", col_id = "1" } },
+  ]
+  granularity = "24h"
+  current     = false
+  start_time  = "2026-10-01T00:00:00Z"
+  end_time    = "2026-10-19T00:00:00Z"
+}
+
+output "hub_pm" {
+  value = data.ipm_module_pm.hub.bins
+}
+
+output "ndu_pm" {
+  value = data.ipm_ndu_pm.ndu.bins
+}
@@ -0,0 +1,38 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "xr"
+  host     = "https://pt-xrivk824-dv"
+}
+
+data "ipm_resolve_selector" "leaf" {
+  selector = {
+    host_port_selector_by_name = {
+      host_name      = "host1"
+      host_port_name = "port1"
+    }
+  }
+}
+
+data "ipm_resolve_selector" "hub" {
+  selector = {
+    module_if_selector_by_module_name = {
+      module_name = "Test_HUB1"
+    }
+  }
+}
+
+output "leaf" {
+  value = data.ipm_resolve_selector.leaf
+}
+
+output "same_constellation" {
+  value = data.ipm_resolve_selector.leaf.network_id == data.ipm_resolve_selector.hub.network_id
+}
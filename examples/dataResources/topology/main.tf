@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "xr"
+  host     = "https://pt-xrivk824-dv"
+}
+
+data "ipm_topology" "constellation" {
+  network_id = "8b31a576-3ad3-4e47-a5c8-764211f90165"
+}
+
+// render with: dot -Tsvg constellation.dot -o constellation.svg
+resource "local_file" "dot" {
+  content  = data.ipm_topology.constellation.dot
+  filename = "${path.module}/constellation.dot"
+}
+
+resource "local_file" "json" {
+  content  = data.ipm_topology.constellation.json
+  filename = "${path.module}/constellation.json"
+}
+
+output "edges" {
+  value = [for e in data.ipm_topology.constellation.edges : "${e.kind} ${e.source} -> ${e.target}"]
+}
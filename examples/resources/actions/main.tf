@@ -17,19 +17,38 @@ resource "ipm_actions" "actions" {
   resource_actions = [
     {
       identifier = {
-        module_id = "617a5eeb-675e-4704-8642-e770cc9c3023"
+        device_id = "617a5eeb-675e-4704-8642-e770cc9c3023"
       }
-      type = "module"
+      type = "Module"
       action = "coldStart"
+      stop_at_error = true
     },
     {
       identifier = {
-        module_id = "617a5eeb-675e-4704-8642-e770cc9c3023"
+        device_id = "617a5eeb-675e-4704-8642-e770cc9c3023"
       }
-      type = "module"
+      type = "Module"
+      action = "warmStart"
+      timeout = 900
+      post_conditions = {
+        "state.connectivityState" = "connected"
+      }
+    }
+  ]
+  // the actions run again only when a trigger value changes
+  triggers = {
+    maintenance_window = "2023-09-01"
+  }
+  on_destroy = [
+    {
+      identifier = {
+        device_id = "617a5eeb-675e-4704-8642-e770cc9c3023"
+      }
+      type = "Module"
       action = "warmStart"
     }
   ]
+  confirm_disruptive = true
 }
 
 output "actions" {
@@ -0,0 +1,67 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "infinera"
+  host     = "sv-xrarch-prd.infinera.com"
+}
+
+// Constellation Resource manages the network, its hub module and its leaf modules as one intent.
+// Leaves added to or removed from leaf_modules are added to or removed from the network on update.
+resource "ipm_constellation" "constellation" {
+  config = {
+    name                    = "Network1"
+    constellation_frequency = 193300000
+    modulation              = "16QAM"
+    topology                = "auto"
+  }
+  hub_module = {
+    config = {
+      selector = {
+        module_selector_by_module_name = {
+          module_name = "Test_HUB1"
+        }
+      }
+      module = {
+        traffic_mode = "L1Mode"
+      }
+    }
+  }
+  leaf_modules = {
+    leaf1 = {
+      config = {
+        selector = {
+          module_selector_by_module_name = {
+            module_name = "Test_LEAF1"
+          }
+        }
+        module = {
+          traffic_mode = "L1Mode"
+        }
+      }
+    }
+    leaf2 = {
+      config = {
+        selector = {
+          module_selector_by_module_name = {
+            module_name = "Test_LEAF2"
+          }
+        }
+        module = {
+          traffic_mode     = "L1Mode"
+          planned_capacity = "100G"
+        }
+      }
+    }
+  }
+}
+
+output "leaf_lifecycle_states" {
+  value = { for k, v in ipm_constellation.constellation.leaf_modules : k => v.lifecycle_state }
+}
@@ -0,0 +1,30 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "infinera"
+  host     = "ipm-eval4.westus3.cloudapp.azure.com"
+}
+
+// a host for each router learned by LLDP on the modules of site a, with a port for each router port
+resource "ipm_hosts_from_lldp" "site_a_routers" {
+  module_labels = {
+    site = "a"
+  }
+  host_name_template = "router-{sys_name}"
+  port_name_template = "{port_id} ({module_name} {client_if_aid})"
+  managed_by         = "terraform"
+  labels = {
+    discovered_by = "lldp"
+  }
+}
+
+output "hosts" {
+  value = { for name, host in ipm_hosts_from_lldp.site_a_routers.hosts : name => keys(host.ports) }
+}
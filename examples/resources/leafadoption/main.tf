@@ -0,0 +1,38 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "infinera"
+  host     = "ipm-eval4.westus3.cloudapp.azure.com"
+
+  profiles = {
+    module_config_profiles = {
+      leaf_400g = {
+        traffic_mode          = "L1Mode"
+        fiber_connection_mode = "dual"
+        planned_capacity      = "400G"
+      }
+    }
+  }
+}
+
+// adopt the reachable modules named LEAF-* with an allowed serial number as leaves of the constellation
+resource "ipm_leaf_adoption" "site1" {
+  network_id = "8b31a576-3ad3-4e47-a5c8-764211f90165"
+  match = {
+    serial_numbers = ["N1234567", "N2345678", "N3456789"]
+    name_regex     = "^LEAF-"
+  }
+  profile          = "leaf_400g"
+  remove_unmatched = true
+}
+
+output "adopted_leaves" {
+  value = ipm_leaf_adoption.site1.leaves
+}
@@ -0,0 +1,40 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "infinera"
+  host     = "ipm-eval4.westus3.cloudapp.azure.com"
+
+  profiles = {
+    module_config_profiles = {
+      leaf_config_profile1 = { traffic_mode = "L1Mode", fiber_connection_mode = "dual", planned_capacity = "100G" }
+    }
+  }
+}
+
+// the 16 leaves are added by one request
+resource "ipm_leaf_modules" "leaves" {
+  network_id = "8b31a576-3ad3-4e47-a5c8-764211f90165"
+  profile    = "leaf_config_profile1"
+  leaf_modules = {
+    for i in range(1, 17) : "LEAF${i}" => {
+      config = {
+        selector = {
+          module_selector_by_module_name = {
+            module_name = "LEAF${i}"
+          }
+        }
+      }
+    }
+  }
+}
+
+output "leaves" {
+  value = { for k, v in ipm_leaf_modules.leaves.leaf_modules : k => v.lifecycle_state }
+}
@@ -0,0 +1,43 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "infinera"
+  host     = "ipm-eval4.westus3.cloudapp.azure.com"
+}
+
+resource "ipm_link_test" "turn_up" {
+  duration = 60
+  targets = [
+    {
+      type       = "dsc"
+      identifier = { href = "/modules/3c1e4b9e-3a1b-4c4e-6c5a-2f0e1d3b4a5c/linePtps/1/carriers/1/dscs/1" }
+      prbs       = true
+    },
+    {
+      type       = "ethernet_client"
+      identifier = { device_id = "3c1e4b9e-3a1b-4c4e-6c5a-2f0e1d3b4a5c", col_id = "1" }
+      loopback   = "facility"
+    },
+  ]
+  triggers = {
+    run = "2026-10-19"
+  }
+
+  lifecycle {
+    postcondition {
+      condition     = self.passed
+      error_message = "The link test failed: ${jsonencode(self.results)}"
+    }
+  }
+}
+
+output "link_test" {
+  value = ipm_link_test.turn_up
+}
@@ -0,0 +1,34 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "infinera"
+  host     = "ipm-eval4.westus3.cloudapp.azure.com"
+}
+
+// the leaves of site a get the same management VLAN mode and a closed debug port
+resource "ipm_module_policy" "site_a_leaves" {
+  selector = {
+    labels = {
+      site = "a"
+    }
+    name_regex = "^XR LEAF"
+  }
+  config = {
+    labels = {
+      managed_by = "terraform"
+    }
+    m_vlan_mode       = "lagged"
+    debug_port_access = "disabled"
+  }
+}
+
+output "site_a_drift" {
+  value = { for name, module in ipm_module_policy.site_a_leaves.modules : name => module.drift if !module.in_sync }
+}
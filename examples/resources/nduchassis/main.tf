@@ -0,0 +1,47 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "infinera"
+  host     = "ipm-eval4.westus3.cloudapp.azure.com"
+}
+
+// port 1 takes a TOM with an XR, port 5 an amplified line with a VOA
+resource "ipm_ndu_chassis" "ndu1" {
+  ndu_id = "2c6b4f0e-3f1a-4b7e-9a3c-1d5e8f0a2b4c"
+  ports = {
+    "1" = {
+      name = "client-1"
+      tom = {
+        required_type     = "QSFP-DD"
+        required_sub_type = "400G-ZR+"
+      }
+      xr = {
+        required_type = "XR"
+      }
+    }
+    "5" = {
+      name         = "line-east"
+      connected_to = "ROADM-A 1/3"
+      edfa = {
+        required_type    = "EDFA"
+        amplifier_enable = true
+        gain_target      = 12
+      }
+      voa = {
+        required_type      = "VOA"
+        voa_attenuation_tx = 3
+      }
+    }
+  }
+}
+
+output "chassis_children" {
+  value = ipm_ndu_chassis.ndu1.children
+}
@@ -0,0 +1,36 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "infinera"
+  host     = "ipm-eval4.westus3.cloudapp.azure.com"
+}
+
+// set dry_run to false once the proposed settings are reviewed
+resource "ipm_ndu_power_balance" "ndu1" {
+  ndu_id              = "2c6b4f0e-3f1a-4b7e-9a3c-1d5e8f0a2b4c"
+  pol_power_ctrl_mode = "manual"
+  spans = [
+    { port_col_id = "5", target_launch_power = 1.0 },
+    { port_col_id = "6", target_launch_power = -2.5 },
+  ]
+  // the launch power reported in the port data of the NDU
+  power_attribute = "state.txPower"
+  tolerance       = 0.5
+  max_iterations  = 5
+  settle_time     = 15
+  dry_run         = true
+  triggers = {
+    fiber_change = "2024-05-02"
+  }
+}
+
+output "power_balance" {
+  value = ipm_ndu_power_balance.ndu1.results
+}
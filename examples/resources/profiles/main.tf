@@ -0,0 +1,109 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+// The profiles replace the network_profiles.json, nc_profiles.json and tc_profiles.json files.
+// References between the profiles and from the resources are checked at plan time.
+provider "ipm" {
+  username = "xr-user-1"
+  password = "infinera"
+  host     = "sv-xrarch-prd.infinera.com"
+  profiles = {
+    network_profiles = {
+      network_profile1 = { network_config_profile = "network_config_profile1", hub_config_profile = "hub_config_profile1", leaf_config_profile = "leaf_config_profile1" }
+    }
+    network_config_profiles = {
+      network_config_profile1 = { constellation_frequency = 194000000, modulation = "16QAM", tc_mode = true, topology = "p2mp" }
+    }
+    module_config_profiles = {
+      hub_config_profile1  = { traffic_mode = "L1Mode", fiber_connection_mode = "dual", planned_capacity = "400G", requested_nominal_psd_offset = "0dB", fec_iterations = "standard", tx_clp_target = -5 }
+      leaf_config_profile1 = { traffic_mode = "L1Mode", fiber_connection_mode = "dual", planned_capacity = "100G", requested_nominal_psd_offset = "0dB", fec_iterations = "standard", tx_clp_target = -5 }
+    }
+    nc_profiles = {
+      nc_profile1 = { service_mode = "XR-L1", endpoint_capacity = 100 }
+    }
+    tc_profiles = {
+      system_tc_profile1 = { capacity_mode = "portMode", labels = { region = "South" }, endpoint_capacity = 100 }
+    }
+  }
+}
+
+resource "ipm_constellation_network" "constellation_network" {
+  profile = "network_profile1"
+  config = {
+    name = "Network1"
+  }
+  hub_module = {
+    config = {
+      selector = {
+        module_selector_by_module_name = {
+          module_name = "Test_HUB1"
+        }
+      }
+    }
+  }
+}
+
+resource "ipm_transport_capacity" "tc" {
+  profile = "system_tc_profile1"
+  config = {
+    name = "TC1"
+  }
+  end_points = [
+    {
+      config = {
+        selector = {
+          module_if_selector_by_module_name = {
+            module_client_if_aid = "XR-T1"
+            module_name          = "Test_HUB1"
+          }
+        }
+      }
+    },
+    {
+      config = {
+        selector = {
+          module_if_selector_by_module_name = {
+            module_client_if_aid = "XR-T1"
+            module_name          = "Test_LEAF1"
+          }
+        }
+      }
+    }
+  ]
+  depends_on = [ipm_constellation_network.constellation_network]
+}
+
+resource "ipm_network_connection" "nc" {
+  profile = "nc_profile1"
+  config = {
+    name = "NC1"
+  }
+  end_points = [
+    {
+      config = {
+        selector = {
+          module_if_selector_by_module_name = {
+            module_client_if_aid = "XR-T1"
+            module_name          = "Test_HUB1"
+          }
+        }
+      }
+    },
+    {
+      config = {
+        selector = {
+          module_if_selector_by_module_name = {
+            module_client_if_aid = "XR-T1"
+            module_name          = "Test_LEAF1"
+          }
+        }
+      }
+    }
+  ]
+  depends_on = [ipm_transport_capacity.tc]
+}
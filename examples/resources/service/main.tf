@@ -0,0 +1,52 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "infinera"
+  host     = "ipm-eval4.westus3.cloudapp.azure.com"
+}
+
+// P2MP service: a TC between the hub and each leaf, then the NC between the hub and all the leaves
+resource "ipm_service" "service" {
+  name          = "SERVICE1"
+  service_mode  = "XR-L1"
+  capacity_mode = "portMode"
+  labels        = { label1="label1" }
+  capacity      = 100
+  timeout       = 600
+  hub = {
+    module_if_selector_by_module_name = {
+      module_client_if_aid = "XR-T1"
+      module_name = "Test_HUB1"
+    }
+  }
+  leaves = {
+    leaf1 = {
+      selector = {
+        module_if_selector_by_module_name = {
+          module_client_if_aid = "XR-T1"
+          module_name = "Test_LEAF1"
+        }
+      }
+    }
+    leaf2 = {
+      capacity = 100
+      selector = {
+        module_if_selector_by_module_name = {
+          module_client_if_aid = "XR-T1"
+          module_name = "Test_LEAF2"
+        }
+      }
+    }
+  }
+}
+
+output "service" {
+  value = ipm_service.service
+}
@@ -0,0 +1,36 @@
+terraform {
+  required_providers {
+    ipm = {
+      source = "infinera.com/poc/ipm"
+    }
+  }
+}
+
+provider "ipm" {
+  username = "xr-user-1"
+  password = "infinera"
+  host     = "ipm-eval4.westus3.cloudapp.azure.com"
+}
+
+// upgrades the hub and the modules of site a, two at a time
+resource "ipm_software_upgrade" "site_a" {
+  device_type = "module"
+  version     = "6.2.1"
+  devices = [
+    { device_name = "XR HUB 1" }
+  ]
+  labels = {
+    site = "a"
+  }
+  batch_size = 2
+  timeout    = 2400
+  health_check = {
+    "state.connectivityState" = "connected"
+    "state.lifecycleState"    = "configured"
+  }
+  rollback_on_failure = true
+}
+
+output "upgrade_status" {
+  value = ipm_software_upgrade.site_a.device_status
+}
@@ -96,6 +96,11 @@ func NewClient(host, username, password *string) (*Client, error) {
 }
 
 func (c *Client) doRequest(req *http.Request) ([]byte, error) {
+	_, body, err := c.doRequestWithStatus(req)
+	return body, err
+}
+
+func (c *Client) doRequestWithStatus(req *http.Request) (int, []byte, error) {
 
 	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	req.Header.Set("Authorization", c.Token)
@@ -114,21 +119,21 @@ func (c *Client) doRequest(req *http.Request) ([]byte, error) {
 
 	if err != nil {
 		log.Debugf("doRequest: Send HTTP Request error %v", err)
-		return nil, err
+		return 0, nil, err
 	}
 	defer res.Body.Close()
 
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		log.Debugf("doRequest: Can not read Reponse Body. error %v", err)
-		return nil, err
+		return res.StatusCode, nil, err
 	}
 
 	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusAccepted {
-		return nil, fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
+		return res.StatusCode, body, fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
 	}
 
-	return body, err
+	return res.StatusCode, body, err
 }
 
 // executes commands on IPM
@@ -150,4 +155,23 @@ func (c *Client) ExecuteIPMHttpCommand(command, commanduri string, commandBody [
 	}
 	log.Debugf("ExecuteIPMHttpCommand: Send HTTP Request SUCCESS. Response = %s", string(body))
 	return body, err
-}
\ No newline at end of file
+}
+
+// executes commands on IPM and returns the HTTP status code with the response body, also when the request failed
+func (c *Client) ExecuteIPMHttpCommandWithStatus(command, commanduri string, commandBody []byte) (status int, result []byte, err error) {
+
+	log.Debugf("ExecuteIPMHttpCommandWithStatus:New HTTP Request https://%s/api/v1%s", c.HostURL, commanduri)
+	req, err := http.NewRequest(command, fmt.Sprintf("https://%s/api/v1%s", c.HostURL, commanduri), bytes.NewBuffer(commandBody))
+	if err != nil {
+		log.Errorf("ExecuteIPMHttpCommandWithStatus: Create New HTTP Request failed error %v", err)
+		return 0, nil, err
+	}
+
+	status, body, err := c.doRequestWithStatus(req)
+	if err != nil {
+		log.Errorf("ExecuteIPMHttpCommandWithStatus: Send HTTP NewRequest failed. status = %d, error= %v", status, err)
+		return status, body, err
+	}
+	log.Debugf("ExecuteIPMHttpCommandWithStatus: Send HTTP Request SUCCESS. status = %d, Response = %s", status, string(body))
+	return status, body, err
+}
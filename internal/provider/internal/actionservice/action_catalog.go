@@ -0,0 +1,109 @@
+package actionservice
+
+import (
+	"errors"
+	"strings"
+
+	common "terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ActionType describes the actions supported by a resource type and the URL of the resource.
+// The URL template refers to the identifier attributes as {device_id}, {grand_parent_col_id}, {parent_col_id} and {col_id}.
+type ActionType struct {
+	Type        string
+	URLTemplate string
+	Actions     []string
+}
+
+// actionCatalog lists the resource types of ipm_actions, see the IPM API of the action endpoints.
+var actionCatalog = []ActionType{
+	{Type: "NDU", URLTemplate: "/ndus/{device_id}", Actions: []string{"coldStart", "warmStart", "factoryReset", "retry", "adopt"}},
+	{Type: "NDU Port", URLTemplate: "/ndus/{device_id}/ports/{parent_col_id}", Actions: []string{"retry", "adopt"}},
+	{Type: "NDU TOM", URLTemplate: "/ndus/{device_id}/ports/{parent_col_id}/tom/{col_id}", Actions: []string{"retry", "adopt"}},
+	{Type: "NDU XR", URLTemplate: "/ndus/{device_id}/ports/{parent_col_id}/xr/{col_id}", Actions: []string{"coldStart", "warmStart", "factoryReset", "retry", "adopt"}},
+	{Type: "NDU EDFA", URLTemplate: "/ndus/{device_id}/ports/{parent_col_id}/edfa/{col_id}", Actions: []string{"retry", "adopt"}},
+	{Type: "NDU VOA", URLTemplate: "/ndus/{device_id}/ports/{parent_col_id}/voa/{col_id}", Actions: []string{"retry", "adopt"}},
+	{Type: "NDU Line PTP", URLTemplate: "/ndus/{device_id}/ports/{parent_col_id}/linePtps/{col_id}", Actions: []string{"retry", "adopt"}},
+	{Type: "NDU Trib PTP", URLTemplate: "/ndus/{device_id}/ports/{parent_col_id}/tribPtps/{col_id}", Actions: []string{"retry", "adopt"}},
+	{Type: "NDU POL PTP", URLTemplate: "/ndus/{device_id}/ports/{parent_col_id}/polPtps/{col_id}", Actions: []string{"retry", "adopt"}},
+	{Type: "NDU Carrier", URLTemplate: "/ndus/{device_id}/ports/{grand_parent_col_id}/linePtps/{parent_col_id}/carrier/{col_id}", Actions: []string{"retry", "adopt"}},
+	{Type: "NDU OTU", URLTemplate: "/ndus/{device_id}/otus/{col_id}", Actions: []string{"retry"}},
+	{Type: "NDU Ethernet Client", URLTemplate: "/ndus/{device_id}/ethernets/{col_id}", Actions: []string{"clrLldpStats", "flushLldpHostDb", "retry"}},
+	{Type: "Module", URLTemplate: "/modules/{device_id}", Actions: []string{"coldStart", "warmStart", "factoryReset", "retry"}},
+	{Type: "Ethernet Client", URLTemplate: "/modules/{device_id}/ethernetClients/{col_id}", Actions: []string{"clrLldpStats", "flushLldpHostDb", "retry"}},
+	{Type: "OTU", URLTemplate: "/modules/{device_id}/otus/{col_id}", Actions: []string{"retry"}},
+	{Type: "ODU", URLTemplate: "/modules/{device_id}/otus/{parent_col_id}/odus/{col_id}", Actions: []string{"retry"}},
+	{Type: "Carrier", URLTemplate: "/modules/{device_id}/linePtps/{parent_col_id}/carriers/{col_id}", Actions: []string{"retry"}},
+	{Type: "DSC", URLTemplate: "/modules/{device_id}/linePtps/{grand_parent_col_id}/carriers/{parent_col_id}/dscs/{col_id}", Actions: []string{"retry"}},
+	{Type: "DSCG", URLTemplate: "/modules/{device_id}/linePtps/{grand_parent_col_id}/carriers/{parent_col_id}/dsgs/{col_id}", Actions: []string{"retry"}},
+}
+
+// identifierAttributes are the identifier attributes which can be referred by an URL template, in URL order.
+var identifierAttributes = []string{"device_id", "grand_parent_col_id", "parent_col_id", "col_id"}
+
+// FindActionType returns the catalog entry of the resource type.
+func FindActionType(resourceType string) (ActionType, bool) {
+	for _, actionType := range actionCatalog {
+		if actionType.Type == resourceType {
+			return actionType, true
+		}
+	}
+	return ActionType{}, false
+}
+
+// RequiredIdentifiers returns the identifier attributes which the URL of the resource type needs.
+func (actionType ActionType) RequiredIdentifiers() []string {
+	required := []string{}
+	for _, name := range identifierAttributes {
+		if strings.Contains(actionType.URLTemplate, "{"+name+"}") {
+			required = append(required, name)
+		}
+	}
+	return required
+}
+
+// SupportsAction tells whether the action is valid for the resource type.
+func (actionType ActionType) SupportsAction(action string) bool {
+	return common.Contains(actionType.Actions, action)
+}
+
+// Validate checks the action and the identifier attributes needed by the URL. Unknown values are not checked.
+// It returns the name of the attribute in error, with the error.
+func (actionType ActionType) Validate(action types.String, identifier common.ResourceIdentifier) (string, error) {
+	if !action.IsUnknown() {
+		if action.IsNull() || action.ValueString() == "" {
+			return "action", errors.New("Action is not specified.")
+		}
+		if !actionType.SupportsAction(action.ValueString()) {
+			return "action", errors.New("Invalid action " + action.ValueString() + " for type " + actionType.Type + ". Valid actions: " + strings.Join(actionType.Actions, ", "))
+		}
+	}
+	values := identifierValues(identifier)
+	for _, name := range actionType.RequiredIdentifiers() {
+		value := values[name]
+		if !value.IsUnknown() && (value.IsNull() || value.ValueString() == "") {
+			return "identifier", errors.New("Type " + actionType.Type + " requires identifier " + name + ".")
+		}
+	}
+	return "", nil
+}
+
+// URL returns the URL of the action for the identified resource.
+func (actionType ActionType) URL(action string, identifier common.ResourceIdentifier) string {
+	url := actionType.URLTemplate
+	for name, value := range identifierValues(identifier) {
+		url = strings.ReplaceAll(url, "{"+name+"}", value.ValueString())
+	}
+	return url + "/" + action
+}
+
+func identifierValues(identifier common.ResourceIdentifier) map[string]types.String {
+	return map[string]types.String{
+		"device_id":           identifier.DeviceId,
+		"grand_parent_col_id": identifier.GrandParentColId,
+		"parent_col_id":       identifier.ParentColId,
+		"col_id":              identifier.ColId,
+	}
+}
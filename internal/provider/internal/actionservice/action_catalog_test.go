@@ -0,0 +1,105 @@
+package actionservice
+
+import (
+	"testing"
+
+	common "terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestFindActionType(t *testing.T) {
+	tests := []struct {
+		resourceType string
+		found        bool
+		required     []string
+	}{
+		{"Module", true, []string{"device_id"}},
+		{"NDU Carrier", true, []string{"device_id", "grand_parent_col_id", "parent_col_id", "col_id"}},
+		{"Ethernet Client", true, []string{"device_id", "col_id"}},
+		{"module", false, nil},
+		{"", false, nil},
+	}
+	for _, test := range tests {
+		actionType, found := FindActionType(test.resourceType)
+		if found != test.found {
+			t.Errorf("FindActionType(%q) found = %v, want %v", test.resourceType, found, test.found)
+			continue
+		}
+		if !found {
+			continue
+		}
+		required := actionType.RequiredIdentifiers()
+		if len(required) != len(test.required) {
+			t.Errorf("%s RequiredIdentifiers() = %v, want %v", test.resourceType, required, test.required)
+			continue
+		}
+		for i := range required {
+			if required[i] != test.required[i] {
+				t.Errorf("%s RequiredIdentifiers() = %v, want %v", test.resourceType, required, test.required)
+				break
+			}
+		}
+	}
+}
+
+func TestActionTypeValidate(t *testing.T) {
+	module, _ := FindActionType("Module")
+	dsc, _ := FindActionType("DSC")
+	tests := []struct {
+		name       string
+		actionType ActionType
+		action     types.String
+		identifier common.ResourceIdentifier
+		attribute  string
+	}{
+		{"valid", module, types.StringValue("warmStart"), common.ResourceIdentifier{DeviceId: types.StringValue("m1")}, ""},
+		{"unknown action", module, types.StringUnknown(), common.ResourceIdentifier{DeviceId: types.StringValue("m1")}, ""},
+		{"unknown identifier", module, types.StringValue("coldStart"), common.ResourceIdentifier{DeviceId: types.StringUnknown()}, ""},
+		{"missing action", module, types.StringNull(), common.ResourceIdentifier{DeviceId: types.StringValue("m1")}, "action"},
+		{"empty action", module, types.StringValue(""), common.ResourceIdentifier{DeviceId: types.StringValue("m1")}, "action"},
+		{"unsupported action", module, types.StringValue("adopt"), common.ResourceIdentifier{DeviceId: types.StringValue("m1")}, "action"},
+		{"missing device", module, types.StringValue("retry"), common.ResourceIdentifier{DeviceId: types.StringNull()}, "identifier"},
+		{"missing col id", dsc, types.StringValue("retry"), common.ResourceIdentifier{
+			DeviceId:         types.StringValue("m1"),
+			GrandParentColId: types.StringValue("1"),
+			ParentColId:      types.StringValue("1"),
+			ColId:            types.StringNull(),
+		}, "identifier"},
+	}
+	for _, test := range tests {
+		attribute, err := test.actionType.Validate(test.action, test.identifier)
+		if attribute != test.attribute {
+			t.Errorf("%s: Validate() attribute = %q, want %q", test.name, attribute, test.attribute)
+		}
+		if (err != nil) != (test.attribute != "") {
+			t.Errorf("%s: Validate() error = %v", test.name, err)
+		}
+	}
+}
+
+func TestActionTypeURL(t *testing.T) {
+	tests := []struct {
+		resourceType string
+		action       string
+		identifier   common.ResourceIdentifier
+		url          string
+	}{
+		{"Module", "warmStart", common.ResourceIdentifier{DeviceId: types.StringValue("m1")}, "/modules/m1/warmStart"},
+		{"NDU", "adopt", common.ResourceIdentifier{DeviceId: types.StringValue("n1")}, "/ndus/n1/adopt"},
+		{"Ethernet Client", "flushLldpHostDb", common.ResourceIdentifier{DeviceId: types.StringValue("m1"), ColId: types.StringValue("2")},
+			"/modules/m1/ethernetClients/2/flushLldpHostDb"},
+		{"DSC", "retry", common.ResourceIdentifier{
+			DeviceId:         types.StringValue("m1"),
+			GrandParentColId: types.StringValue("1"),
+			ParentColId:      types.StringValue("1"),
+			ColId:            types.StringValue("7"),
+		}, "/modules/m1/linePtps/1/carriers/1/dscs/7/retry"},
+	}
+	for _, test := range tests {
+		actionType, _ := FindActionType(test.resourceType)
+		if url := actionType.URL(test.action, test.identifier); url != test.url {
+			t.Errorf("%s URL(%q) = %q, want %q", test.resourceType, test.action, url, test.url)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package actionservice
+
+import (
+	"context"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	common "terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ action.Action              = &EClientClearLLDPAction{}
+	_ action.ActionWithConfigure = &EClientClearLLDPAction{}
+)
+
+// NewEClientClearLLDPAction is a helper function to simplify the provider implementation.
+func NewEClientClearLLDPAction() action.Action {
+	return &EClientClearLLDPAction{}
+}
+
+type EClientClearLLDPAction struct {
+	client *ipm_pf.Client
+}
+
+type EClientClearLLDPActionData struct {
+	DeviceId    types.String `tfsdk:"device_id"`
+	ColId       types.String `tfsdk:"col_id"`
+	FlushHostDb types.Bool   `tfsdk:"flush_host_db"`
+}
+
+// Metadata returns the action type name.
+func (a *EClientClearLLDPAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ethernet_client_clear_lldp"
+}
+
+// Schema defines the schema for the action.
+func (a *EClientClearLLDPAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Clears the LLDP statistics of a module ethernet client",
+		Attributes: map[string]schema.Attribute{
+			"device_id": schema.StringAttribute{
+				Description: "Device ID of the module.",
+				Required:    true,
+			},
+			"col_id": schema.StringAttribute{
+				Description: "Col ID of the ethernet client.",
+				Required:    true,
+			},
+			"flush_host_db": schema.BoolAttribute{
+				Description: "Flush the LLDP host database as well.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the action.
+func (a *EClientClearLLDPAction) Configure(_ context.Context, req action.ConfigureRequest, _ *action.ConfigureResponse) {
+	a.client = actionClient(req)
+}
+
+func (a *EClientClearLLDPAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data EClientClearLLDPActionData
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	tflog.Debug(ctx, "EClientClearLLDPAction: Invoke - ", map[string]interface{}{"EClientClearLLDPActionData": data})
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	identifier := common.ResourceIdentifier{DeviceId: data.DeviceId, ColId: data.ColId}
+	invokeResourceAction(ctx, a.client, ResourceAction{
+		Type:       types.StringValue("Ethernet Client"),
+		Identifier: identifier,
+		Action:     types.StringValue("clrLldpStats"),
+	}, resp)
+	if resp.Diagnostics.HasError() || data.FlushHostDb.IsNull() || !data.FlushHostDb.ValueBool() {
+		return
+	}
+	invokeResourceAction(ctx, a.client, ResourceAction{
+		Type:       types.StringValue("Ethernet Client"),
+		Identifier: identifier,
+		Action:     types.StringValue("flushLldpHostDb"),
+	}, resp)
+}
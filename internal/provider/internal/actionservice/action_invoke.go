@@ -0,0 +1,62 @@
+package actionservice
+
+import (
+	"context"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// invokeResourceAction runs a single action of the catalog for a Terraform action. The URL, the request and the
+// completion wait are the ones of ipm_actions, but nothing is kept in state.
+func invokeResourceAction(ctx context.Context, client *ipm_pf.Client, resourceAction ResourceAction, resp *action.InvokeResponse) {
+	command, err := getActionCommand(resourceAction)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invoke: Invalid action "+resourceAction.Type.ValueString()+" "+resourceAction.Action.ValueString(),
+			err.Error(),
+		)
+		return
+	}
+	resourceAction.StopAtError = types.BoolValue(true)
+
+	tflog.Debug(ctx, "invokeResourceAction: ", map[string]interface{}{"command": command})
+	resp.SendProgress(action.InvokeProgressEvent{Message: "POST " + command})
+	resourceActions := []ResourceAction{resourceAction}
+	applyActions(ctx, client, resourceActions, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if resourceActions[0].waitRequired() {
+		resp.SendProgress(action.InvokeProgressEvent{Message: command + " " + resourceActions[0].CompletionState.ValueString()})
+	} else {
+		resp.SendProgress(action.InvokeProgressEvent{Message: command + " sent"})
+	}
+}
+
+// actionClient returns the client of the provider data, as Configure of resources does.
+func actionClient(req action.ConfigureRequest) *ipm_pf.Client {
+	if req.ProviderData == nil {
+		return nil
+	}
+	return req.ProviderData.(*common.ProviderData).Client
+}
+
+// waitSchemaAttributes are the completion attributes shared by the actions.
+func waitSchemaAttributes(attributes map[string]schema.Attribute) map[string]schema.Attribute {
+	attributes["wait_for_completion"] = schema.BoolAttribute{
+		Description: "Wait until the device is connected again. A device which is connected when the action is sent has to get disconnected first.",
+		Optional:    true,
+	}
+	attributes["timeout"] = schema.Int64Attribute{
+		Description: "Time in seconds to wait for the completion of the action. Default is 600.",
+		Optional:    true,
+	}
+	return attributes
+}
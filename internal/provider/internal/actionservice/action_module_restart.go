@@ -0,0 +1,101 @@
+package actionservice
+
+import (
+	"context"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	common "terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ action.Action                   = &ModuleRestartAction{}
+	_ action.ActionWithConfigure      = &ModuleRestartAction{}
+	_ action.ActionWithValidateConfig = &ModuleRestartAction{}
+)
+
+// NewModuleRestartAction is a helper function to simplify the provider implementation.
+func NewModuleRestartAction() action.Action {
+	return &ModuleRestartAction{}
+}
+
+type ModuleRestartAction struct {
+	client *ipm_pf.Client
+}
+
+type ModuleRestartActionData struct {
+	DeviceId          types.String `tfsdk:"device_id"`
+	RestartType       types.String `tfsdk:"restart_type"`
+	WaitForCompletion types.Bool   `tfsdk:"wait_for_completion"`
+	Timeout           types.Int64  `tfsdk:"timeout"`
+}
+
+// Metadata returns the action type name.
+func (a *ModuleRestartAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_module_restart"
+}
+
+// Schema defines the schema for the action.
+func (a *ModuleRestartAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Restarts an XR Module",
+		Attributes: waitSchemaAttributes(map[string]schema.Attribute{
+			"device_id": schema.StringAttribute{
+				Description: "Device ID of the module.",
+				Required:    true,
+			},
+			"restart_type": schema.StringAttribute{
+				Description: "warmStart (default) or coldStart.",
+				Optional:    true,
+			},
+		}),
+	}
+}
+
+// Configure adds the provider configured client to the action.
+func (a *ModuleRestartAction) Configure(_ context.Context, req action.ConfigureRequest, _ *action.ConfigureResponse) {
+	a.client = actionClient(req)
+}
+
+func (a *ModuleRestartAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var data ModuleRestartActionData
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !data.RestartType.IsNull() && !data.RestartType.IsUnknown() &&
+		data.RestartType.ValueString() != "warmStart" && data.RestartType.ValueString() != "coldStart" {
+		resp.Diagnostics.AddError(
+			"ModuleRestartAction: Invalid restart_type",
+			"restart_type must be warmStart or coldStart, got "+data.RestartType.ValueString(),
+		)
+	}
+}
+
+func (a *ModuleRestartAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data ModuleRestartActionData
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	tflog.Debug(ctx, "ModuleRestartAction: Invoke - ", map[string]interface{}{"ModuleRestartActionData": data})
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	restartType := "warmStart"
+	if !data.RestartType.IsNull() {
+		restartType = data.RestartType.ValueString()
+	}
+	invokeResourceAction(ctx, a.client, ResourceAction{
+		Type:              types.StringValue("Module"),
+		Identifier:        common.ResourceIdentifier{DeviceId: data.DeviceId},
+		Action:            types.StringValue(restartType),
+		WaitForCompletion: data.WaitForCompletion,
+		Timeout:           data.Timeout,
+	}, resp)
+}
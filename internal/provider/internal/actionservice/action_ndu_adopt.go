@@ -0,0 +1,75 @@
+package actionservice
+
+import (
+	"context"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	common "terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ action.Action              = &NDUAdoptAction{}
+	_ action.ActionWithConfigure = &NDUAdoptAction{}
+)
+
+// NewNDUAdoptAction is a helper function to simplify the provider implementation.
+func NewNDUAdoptAction() action.Action {
+	return &NDUAdoptAction{}
+}
+
+type NDUAdoptAction struct {
+	client *ipm_pf.Client
+}
+
+type NDUAdoptActionData struct {
+	DeviceId          types.String `tfsdk:"device_id"`
+	WaitForCompletion types.Bool   `tfsdk:"wait_for_completion"`
+	Timeout           types.Int64  `tfsdk:"timeout"`
+}
+
+// Metadata returns the action type name.
+func (a *NDUAdoptAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ndu_adopt"
+}
+
+// Schema defines the schema for the action.
+func (a *NDUAdoptAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Adopts an NDU",
+		Attributes: waitSchemaAttributes(map[string]schema.Attribute{
+			"device_id": schema.StringAttribute{
+				Description: "Device ID of the NDU.",
+				Required:    true,
+			},
+		}),
+	}
+}
+
+// Configure adds the provider configured client to the action.
+func (a *NDUAdoptAction) Configure(_ context.Context, req action.ConfigureRequest, _ *action.ConfigureResponse) {
+	a.client = actionClient(req)
+}
+
+func (a *NDUAdoptAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data NDUAdoptActionData
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	tflog.Debug(ctx, "NDUAdoptAction: Invoke - ", map[string]interface{}{"NDUAdoptActionData": data})
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	invokeResourceAction(ctx, a.client, ResourceAction{
+		Type:              types.StringValue("NDU"),
+		Identifier:        common.ResourceIdentifier{DeviceId: data.DeviceId},
+		Action:            types.StringValue("adopt"),
+		WaitForCompletion: data.WaitForCompletion,
+		Timeout:           data.Timeout,
+	}, resp)
+}
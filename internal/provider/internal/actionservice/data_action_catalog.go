@@ -0,0 +1,113 @@
+package actionservice
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource = &ActionCatalogDataSource{}
+)
+
+// NewActionCatalogDataSource is a helper function to simplify the provider implementation.
+func NewActionCatalogDataSource() datasource.DataSource {
+	return &ActionCatalogDataSource{}
+}
+
+// ActionCatalogDataSource is the data source implementation.
+type ActionCatalogDataSource struct {
+}
+
+type ActionCatalogDataSourceData struct {
+	Type        types.String `tfsdk:"type"`
+	ActionTypes types.List   `tfsdk:"action_types"`
+}
+
+// Metadata returns the data source type name.
+func (d *ActionCatalogDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_action_catalog"
+}
+
+func (d *ActionCatalogDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the resource types and actions supported by ipm_actions",
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				Description: "Resource type. All the resource types are listed when it is not specified.",
+				Optional:    true,
+			},
+			"action_types": schema.ListAttribute{
+				Computed:    true,
+				ElementType: ActionTypeObjectType(),
+			},
+		},
+	}
+}
+
+func (d *ActionCatalogDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	query := ActionCatalogDataSourceData{}
+
+	diags := req.Config.Get(ctx, &query)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	actionTypes := []attr.Value{}
+	for _, actionType := range actionCatalog {
+		if !query.Type.IsNull() && query.Type.ValueString() != actionType.Type {
+			continue
+		}
+		actionTypes = append(actionTypes, types.ObjectValueMust(ActionTypeAttributeType(), ActionTypeAttributeValue(actionType)))
+	}
+	if len(actionTypes) == 0 {
+		resp.Diagnostics.AddError(
+			"ActionCatalogDataSource: read ##: Error read ActionCatalogDataSource",
+			"Get: Invalid resource type "+query.Type.ValueString(),
+		)
+		return
+	}
+
+	query.ActionTypes = types.ListValueMust(ActionTypeObjectType(), actionTypes)
+	diags = resp.State.Set(ctx, query)
+	resp.Diagnostics.Append(diags...)
+	tflog.Debug(ctx, "ActionCatalogDataSource: get ", map[string]interface{}{"ActionTypes": query})
+}
+
+func ActionTypeObjectType() types.ObjectType {
+	return types.ObjectType{
+		AttrTypes: ActionTypeAttributeType(),
+	}
+}
+
+func ActionTypeAttributeType() map[string]attr.Type {
+	return map[string]attr.Type{
+		"type":                 types.StringType,
+		"url_template":         types.StringType,
+		"actions":              types.ListType{ElemType: types.StringType},
+		"required_identifiers": types.ListType{ElemType: types.StringType},
+	}
+}
+
+func ActionTypeAttributeValue(actionType ActionType) map[string]attr.Value {
+	actions := []attr.Value{}
+	for _, v := range actionType.Actions {
+		actions = append(actions, types.StringValue(v))
+	}
+	requiredIdentifiers := []attr.Value{}
+	for _, v := range actionType.RequiredIdentifiers() {
+		requiredIdentifiers = append(requiredIdentifiers, types.StringValue(v))
+	}
+	return map[string]attr.Value{
+		"type":                 types.StringValue(actionType.Type),
+		"url_template":         types.StringValue(actionType.URLTemplate),
+		"actions":              types.ListValueMust(types.StringType, actions),
+		"required_identifiers": types.ListValueMust(types.StringType, requiredIdentifiers),
+	}
+}
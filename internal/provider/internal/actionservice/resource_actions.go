@@ -3,6 +3,8 @@ package actionservice
 import (
 	"context"
 	"errors"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +18,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
@@ -24,6 +27,32 @@ var (
 	_ resource.Resource                = &ActionsResource{}
 	_ resource.ResourceWithConfigure   = &ActionsResource{}
 	_ resource.ResourceWithImportState = &ActionsResource{}
+	_ resource.ResourceWithModifyPlan  = &ActionsResource{}
+	_ resource.ResourceWithValidateConfig = &ActionsResource{}
+)
+
+// disruptiveActions are the actions which interrupt the traffic of the device or wipe its configuration.
+var disruptiveActions = map[string]bool{
+	"factoryReset": true,
+	"coldStart":    true,
+}
+
+// restartActions are the actions which restart the device, the adoption of an NDU also reconnects it. Their completion is
+// only checked once the device has left the state of the completion conditions, which usually still holds when the action
+// is accepted.
+var restartActions = map[string]bool{
+	"factoryReset":     true,
+	"coldStart":        true,
+	"warmStart":        true,
+	"activateSoftware": true,
+	"rollbackSoftware": true,
+}
+
+// default time to wait for the completion of an action, and polling intervals of the target device
+const (
+	defaultActionTimeout = 600
+	actionPollInterval   = 15 * time.Second
+	actionLeaveInterval  = 2 * time.Second
 )
 
 // NewActionsResource is a helper function to simplify the provider implementation.
@@ -39,16 +68,26 @@ type ResourceAction struct {
 	Type           types.String  `tfsdk:"type"`
 	Identifier    common.ResourceIdentifier `tfsdk:"identifier"`
 	Action         types.String  `tfsdk:"action"`
-	Parameter      types.String `tfsdk:"paremeter"`
+	Parameter      types.String `tfsdk:"parameter"`
+	Paremeter      types.String `tfsdk:"paremeter"`
 	RawAction      types.Bool   `tfsdk:"raw_action"`
+	StatusCode     types.Int64  `tfsdk:"status_code"`
 	Response       types.String `tfsdk:"response"`
 	DelayBeforeApply types.Int64   `tfsdk:"delay_before_apply"`
 	StopAtError    types.Bool  `tfsdk:"stop_at_error"`
+	WaitForCompletion types.Bool `tfsdk:"wait_for_completion"`
+	Timeout        types.Int64  `tfsdk:"timeout"`
+	PostConditions types.Map    `tfsdk:"post_conditions"`
+	CompletionState types.String `tfsdk:"completion_state"`
 }
 
 type ActionsResourceData struct {
 	Id             types.String  `tfsdk:"id"`
 	ResourceActions   []ResourceAction  `tfsdk:"resource_actions"`
+	OnDestroy         []ResourceAction  `tfsdk:"on_destroy"`
+	Triggers          types.Map         `tfsdk:"triggers"`
+	RunOnce           types.Bool        `tfsdk:"run_once"`
+	ConfirmDisruptive types.Bool        `tfsdk:"confirm_disruptive"`
 }
 
 // Metadata returns the data source type name.
@@ -71,7 +110,7 @@ func (r *ActionsResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r ActionsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -86,8 +125,11 @@ func (r ActionsResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	r.apply(&data, ctx, &resp.Diagnostics)
-	resp.State.Set(ctx, &data)
-
+	if data.Id.IsNull() {
+		return
+	}
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
 }
 
 func (r ActionsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -109,6 +151,7 @@ func (r ActionsResource) Read(ctx context.Context, req resource.ReadRequest, res
 
 func (r ActionsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var data ActionsResourceData
+	var stateData ActionsResourceData
 
 	diags := req.Plan.Get(ctx, &data)
 	tflog.Debug(ctx, "ActionsResource: Update", map[string]interface{}{"ActionsResourceData": data})
@@ -119,10 +162,23 @@ func (r ActionsResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	r.apply(&data, ctx, &resp.Diagnostics)
+	diags = req.State.Get(ctx, &stateData)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	data.Id = stateData.Id
+	clearActionResults(data.OnDestroy)
+
+	if data.rerunRequired(&stateData) {
+		r.apply(&data, ctx, &resp.Diagnostics)
+	} else {
+		tflog.Debug(ctx, "ActionsResource: Update - the actions and triggers are unchanged, the actions are not executed again")
+		copyActionResults(data.ResourceActions, stateData.ResourceActions)
+	}
+	if data.Id.IsNull() {
+		return
+	}
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 }
@@ -140,36 +196,287 @@ func (r ActionsResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	if len(data.OnDestroy) > 0 {
+		applyActions(ctx, r.client, data.OnDestroy, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	resp.State.RemoveResource(ctx)
 }
 
+// ValidateConfig checks the actions against the action catalog.
+func (r ActionsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	// the lists are read one by one, the actions may be unknown until other resources are applied
+	var resourceActions types.List
+	var onDestroy types.List
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("resource_actions"), &resourceActions)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("on_destroy"), &onDestroy)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	validateActions(ctx, resourceActions, "resource_actions", &resp.Diagnostics)
+	validateActions(ctx, onDestroy, "on_destroy", &resp.Diagnostics)
+}
+
+// ModifyPlan warns about disruptive actions which are about to be executed, and keeps the results of the
+// previous execution when an update does not execute the actions again.
+func (r ActionsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan ActionsResourceData
+	var state ActionsResourceData
+
+	if req.Plan.Raw.IsNull() {
+		// destroy plan, only on_destroy actions are executed
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		warnDisruptiveActions(state.OnDestroy, state.ConfirmDisruptive, "on_destroy", &resp.Diagnostics)
+		return
+	}
+
+	// the actions may be unknown until other resources are applied, their results are then unknown too
+	var resourceActions types.List
+	var onDestroy types.List
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("resource_actions"), &resourceActions)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("on_destroy"), &onDestroy)...)
+	if resp.Diagnostics.HasError() || resourceActions.IsUnknown() || onDestroy.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	clearActionResults(plan.OnDestroy)
+
+	if req.State.Raw.IsNull() {
+		warnDisruptiveActions(plan.ResourceActions, plan.ConfirmDisruptive, "resource_actions", &resp.Diagnostics)
+	} else {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		plan.Id = state.Id
+		if plan.rerunRequired(&state) {
+			warnDisruptiveActions(plan.ResourceActions, plan.ConfirmDisruptive, "resource_actions", &resp.Diagnostics)
+		} else {
+			copyActionResults(plan.ResourceActions, state.ResourceActions)
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// rerunRequired tells whether an update executes the actions again.
+// run_once actions are never executed again. Otherwise the actions are executed again when a trigger value changes,
+// or when an action is added, removed or changed. The execution settings of the actions (delay, timeout, completion
+// and post conditions, stop_at_error), on_destroy and confirm_disruptive do not execute them again.
+func (plan *ActionsResourceData) rerunRequired(state *ActionsResourceData) bool {
+	if !plan.RunOnce.IsNull() && plan.RunOnce.ValueBool() {
+		return false
+	}
+	if !plan.Triggers.Equal(state.Triggers) {
+		return true
+	}
+	return actionsChanged(plan.ResourceActions, state.ResourceActions)
+}
+
+// actionsChanged tells whether an action is added, removed, or executes another action or on another object.
+func actionsChanged(planned []ResourceAction, previous []ResourceAction) bool {
+	if len(planned) != len(previous) {
+		return true
+	}
+	for i := range planned {
+		p, s := planned[i], previous[i]
+		if !p.Type.Equal(s.Type) || !p.Action.Equal(s.Action) || !p.Parameter.Equal(s.Parameter) || !p.Paremeter.Equal(s.Paremeter) ||
+			!p.RawAction.Equal(s.RawAction) || !reflect.DeepEqual(p.Identifier, s.Identifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// warnDisruptiveActions adds a warning for every disruptive action unless confirm_disruptive is set.
+func warnDisruptiveActions(resourceActions []ResourceAction, confirmDisruptive types.Bool, attribute string, diags *diag.Diagnostics) {
+	if !confirmDisruptive.IsNull() && confirmDisruptive.ValueBool() {
+		return
+	}
+	for i, resourceAction := range resourceActions {
+		action := resourceAction.Action.ValueString()
+		if !resourceAction.RawAction.IsNull() && resourceAction.RawAction.ValueBool() {
+			action = action[strings.LastIndex(action, "/")+1:]
+		}
+		if disruptiveActions[action] {
+			diags.AddAttributeWarning(
+				path.Root(attribute).AtListIndex(i).AtName("action"),
+				"ActionsResource: Disruptive action "+action,
+				"The action "+action+" interrupts the traffic of "+resourceAction.Identifier.DeviceId.ValueString()+
+					". Set confirm_disruptive = true to acknowledge it.",
+			)
+		}
+	}
+}
+
+// clearActionResults sets the results of actions which are not executed by the current operation.
+func clearActionResults(resourceActions []ResourceAction) {
+	for i := range resourceActions {
+		resourceActions[i].StatusCode = types.Int64Null()
+		resourceActions[i].Response = types.StringNull()
+		resourceActions[i].CompletionState = types.StringNull()
+	}
+}
+
+// copyActionResults keeps the results of the previous execution for the actions at the same position.
+func copyActionResults(resourceActions []ResourceAction, previousActions []ResourceAction) {
+	clearActionResults(resourceActions)
+	for i := range resourceActions {
+		if i < len(previousActions) {
+			resourceActions[i].StatusCode = previousActions[i].StatusCode
+			resourceActions[i].Response = previousActions[i].Response
+			resourceActions[i].CompletionState = previousActions[i].CompletionState
+		}
+	}
+}
+
 func (r *ActionsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// Retrieve Action ID and save to id attribute
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
-func applyActions(ctx context.Context, client *ipm_pf.Client, resourceActions []ResourceAction) (err error) {
-		for _, resourecAction := range resourceActions {
-			command, err := getActionCommand(resourecAction)
-			tflog.Debug(ctx, "applyActions ###########: ", map[string]interface{}{"command": command})
-			if err != nil {
-				_, err := client.ExecuteIPMHttpCommand("POST", command, nil)
-				if err == nil {
-					resourecAction.Response = types.StringValue("Failed. " + err.Error())
-					if  !resourecAction.StopAtError.IsNull() && resourecAction.StopAtError.ValueBool() == true {
-						break
-					}
-				} else {
-					resourecAction.Response = types.StringValue("Send.")
-				}
-			} 
-			if  !resourecAction.DelayBeforeApply.IsNull() {
-				time.Sleep(time.Duration(resourecAction.DelayBeforeApply.ValueInt64()) * time.Second)
+// applyActions executes the actions in order and records the HTTP status and response of each one of them.
+// The actions which are not executed because of an earlier failure with stop_at_error get a null status and response.
+func applyActions(ctx context.Context, client *ipm_pf.Client, resourceActions []ResourceAction, diags *diag.Diagnostics) {
+	stopped := false
+	for i := range resourceActions {
+		resourceAction := &resourceActions[i]
+		resourceAction.StatusCode = types.Int64Null()
+		resourceAction.Response = types.StringNull()
+		resourceAction.CompletionState = types.StringNull()
+		if stopped {
+			continue
+		}
+		command, err := getActionCommand(*resourceAction)
+		tflog.Debug(ctx, "applyActions ###########: ", map[string]interface{}{"command": command})
+		if err != nil {
+			resourceAction.Response = types.StringValue("Failed. " + err.Error())
+			stopped = reportActionError(resourceAction, "Could not build the action command: "+err.Error(), diags)
+			continue
+		}
+		// a restarted device has to leave the completion state first, if it is in that state before the action
+		restart := false
+		if resourceAction.waitRequired() && resourceAction.restartsDevice() {
+			target, conditions := completionConditions(ctx, *resourceAction, command)
+			if data, err := common.GetResource(client, target); err == nil {
+				restart = common.CheckConditions(data, conditions) == nil
 			}
 		}
-		return nil
+		if !resourceAction.DelayBeforeApply.IsNull() {
+			time.Sleep(time.Duration(resourceAction.DelayBeforeApply.ValueInt64()) * time.Second)
+		}
+		var body []byte
+		if parameter := actionParameter(*resourceAction); parameter != "" {
+			body = []byte(parameter)
+		}
+		status, response, err := client.ExecuteIPMHttpCommandWithStatus("POST", command, body)
+		if status != 0 {
+			resourceAction.StatusCode = types.Int64Value(int64(status))
+		}
+		if err != nil {
+			resourceAction.Response = types.StringValue("Failed. " + err.Error())
+			stopped = reportActionError(resourceAction, "POST "+command+" failed, unexpected error: "+err.Error(), diags)
+			continue
+		}
+		resourceAction.Response = types.StringValue(string(response))
+		tflog.Debug(ctx, "applyActions ###########: ", map[string]interface{}{"command": command, "status": status, "response": string(response)})
+		if resourceAction.waitRequired() {
+			stopped = waitForCompletion(ctx, client, resourceAction, command, restart, diags)
+		}
+	}
+}
+
+func (resourceAction *ResourceAction) restartsDevice() bool {
+	return restartActions[resourceAction.Action.ValueString()] ||
+		(resourceAction.Action.ValueString() == "adopt" && resourceAction.Type.ValueString() == "NDU")
+}
+
+func (resourceAction *ResourceAction) waitRequired() bool {
+	return (!resourceAction.WaitForCompletion.IsNull() && resourceAction.WaitForCompletion.ValueBool()) ||
+		(!resourceAction.PostConditions.IsNull() && len(resourceAction.PostConditions.Elements()) > 0)
+}
+
+// waitForCompletion polls the target of the action until its post conditions are met, and records the outcome in completion_state.
+// When the action restarts the device, the target has to leave the state of the conditions first.
+// It returns true when the remaining actions must not be executed.
+func waitForCompletion(ctx context.Context, client *ipm_pf.Client, resourceAction *ResourceAction, command string, restart bool, diags *diag.Diagnostics) bool {
+	target, conditions := completionConditions(ctx, *resourceAction, command)
+	timeout := int64(defaultActionTimeout)
+	if !resourceAction.Timeout.IsNull() {
+		timeout = resourceAction.Timeout.ValueInt64()
+	}
+
+	tflog.Debug(ctx, "applyActions: wait for completion ", map[string]interface{}{"target": target, "conditions": conditions, "timeout": timeout, "restart": restart})
+	var err error
+	if restart {
+		_, err = common.WaitForResourceStateChange(ctx, client, target, conditions, time.Duration(timeout)*time.Second, actionPollInterval, actionLeaveInterval)
+	} else {
+		_, err = common.WaitForResourceState(ctx, client, target, conditions, time.Duration(timeout)*time.Second, actionPollInterval)
+	}
+	if err != nil {
+		resourceAction.CompletionState = types.StringValue("Failed. " + err.Error())
+		return reportActionError(resourceAction, "The action did not complete: "+err.Error(), diags)
+	}
+	resourceAction.CompletionState = types.StringValue("completed")
+	return false
 }
 
+// completionConditions returns the query of the object to poll after the action and the post conditions, the default
+// completion conditions unless post_conditions are set.
+func completionConditions(ctx context.Context, resourceAction ResourceAction, command string) (string, map[string]string) {
+	target, conditions := actionCompletionTarget(resourceAction, command)
+	if !resourceAction.PostConditions.IsNull() {
+		conditions = map[string]string{}
+		resourceAction.PostConditions.ElementsAs(ctx, &conditions, false)
+	}
+	return target, conditions
+}
+
+// actionCompletionTarget returns the query of the object to poll after the action, and its default completion conditions.
+// The identifier href selects the object, which has to return to the configured state. Otherwise the device of the action
+// (module or NDU) is polled until it is connected again.
+func actionCompletionTarget(resourceAction ResourceAction, command string) (string, map[string]string) {
+	if !resourceAction.Identifier.Href.IsNull() && resourceAction.Identifier.Href.ValueString() != "" {
+		return resourceAction.Identifier.Href.ValueString(), map[string]string{"state.lifecycleState": "configured"}
+	}
+	items := strings.Split(strings.TrimPrefix(command, "/"), "/")
+	target := command
+	if len(items) >= 2 {
+		target = "/" + items[0] + "/" + items[1]
+	}
+	return target, map[string]string{"state.connectivityState": "connected"}
+}
+
+// reportActionError adds the failure of an action as diagnostic: an error when stop_at_error is set, otherwise a warning.
+// It returns true when the remaining actions must not be executed.
+func reportActionError(resourceAction *ResourceAction, detail string, diags *diag.Diagnostics) bool {
+	summary := "ActionsResource: apply ##: Action " + resourceAction.Action.ValueString() + " failed"
+	if !resourceAction.StopAtError.IsNull() && resourceAction.StopAtError.ValueBool() {
+		diags.AddError(summary, detail)
+		return true
+	}
+	diags.AddWarning(summary, detail)
+	return false
+}
+
+// actionParameter returns the request body of the action. parameter takes precedence over the deprecated paremeter.
+func actionParameter(resourceAction ResourceAction) string {
+	if !resourceAction.Parameter.IsNull() {
+		return resourceAction.Parameter.ValueString()
+	}
+	return resourceAction.Paremeter.ValueString()
+}
 
 func (r *ActionsResource) apply(plan *ActionsResourceData, ctx context.Context, diags *diag.Diagnostics) {
 
@@ -183,12 +490,13 @@ func (r *ActionsResource) apply(plan *ActionsResourceData, ctx context.Context,
 		return
 	}
 
-	applyActions(ctx,  r.client, plan.ResourceActions)
-
-	if plan.Id.IsNull() {
+	if plan.Id.IsNull() || plan.Id.IsUnknown() {
 		plan.Id = types.StringValue((uuid.New()).String())
 	}
 
+	clearActionResults(plan.OnDestroy)
+	applyActions(ctx, r.client, plan.ResourceActions, diags)
+
 	tflog.Debug(ctx, "ActionsResource: create ##", map[string]interface{}{"plan": plan})
 }
 
@@ -209,77 +517,58 @@ func (r *ActionsResource) read(state *ActionsResourceData, ctx context.Context,
 func getActionCommand(resourceAction ResourceAction) (string, error) {
 	if  resourceAction.RawAction.IsNull() || !resourceAction.RawAction.ValueBool() {
 		resourceType := resourceAction.Type.ValueString()
-		deviceId := resourceAction.Identifier.DeviceId.ValueString()
-		action := resourceAction.Action.ValueString()
-		if len(action) == 0 {
-			return "", errors.New("Action is not specified.")
+		actionType, found := FindActionType(resourceType)
+		if !found {
+			return "", errors.New("Invalid Resource Type: " + resourceType)
 		}
-		switch resourceType {
-			case "NDU": 
-				// '/ndus/{deviceId}/coldStart|warmStart|factoryReset|retry|adopt'
-					return "/ndus/" + deviceId + "/" + action, nil;
-			case "NDU Port": 
-				// '/ndus/{nduId}/ports/{nduPortColId}/retry|adopt'
-				return "/ndus/" + deviceId  + "/ports/" + resourceAction.Identifier.ParentColId.ValueString() + "/" + action, nil;
-			case "NDU TOM": 
-				// '/ndus/{nduId}/ports/{nduPortColId}/tom/{nduTomColId}/retry|adopt'
-				return "/ndus/" + deviceId  + "/ports/" + resourceAction.Identifier.ParentColId.ValueString() + "/tom/" + resourceAction.Identifier.ColId.ValueString() + "/" + action, nil;
-			case "NDU XR": 
-				// '/ndus/{nduId}/ports/{nduPortColId}/xr/{nduXrColId}/coldStart|warmStart|factoryReset|retry|adopt'
-				return "/ndus/" + deviceId  + "/ports/" + resourceAction.Identifier.ParentColId.ValueString() + "/xr/" + resourceAction.Identifier.ColId.ValueString() + "/" + action, nil;
-			case "NDU EDFA": 
-				// '/ndus/{nduId}/ports/{nduPortColId}/edfa/{nduEdfaColId}/retry|adopt'
-				return "/ndus/" + deviceId  + "/ports/" + resourceAction.Identifier.ParentColId.ValueString() + "/edfa/" + resourceAction.Identifier.ColId.ValueString() + "/" + action, nil;
-			case "NDU VOA": 
-				// '/ndus/{nduId}/ports/{nduPortColId}/voa/{nduVoaColId}/retry|adopt'
-				return "/ndus/" + deviceId  + "/ports/" + resourceAction.Identifier.ParentColId.ValueString() + "/voa/" + resourceAction.Identifier.ColId.ValueString() + "/" + action, nil;
-			case "NDU Line PTP": 
-				// '/ndus/{nduId}/ports/{nduPortColId}/linePtps/{nduLinePtpColId}/retry|adopt'
-				return "/ndus/" + deviceId  + "/ports/" + resourceAction.Identifier.ParentColId.ValueString() + "/linePtps/" + resourceAction.Identifier.ColId.ValueString() + "/" + action, nil;
-			case "NDU Trib PTP": 
-				// '/ndus/{nduId}/ports/{nduPortColId}/tripPtps/{nduTribPtpColId}/retry|adopt'
-				return "/ndus/" + deviceId  + "/ports/" + resourceAction.Identifier.ParentColId.ValueString() + "/tribPtps/" + resourceAction.Identifier.ColId.ValueString() + "/" + action, nil;
-			case "NDU POL PTP": 
-				// '/ndus/{nduId}/ports/{nduPortColId}/tom/{nduPolPtpColId}/retry|adopt'
-				return "/ndus/" + deviceId  + "/ports/" + resourceAction.Identifier.ParentColId.ValueString() + "/polPtps/" + resourceAction.Identifier.ColId.ValueString() + "/" + action, nil;
-			case "NDU Carrier": 
-				// '/ndus/{nduId}/ports/{nduPortColId}/linePtps/{nduLinePtpColId}/carrier/{carrierColId}/retry|adopt
-				return "/ndus/" + deviceId  + "/ports/" + resourceAction.Identifier.GrandParentColId.ValueString() + "/linePtps/" + resourceAction.Identifier.ParentColId.ValueString() + "/carrier/" + resourceAction.Identifier.ColId.ValueString() + "/" + action, nil;
-			case "NDU OTU" :
-				// '/ndus/{deviceId}/otus/{otuColId}/retry'
-				return "/ndus/" + deviceId  + "/otus/" + resourceAction.Identifier.ColId.ValueString() + "/" + action, nil;
-			case "NDU Ethernet Client":
-				// '/modules/{deviceId}/ethernetClients/{ethernetColId}/clrLldpStats|flushLldpHostDb|retry';
-				return "/ndus/" + deviceId  + "/ethernets/" + resourceAction.Identifier.ColId.ValueString() + "/" + action, nil;
-			case "Module": 
-				// '/modules/{deviceId}/coldStart|warmStart|factoryReset|retry'
-					return "/modules/" + deviceId + "/" + action, nil;
-			case "Ethernet Client":
-				// '/modules/{deviceId}/ethernetClients/{ethernetColId}/clrLldpStats|flushLldpHostDb|retry';
-				return "/modules/" + deviceId  + "/ethernetClients/" + resourceAction.Identifier.ColId.ValueString() + "/" + action, nil;
-			case "OTU" :
-				// '/modules/{deviceId}/otus/{otuColId}/retry'
-				return "/modules/" + deviceId  + "/otus/" + resourceAction.Identifier.ColId.ValueString() + "/" + action, nil;
-			case "ODU" :
-				// '/modules/{deviceId}/otus/{otuColId}/odus/{oduColId}/retry'
-				return "/modules/" + deviceId  + "/otus/" + resourceAction.Identifier.ParentColId.ValueString() + "/odus/" + resourceAction.Identifier.ColId.ValueString() + "/" + action, nil;
-			case "Carrier" :
-				// '/modules/{deviceId}/linePtps/{linePtpColId}/carriers/{carrierColId}/retry'
-				return "/modules/" + deviceId  + "/linePtps/" + resourceAction.Identifier.ParentColId.ValueString() + "/carriers/" + resourceAction.Identifier.ColId.ValueString() + "/" + action, nil;
-			case "DSC":
-				// '/modules/{deviceId}/linePtps/{linePtpColId}/carriers/{carrierColId}/dscs/{dscColId}/retry'
-				return "/modules/" + deviceId  + "/linePtps/" + resourceAction.Identifier.GrandParentColId.ValueString() + "/carriers/" + resourceAction.Identifier.ParentColId.ValueString() + "/dscs/" + resourceAction.Identifier.ColId.ValueString() + "/" + action, nil;
-			case "DSCG":
-				// '/modules/{deviceId}/linePtps/{linePtpColId}/carriers/{carrierColId}/dsgs/{dsgColId}/retry'
-				return "/modules/" + deviceId  + "/linePtps/" + resourceAction.Identifier.GrandParentColId.ValueString() + "/carriers/" + resourceAction.Identifier.ParentColId.ValueString() + "/dsgs/" + resourceAction.Identifier.ColId.ValueString() + "/" + action, nil;
-	        default:
-				return "", errors.New("Invalid Resource Type: " + resourceType)
+		if _, err := actionType.Validate(resourceAction.Action, resourceAction.Identifier); err != nil {
+			return "", err
 		}
+		return actionType.URL(resourceAction.Action.ValueString(), resourceAction.Identifier), nil
 	} else {
 		return resourceAction.Action.ValueString(), nil
 	}
 }
 
+// validateActions checks the type, the action and the identifier of the actions which are not raw actions.
+// The actions or identifiers which are not known yet are checked when they are.
+func validateActions(ctx context.Context, resourceActions types.List, attribute string, diags *diag.Diagnostics) {
+	if resourceActions.IsNull() || resourceActions.IsUnknown() {
+		return
+	}
+	for i, element := range resourceActions.Elements() {
+		object, ok := element.(types.Object)
+		if !ok || object.IsUnknown() || object.Attributes()["identifier"].IsUnknown() {
+			continue
+		}
+		var resourceAction ResourceAction
+		diags.Append(object.As(ctx, &resourceAction, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return
+		}
+		if resourceAction.RawAction.IsUnknown() || (!resourceAction.RawAction.IsNull() && resourceAction.RawAction.ValueBool()) || resourceAction.Type.IsUnknown() {
+			continue
+		}
+		actionPath := path.Root(attribute).AtListIndex(i)
+		actionType, found := FindActionType(resourceAction.Type.ValueString())
+		if !found {
+			resourceTypes := []string{}
+			for _, v := range actionCatalog {
+				resourceTypes = append(resourceTypes, v.Type)
+			}
+			diags.AddAttributeError(
+				actionPath.AtName("type"),
+				"ActionsResource: Invalid resource type",
+				"Invalid resource type \""+resourceAction.Type.ValueString()+"\". Valid types: "+strings.Join(resourceTypes, ", "),
+			)
+			continue
+		}
+		if name, err := actionType.Validate(resourceAction.Action, resourceAction.Identifier); err != nil {
+			diags.AddAttributeError(actionPath.AtName(name), "ActionsResource: Invalid action", err.Error())
+		}
+	}
+}
+
 func actionsResourceSchemaAttributes() map[string]schema.Attribute { 
 	return map[string]schema.Attribute{
 		"id": schema.StringAttribute{
@@ -287,11 +576,37 @@ func actionsResourceSchemaAttributes() map[string]schema.Attribute {
 			Computed:    true,
 		},
 		"resource_actions":schema.ListNestedAttribute{
+			Description:  "Actions executed on create, and on update when an action is added, removed or changed or when a trigger changes. " +
+				"Changing only the delay, timeout, completion or post condition settings, stop_at_error, on_destroy or confirm_disruptive does not execute the actions again.",
+			Optional:     true,
+			NestedObject: resourceActionSchemaObject(),
+		},
+		"on_destroy":schema.ListNestedAttribute{
+			Description:  "Actions executed when the resource is destroyed.",
 			Optional:     true,
-			NestedObject: schema.NestedAttributeObject{
+			NestedObject: resourceActionSchemaObject(),
+		},
+		"triggers": schema.MapAttribute{
+			Description: "Values which execute the actions again when any of them changes.",
+			Optional:    true,
+			ElementType: types.StringType,
+		},
+		"run_once": schema.BoolAttribute{
+			Description: "Execute the actions only when the resource is created.",
+			Optional:    true,
+		},
+		"confirm_disruptive": schema.BoolAttribute{
+			Description: "Acknowledge disruptive actions (factoryReset, coldStart) and suppress their plan warning.",
+			Optional:    true,
+		},
+	}
+}
+
+func resourceActionSchemaObject() schema.NestedAttributeObject {
+	return schema.NestedAttributeObject{
 				Attributes: map[string]schema.Attribute{
 					"type": schema.StringAttribute{
-						Description: "type",
+						Description: "Resource type of the action, see the ipm_action_catalog data source.",
 						Optional:     true,
 					},
 					"identifier": common.ResourceIdentifierAttribute(),
@@ -299,12 +614,21 @@ func actionsResourceSchemaAttributes() map[string]schema.Attribute {
 						Description: "Action",
 						Optional:     true,
 					},
+					"parameter": schema.StringAttribute{
+						Description: "Request body sent with the action.",
+						Optional:     true,
+					},
 					"paremeter": schema.StringAttribute{
 						Description: "paremeter",
 						Optional:     true,
+						DeprecationMessage: "Use parameter instead.",
+					},
+					"status_code": schema.Int64Attribute{
+						Description: "HTTP status code returned for the action.",
+						Computed:     true,
 					},
 					"response": schema.StringAttribute{
-						Description: "Response",
+						Description: "Response body returned for the action, or the failure cause.",
 						Computed:     true,
 					},
 					"raw_action": schema.BoolAttribute{
@@ -319,8 +643,24 @@ func actionsResourceSchemaAttributes() map[string]schema.Attribute {
 						Description: "stop apply at first error.",
 						Optional:    true,
 					},
+					"wait_for_completion": schema.BoolAttribute{
+						Description: "Wait until the target returns to service: the object of identifier href is configured, otherwise the module or NDU of the action is connected. " +
+							"After factoryReset, coldStart, warmStart, activateSoftware, rollbackSoftware and the adoption of an NDU, the target has to leave that state first when it was in it before the action.",
+						Optional:    true,
+					},
+					"timeout": schema.Int64Attribute{
+						Description: "Time in seconds to wait for the completion of the action. Default is 600.",
+						Optional:    true,
+					},
+					"post_conditions": schema.MapAttribute{
+						Description: "Expected values of the target after the action, by dotted path, e.g. state.connectivityState = connected. Implies wait_for_completion.",
+						Optional:    true,
+						ElementType: types.StringType,
+					},
+					"completion_state": schema.StringAttribute{
+						Description: "completed, or the cause why the action did not complete.",
+						Computed:    true,
+					},
 				},
-			},
-		},
-	}
+			}
 }
\ No newline at end of file
@@ -0,0 +1,81 @@
+package actionservice
+
+import (
+	"testing"
+
+	common "terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testTriggers(value string) types.Map {
+	return types.MapValueMust(types.StringType, map[string]attr.Value{"version": types.StringValue(value)})
+}
+
+func testAction(action string, deviceId string) ResourceAction {
+	return ResourceAction{
+		Type:             types.StringValue("Module"),
+		Identifier:       common.ResourceIdentifier{DeviceId: types.StringValue(deviceId)},
+		Action:           types.StringValue(action),
+		RawAction:        types.BoolNull(),
+		DelayBeforeApply: types.Int64Value(60),
+	}
+}
+
+func TestRerunRequired(t *testing.T) {
+	state := ActionsResourceData{
+		ResourceActions: []ResourceAction{testAction("warmStart", "m1")},
+		Triggers:        testTriggers("1"),
+		RunOnce:         types.BoolNull(),
+	}
+	delayChanged := testAction("warmStart", "m1")
+	delayChanged.DelayBeforeApply = types.Int64Value(600)
+	tests := []struct {
+		name  string
+		plan  ActionsResourceData
+		rerun bool
+	}{
+		{"unchanged", ActionsResourceData{
+			ResourceActions: []ResourceAction{testAction("warmStart", "m1")},
+			Triggers:        testTriggers("1"),
+			RunOnce:         types.BoolNull(),
+		}, false},
+		{"execution settings changed", ActionsResourceData{
+			ResourceActions:   []ResourceAction{delayChanged},
+			Triggers:          testTriggers("1"),
+			RunOnce:           types.BoolNull(),
+			ConfirmDisruptive: types.BoolValue(true),
+		}, false},
+		{"trigger changed", ActionsResourceData{
+			ResourceActions: []ResourceAction{testAction("warmStart", "m1")},
+			Triggers:        testTriggers("2"),
+			RunOnce:         types.BoolNull(),
+		}, true},
+		{"action changed", ActionsResourceData{
+			ResourceActions: []ResourceAction{testAction("coldStart", "m1")},
+			Triggers:        testTriggers("1"),
+			RunOnce:         types.BoolNull(),
+		}, true},
+		{"identifier changed", ActionsResourceData{
+			ResourceActions: []ResourceAction{testAction("warmStart", "m2")},
+			Triggers:        testTriggers("1"),
+			RunOnce:         types.BoolNull(),
+		}, true},
+		{"action added", ActionsResourceData{
+			ResourceActions: []ResourceAction{testAction("warmStart", "m1"), testAction("warmStart", "m2")},
+			Triggers:        testTriggers("1"),
+			RunOnce:         types.BoolNull(),
+		}, true},
+		{"run once", ActionsResourceData{
+			ResourceActions: []ResourceAction{testAction("coldStart", "m2")},
+			Triggers:        testTriggers("2"),
+			RunOnce:         types.BoolValue(true),
+		}, false},
+	}
+	for _, test := range tests {
+		if rerun := test.plan.rerunRequired(&state); rerun != test.rerun {
+			t.Errorf("%s: rerunRequired() = %v, want %v", test.name, rerun, test.rerun)
+		}
+	}
+}
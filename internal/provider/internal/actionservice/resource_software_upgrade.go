@@ -0,0 +1,608 @@
+package actionservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	common "terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &SoftwareUpgradeResource{}
+	_ resource.ResourceWithConfigure      = &SoftwareUpgradeResource{}
+	_ resource.ResourceWithValidateConfig = &SoftwareUpgradeResource{}
+	_ resource.ResourceWithModifyPlan     = &SoftwareUpgradeResource{}
+)
+
+// default time to wait for each upgrade step of a device
+const defaultSoftwareUpgradeTimeout = 1800
+
+// the software version a device runs, and the upgrade step it completed last
+const (
+	softwareVersionPath      = "state.hwDescription.sv"
+	softwareUpgradeStatePath = "state.softwareUpgradeState"
+)
+
+// NewSoftwareUpgradeResource is a helper function to simplify the provider implementation.
+func NewSoftwareUpgradeResource() resource.Resource {
+	return &SoftwareUpgradeResource{}
+}
+
+// SoftwareUpgradeResource upgrades the software of modules or NDUs, batch by batch.
+type SoftwareUpgradeResource struct {
+	client *ipm_pf.Client
+}
+
+type SoftwareUpgradeDevice struct {
+	Name            types.String `tfsdk:"name"`
+	PreviousVersion types.String `tfsdk:"previous_version"`
+	Version         types.String `tfsdk:"version"`
+	Status          types.String `tfsdk:"status"`
+	Message         types.String `tfsdk:"message"`
+}
+
+type SoftwareUpgradeResourceData struct {
+	Id                types.String                     `tfsdk:"id"`
+	DeviceType        types.String                     `tfsdk:"device_type"`
+	Devices           []common.DeviceIdentifier        `tfsdk:"devices"`
+	Labels            types.Map                        `tfsdk:"labels"`
+	Version           types.String                     `tfsdk:"version"`
+	BatchSize         types.Int64                      `tfsdk:"batch_size"`
+	Timeout           types.Int64                      `tfsdk:"timeout"`
+	HealthCheck       types.Map                        `tfsdk:"health_check"`
+	RollbackOnFailure types.Bool                       `tfsdk:"rollback_on_failure"`
+	DeviceStatus      map[string]SoftwareUpgradeDevice `tfsdk:"device_status"`
+}
+
+// upgradeDevice is a device selected for the upgrade.
+type upgradeDevice struct {
+	id      string
+	name    string
+	version string
+}
+
+// Metadata returns the data source type name.
+func (r *SoftwareUpgradeResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_software_upgrade"
+}
+
+// Schema defines the schema for the data source.
+func (r *SoftwareUpgradeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Upgrades the software of modules or NDUs, batch by batch. The devices of a batch download the version, activate it, pass the health check " +
+			"and commit it. A device which fails the health check is rolled back, and the remaining batches are not upgraded after a failure. " +
+			"Each step completes when the device reports it in state.softwareUpgradeState. An update is planned when a device of the previous upgrade no longer runs the version. " +
+			"The devices which were rolled back or failed are upgraded again only when the version changes or the resource is replaced. " +
+			"Destroying the resource leaves the software as it is.",
+		Attributes: SoftwareUpgradeSchemaAttributes(),
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *SoftwareUpgradeResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+// ValidateConfig checks the device type and that devices are selected.
+func (r *SoftwareUpgradeResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	// the attributes are read one by one, the devices may be unknown until other resources are applied
+	var deviceType types.String
+	var devices types.List
+	var labels types.Map
+	var batchSize types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("device_type"), &deviceType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("devices"), &devices)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("labels"), &labels)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("batch_size"), &batchSize)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !deviceType.IsUnknown() && deviceType.ValueString() != "module" && deviceType.ValueString() != "ndu" {
+		resp.Diagnostics.AddAttributeError(path.Root("device_type"), "SoftwareUpgradeResource: Invalid device_type", "device_type must be module or ndu.")
+	}
+	if devices.IsNull() && labels.IsNull() {
+		resp.Diagnostics.AddError("SoftwareUpgradeResource: No device selected", "At least one of devices and labels must be specified.")
+	}
+	if !batchSize.IsNull() && !batchSize.IsUnknown() && batchSize.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("batch_size"), "SoftwareUpgradeResource: Invalid batch_size", "batch_size must be at least 1.")
+	}
+}
+
+// ModifyPlan plans an update when a device of the previous upgrade no longer runs the version. The devices which were rolled
+// back or failed do not plan an update until the version changes.
+func (r *SoftwareUpgradeResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+	var version types.String
+	var state SoftwareUpgradeResourceData
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("version"), &version)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || version.IsUnknown() {
+		return
+	}
+	if id, found := outdatedDevice(&state, version.ValueString()); found {
+		tflog.Debug(ctx, "SoftwareUpgradeResource: ModifyPlan ## device not at the version", map[string]interface{}{"id": id, "version": state.DeviceStatus[id].Version.ValueString()})
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("device_status"), types.MapUnknown(types.ObjectType{AttrTypes: softwareUpgradeDeviceAttributeTypes()}))...)
+	}
+}
+
+// outdatedDevice returns a device of the state which does not run the version. The devices which were rolled back or
+// failed are only returned when the version differs from the version of their upgrade.
+func outdatedDevice(state *SoftwareUpgradeResourceData, version string) (string, bool) {
+	for _, id := range sortedDeviceIds(state.DeviceStatus) {
+		status := state.DeviceStatus[id]
+		if status.Version.ValueString() == version {
+			continue
+		}
+		if version == state.Version.ValueString() && retiredUpgrade(status) {
+			continue
+		}
+		return id, true
+	}
+	return "", false
+}
+
+// retiredUpgrade tells whether the upgrade of the device was rolled back or failed.
+func retiredUpgrade(status SoftwareUpgradeDevice) bool {
+	return status.Status.ValueString() == "rolled_back" || status.Status.ValueString() == "failed"
+}
+
+func sortedDeviceIds(deviceStatus map[string]SoftwareUpgradeDevice) []string {
+	ids := make([]string, 0, len(deviceStatus))
+	for id := range deviceStatus {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (r *SoftwareUpgradeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SoftwareUpgradeResourceData
+
+	diags := req.Config.Get(ctx, &data)
+
+	tflog.Debug(ctx, "SoftwareUpgradeResource: Create - ", map[string]interface{}{"SoftwareUpgradeResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(uuid.New().String())
+	r.upgrade(&data, nil, ctx, &resp.Diagnostics)
+
+	// the progress of the devices is kept in the state, also when the upgrade failed
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *SoftwareUpgradeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SoftwareUpgradeResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.read(&data, ctx, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *SoftwareUpgradeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan SoftwareUpgradeResourceData
+	var state SoftwareUpgradeResourceData
+
+	// the planned device status is unknown, the settings are read from the config
+	diags := req.Config.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Debug(ctx, "SoftwareUpgradeResource: Update", map[string]interface{}{"plan": plan})
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the devices which are already at the version are not upgraded again, nor the devices which were rolled back or
+	// failed while the version is the same
+	plan.Id = state.Id
+	var previous map[string]SoftwareUpgradeDevice
+	if plan.Version.Equal(state.Version) {
+		previous = state.DeviceStatus
+	}
+	r.upgrade(&plan, previous, ctx, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *SoftwareUpgradeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data SoftwareUpgradeResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "SoftwareUpgradeResource: Delete", map[string]interface{}{"SoftwareUpgradeResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+// upgrade upgrades the selected devices which do not run the version. The devices whose previous status was rolled back
+// or failed keep that status.
+func (r *SoftwareUpgradeResource) upgrade(plan *SoftwareUpgradeResourceData, previous map[string]SoftwareUpgradeDevice, ctx context.Context, diags *diag.Diagnostics) {
+	tflog.Debug(ctx, "SoftwareUpgradeResource: upgrade ## ", map[string]interface{}{"plan": plan})
+
+	devices, err := r.selectDevices(plan, ctx)
+	if err != nil {
+		diags.AddError("SoftwareUpgradeResource: upgrade ##: Error select devices", "Upgrade: Could not select the devices, unexpected error: "+err.Error())
+		return
+	}
+	if len(devices) == 0 {
+		diags.AddError("SoftwareUpgradeResource: upgrade ##: No device", "Upgrade: No "+plan.DeviceType.ValueString()+" matches the devices and labels.")
+		return
+	}
+
+	plan.DeviceStatus = make(map[string]SoftwareUpgradeDevice)
+	pending := []upgradeDevice{}
+	for _, device := range devices {
+		status := SoftwareUpgradeDevice{
+			Name:            types.StringValue(device.name),
+			PreviousVersion: types.StringValue(device.version),
+			Version:         types.StringValue(device.version),
+			Status:          types.StringValue("pending"),
+			Message:         types.StringNull(),
+		}
+		if previousStatus, ok := previous[device.id]; ok && device.version != plan.Version.ValueString() && retiredUpgrade(previousStatus) {
+			status.PreviousVersion = previousStatus.PreviousVersion
+			status.Status = previousStatus.Status
+			status.Message = previousStatus.Message
+		} else if device.version == plan.Version.ValueString() {
+			status.Status = types.StringValue("current")
+		} else {
+			pending = append(pending, device)
+		}
+		plan.DeviceStatus[device.id] = status
+	}
+
+	batchSize := 1
+	if !plan.BatchSize.IsNull() {
+		batchSize = int(plan.BatchSize.ValueInt64())
+	}
+	for start := 0; start < len(pending); start += batchSize {
+		end := start + batchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		if !r.upgradeBatch(plan, pending[start:end], ctx) {
+			diags.AddError(
+				"SoftwareUpgradeResource: upgrade ##: Upgrade failed",
+				"Upgrade: The upgrade of a batch failed, the remaining devices are not upgraded. See device_status for the outcome of each device.",
+			)
+			return
+		}
+	}
+}
+
+// upgradeBatch runs the upgrade steps on the devices of the batch together. The devices which fail a step leave the batch.
+// It returns false when a device of the batch failed.
+func (r *SoftwareUpgradeResource) upgradeBatch(plan *SoftwareUpgradeResourceData, batch []upgradeDevice, ctx context.Context) bool {
+	version := plan.Version.ValueString()
+	healthCheck := map[string]string{"state.connectivityState": "connected"}
+	if !plan.HealthCheck.IsNull() {
+		plan.HealthCheck.ElementsAs(ctx, &healthCheck, false)
+	}
+	healthCheck[softwareVersionPath] = version
+
+	succeeded := true
+	active := batch
+	for _, step := range []struct {
+		action     string
+		conditions map[string]string
+	}{
+		{"downloadSoftware", map[string]string{softwareUpgradeStatePath: "downloaded"}},
+		{"activateSoftware", map[string]string{softwareUpgradeStatePath: "activated", "state.connectivityState": "connected"}},
+		{"healthCheck", healthCheck},
+		{"commitSoftware", map[string]string{softwareUpgradeStatePath: "committed", softwareVersionPath: version}},
+	} {
+		if step.action != "healthCheck" {
+			for _, device := range active {
+				var body []byte
+				if step.action == "downloadSoftware" {
+					body, _ = json.Marshal(map[string]interface{}{"version": version})
+				}
+				if err := r.postAction(plan, device, step.action, body); err != nil {
+					r.setStatus(plan, device, "failed", step.action+" failed: "+err.Error())
+				}
+			}
+		}
+		remaining := []upgradeDevice{}
+		for _, device := range active {
+			if plan.DeviceStatus[device.id].Status.ValueString() == "failed" {
+				succeeded = false
+				continue
+			}
+			data, err := common.WaitForResourceState(ctx, r.client, deviceHref(plan, device), step.conditions, r.stepTimeout(plan), actionPollInterval)
+			if err != nil {
+				succeeded = false
+				r.setStatus(plan, device, "failed", step.action+" did not complete: "+err.Error())
+				if step.action == "healthCheck" && (plan.RollbackOnFailure.IsNull() || plan.RollbackOnFailure.ValueBool()) {
+					r.rollback(plan, device, ctx)
+				}
+				continue
+			}
+			if v, found := common.LookupValue(data, softwareVersionPath); found {
+				status := plan.DeviceStatus[device.id]
+				status.Version = types.StringValue(v)
+				plan.DeviceStatus[device.id] = status
+			}
+			r.setStatus(plan, device, step.action, "")
+			tflog.Info(ctx, "SoftwareUpgradeResource: upgrade ## "+device.name+" "+step.action+" completed")
+			remaining = append(remaining, device)
+		}
+		active = remaining
+	}
+	for _, device := range active {
+		r.setStatus(plan, device, "committed", "")
+	}
+	return succeeded
+}
+
+// rollback restores the previous software of a device which failed the health check.
+func (r *SoftwareUpgradeResource) rollback(plan *SoftwareUpgradeResourceData, device upgradeDevice, ctx context.Context) {
+	message := plan.DeviceStatus[device.id].Message.ValueString()
+	if err := r.postAction(plan, device, "rollbackSoftware", nil); err != nil {
+		r.setStatus(plan, device, "failed", message+"; rollbackSoftware failed: "+err.Error())
+		return
+	}
+	conditions := map[string]string{"state.connectivityState": "connected", softwareVersionPath: device.version}
+	if _, err := common.WaitForResourceState(ctx, r.client, deviceHref(plan, device), conditions, r.stepTimeout(plan), actionPollInterval); err != nil {
+		r.setStatus(plan, device, "failed", message+"; rollbackSoftware did not complete: "+err.Error())
+		return
+	}
+	status := plan.DeviceStatus[device.id]
+	status.Version = types.StringValue(device.version)
+	plan.DeviceStatus[device.id] = status
+	r.setStatus(plan, device, "rolled_back", message)
+}
+
+// postAction posts an upgrade step to the device. The software steps are not in the catalog of ipm_actions.
+func (r *SoftwareUpgradeResource) postAction(plan *SoftwareUpgradeResourceData, device upgradeDevice, action string, body []byte) error {
+	_, err := r.client.ExecuteIPMHttpCommand("POST", deviceHref(plan, device)+"/"+action, body)
+	return err
+}
+
+func (r *SoftwareUpgradeResource) setStatus(plan *SoftwareUpgradeResourceData, device upgradeDevice, status string, message string) {
+	deviceStatus := plan.DeviceStatus[device.id]
+	deviceStatus.Status = types.StringValue(status)
+	deviceStatus.Message = types.StringNull()
+	if message != "" {
+		deviceStatus.Message = types.StringValue(message)
+	}
+	plan.DeviceStatus[device.id] = deviceStatus
+}
+
+func (r *SoftwareUpgradeResource) stepTimeout(plan *SoftwareUpgradeResourceData) time.Duration {
+	timeout := int64(defaultSoftwareUpgradeTimeout)
+	if !plan.Timeout.IsNull() {
+		timeout = plan.Timeout.ValueInt64()
+	}
+	return time.Duration(timeout) * time.Second
+}
+
+// selectDevices returns the devices identified by devices and the devices having all the labels, sorted by name.
+func (r *SoftwareUpgradeResource) selectDevices(plan *SoftwareUpgradeResourceData, ctx context.Context) ([]upgradeDevice, error) {
+	collection, nameKey := "/modules", "state.moduleName"
+	if plan.DeviceType.ValueString() == "ndu" {
+		collection, nameKey = "/ndus", "state.name"
+	}
+	selected := make(map[string]upgradeDevice)
+	add := func(data map[string]interface{}) {
+		id, _ := common.LookupValue(data, "id")
+		name, _ := common.LookupValue(data, nameKey)
+		version, _ := common.LookupValue(data, softwareVersionPath)
+		selected[id] = upgradeDevice{id: id, name: name, version: version}
+	}
+
+	for _, identifier := range plan.Devices {
+		var queryString string
+		switch {
+		case !identifier.DeviceId.IsNull():
+			queryString = collection + "/" + identifier.DeviceId.ValueString() + "?content=expanded"
+		case !identifier.DeviceName.IsNull():
+			queryString = collection + common.QueryString(map[string]string{nameKey: identifier.DeviceName.ValueString()})
+		case !identifier.DeviceSerialNumber.IsNull():
+			queryString = collection + common.QueryString(map[string]string{"state.hwDescription.serialNumber": identifier.DeviceSerialNumber.ValueString()})
+		case !identifier.DeviceMACAddress.IsNull():
+			queryString = collection + common.QueryString(map[string]string{"state.hwDescription.macAddress": identifier.DeviceMACAddress.ValueString()})
+		default:
+			return nil, errors.New("a device identifier has no attribute set")
+		}
+		data, err := common.GetResource(r.client, queryString)
+		if err != nil {
+			return nil, err
+		}
+		add(data)
+	}
+
+	if !plan.Labels.IsNull() {
+		labels := make(map[string]string)
+		plan.Labels.ElementsAs(ctx, &labels, false)
+		data, err := common.GetResources(r.client, collection+"?content=expanded")
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range data {
+			if device, ok := v.(map[string]interface{}); ok && common.MatchLabels(device, labels) {
+				add(device)
+			}
+		}
+	}
+
+	devices := make([]upgradeDevice, 0, len(selected))
+	for _, device := range selected {
+		devices = append(devices, device)
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].name < devices[j].name })
+	return devices, nil
+}
+
+// read refreshes the software version of the upgraded devices.
+func (r *SoftwareUpgradeResource) read(state *SoftwareUpgradeResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	for id, status := range state.DeviceStatus {
+		data, err := common.GetResource(r.client, deviceHref(state, upgradeDevice{id: id})+"?content=expanded")
+		if err != nil {
+			tflog.Debug(ctx, "SoftwareUpgradeResource: read ## device not found", map[string]interface{}{"id": id, "error": err.Error()})
+			continue
+		}
+		if version, found := common.LookupValue(data, softwareVersionPath); found {
+			status.Version = types.StringValue(version)
+			state.DeviceStatus[id] = status
+		}
+	}
+}
+
+func softwareUpgradeDeviceAttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"name":             types.StringType,
+		"previous_version": types.StringType,
+		"version":          types.StringType,
+		"status":           types.StringType,
+		"message":          types.StringType,
+	}
+}
+
+func deviceHref(plan *SoftwareUpgradeResourceData, device upgradeDevice) string {
+	if plan.DeviceType.ValueString() == "ndu" {
+		return "/ndus/" + device.id
+	}
+	return "/modules/" + device.id
+}
+
+func SoftwareUpgradeSchemaAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Description: "Identifier of the upgrade",
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"device_type": schema.StringAttribute{
+			Description: "module or ndu",
+			Required:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"devices": schema.ListNestedAttribute{
+			Description: "The devices to upgrade, by id, name, serial number or MAC address",
+			Optional:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"device_name": schema.StringAttribute{
+						Description: "device name",
+						Optional:    true,
+					},
+					"device_id": schema.StringAttribute{
+						Description: "device id",
+						Optional:    true,
+					},
+					"device_mac_address": schema.StringAttribute{
+						Description: "device mac_address",
+						Optional:    true,
+					},
+					"device_serial_number": schema.StringAttribute{
+						Description: "device serial_number",
+						Optional:    true,
+					},
+				},
+			},
+		},
+		"labels": schema.MapAttribute{
+			Description: "Upgrades the devices which have all these labels",
+			Optional:    true,
+			ElementType: types.StringType,
+		},
+		"version": schema.StringAttribute{
+			Description: "The software version to install",
+			Required:    true,
+		},
+		"batch_size": schema.Int64Attribute{
+			Description: "Number of devices upgraded together, 1 by default",
+			Optional:    true,
+		},
+		"timeout": schema.Int64Attribute{
+			Description: "Time in seconds to wait for each step of a device. Default is 1800.",
+			Optional:    true,
+		},
+		"health_check": schema.MapAttribute{
+			Description: "Conditions the device must meet after the activation, as dotted paths in the device data and their expected values. " +
+				"Default is {\"state.connectivityState\" = \"connected\"}. The device must also run the version.",
+			Optional:    true,
+			ElementType: types.StringType,
+		},
+		"rollback_on_failure": schema.BoolAttribute{
+			Description: "Roll back the devices which fail the health check, true by default",
+			Optional:    true,
+		},
+		"device_status": schema.MapNestedAttribute{
+			Description: "Progress of each device by device id. The status is current, pending, downloadSoftware, activateSoftware, healthCheck, committed, rolled_back or failed.",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						Description: "name of the device",
+						Computed:    true,
+					},
+					"previous_version": schema.StringAttribute{
+						Description: "software version before the upgrade",
+						Computed:    true,
+					},
+					"version": schema.StringAttribute{
+						Description: "software version the device runs",
+						Computed:    true,
+					},
+					"status": schema.StringAttribute{
+						Description: "last completed step, or outcome of the upgrade",
+						Computed:    true,
+					},
+					"message": schema.StringAttribute{
+						Description: "cause of the failure",
+						Computed:    true,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,43 @@
+package actionservice
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testDeviceStatus(version string, status string) SoftwareUpgradeDevice {
+	return SoftwareUpgradeDevice{
+		Name:            types.StringValue("m1"),
+		PreviousVersion: types.StringValue("1.0"),
+		Version:         types.StringValue(version),
+		Status:          types.StringValue(status),
+		Message:         types.StringNull(),
+	}
+}
+
+func TestOutdatedDevice(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   SoftwareUpgradeDevice
+		version  string
+		outdated bool
+	}{
+		{"committed", testDeviceStatus("2.0", "committed"), "2.0", false},
+		{"downgraded", testDeviceStatus("1.0", "committed"), "2.0", true},
+		{"pending", testDeviceStatus("1.0", "pending"), "2.0", true},
+		{"rolled back", testDeviceStatus("1.0", "rolled_back"), "2.0", false},
+		{"failed", testDeviceStatus("1.0", "failed"), "2.0", false},
+		{"rolled back, other version", testDeviceStatus("1.0", "rolled_back"), "3.0", true},
+		{"failed, other version", testDeviceStatus("1.0", "failed"), "3.0", true},
+	}
+	for _, test := range tests {
+		state := SoftwareUpgradeResourceData{
+			Version:      types.StringValue("2.0"),
+			DeviceStatus: map[string]SoftwareUpgradeDevice{"d1": test.status},
+		}
+		if _, outdated := outdatedDevice(&state, test.version); outdated != test.outdated {
+			t.Errorf("%s: outdatedDevice() = %v, want %v", test.name, outdated, test.outdated)
+		}
+	}
+}
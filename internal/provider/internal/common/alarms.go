@@ -0,0 +1,171 @@
+package common
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Alarm is an active or historical alarm raised by IPM on a device resource.
+type Alarm struct {
+	Id               string
+	Href             string
+	DeviceId         string
+	Resource         string
+	Severity         string
+	ProbableCause    string
+	Description      string
+	ServiceAffecting bool
+	RaisedTime       string
+	ClearedTime      string
+	Active           bool
+	// Raised is the parsed RaisedTime, zero when IPM reports no time in RFC 3339 format
+	Raised time.Time
+}
+
+// AlarmFilter selects alarms. Empty fields do not filter; a resource matches the alarms of the resource and of its children.
+type AlarmFilter struct {
+	DeviceId      string
+	Resource      string
+	ProbableCause string
+	Severities    []string
+	StartTime     time.Time
+	EndTime       time.Time
+	History       bool
+}
+
+// GetAlarms returns the active alarms, and the alarm history if requested, which match the filter, the most recent first.
+// The alarms whose raised time cannot be parsed are kept by the time filter and come last.
+func GetAlarms(client *ipm_pf.Client, filter AlarmFilter) ([]Alarm, error) {
+	queryString := QueryString(map[string]string{"state.deviceId": filter.DeviceId, "state.probableCause": filter.ProbableCause})
+	collections := []string{"/alarms"}
+	if filter.History {
+		collections = append(collections, "/alarms/history")
+	}
+
+	alarms := []Alarm{}
+	for _, collection := range collections {
+		data, err := GetResources(client, collection+queryString)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range data {
+			record, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			alarm := alarmValue(record, collection == "/alarms")
+			if filter.matches(alarm) {
+				alarms = append(alarms, alarm)
+			}
+		}
+	}
+	sort.SliceStable(alarms, func(i, j int) bool {
+		if alarms[i].Raised.IsZero() || alarms[j].Raised.IsZero() {
+			return !alarms[i].Raised.IsZero() && alarms[j].Raised.IsZero()
+		}
+		return alarms[i].Raised.After(alarms[j].Raised)
+	})
+	return alarms, nil
+}
+
+func (filter AlarmFilter) matches(alarm Alarm) bool {
+	if filter.Resource != "" && alarm.Resource != filter.Resource && !strings.HasPrefix(alarm.Resource, filter.Resource+"/") {
+		return false
+	}
+	if len(filter.Severities) > 0 {
+		found := false
+		for _, severity := range filter.Severities {
+			found = found || strings.EqualFold(severity, alarm.Severity)
+		}
+		if !found {
+			return false
+		}
+	}
+	// an alarm without a raised time is kept, as it may be in the time window
+	if !alarm.Raised.IsZero() {
+		if (!filter.StartTime.IsZero() && alarm.Raised.Before(filter.StartTime)) || (!filter.EndTime.IsZero() && alarm.Raised.After(filter.EndTime)) {
+			return false
+		}
+	}
+	return true
+}
+
+// alarmValue converts an alarm returned by IPM, whose attributes are in its state.
+func alarmValue(record map[string]interface{}, active bool) Alarm {
+	value := func(key string) string {
+		if v, ok := LookupValue(record, "state."+key); ok {
+			return v
+		}
+		v, _ := LookupValue(record, key)
+		return v
+	}
+	alarm := Alarm{
+		Id:            value("id"),
+		Href:          value("href"),
+		DeviceId:      value("deviceId"),
+		Resource:      value("resource"),
+		Severity:      strings.ToLower(value("severity")),
+		ProbableCause: value("probableCause"),
+		Description:   value("description"),
+		RaisedTime:    value("raisedTime"),
+		ClearedTime:   value("clearedTime"),
+		Active:        active,
+	}
+	alarm.ServiceAffecting = value("serviceAffecting") == "true"
+	if raised, err := time.Parse(time.RFC3339, alarm.RaisedTime); err == nil {
+		alarm.Raised = raised
+	}
+	return alarm
+}
+
+func AlarmObjectType() types.ObjectType {
+	return types.ObjectType{
+		AttrTypes: AlarmAttributeType(),
+	}
+}
+
+func AlarmObjectsValue(alarms []Alarm) []attr.Value {
+	values := []attr.Value{}
+	for _, alarm := range alarms {
+		values = append(values, types.ObjectValueMust(AlarmAttributeType(), AlarmAttributeValue(alarm)))
+	}
+	return values
+}
+
+func AlarmAttributeType() map[string]attr.Type {
+	return map[string]attr.Type{
+		"id":                types.StringType,
+		"href":              types.StringType,
+		"device_id":         types.StringType,
+		"resource":          types.StringType,
+		"severity":          types.StringType,
+		"probable_cause":    types.StringType,
+		"description":       types.StringType,
+		"service_affecting": types.BoolType,
+		"raised_time":       types.StringType,
+		"cleared_time":      types.StringType,
+		"active":            types.BoolType,
+	}
+}
+
+func AlarmAttributeValue(alarm Alarm) map[string]attr.Value {
+	return map[string]attr.Value{
+		"id":                OptionalString(alarm.Id),
+		"href":              OptionalString(alarm.Href),
+		"device_id":         OptionalString(alarm.DeviceId),
+		"resource":          OptionalString(alarm.Resource),
+		"severity":          OptionalString(alarm.Severity),
+		"probable_cause":    OptionalString(alarm.ProbableCause),
+		"description":       OptionalString(alarm.Description),
+		"service_affecting": types.BoolValue(alarm.ServiceAffecting),
+		"raised_time":       OptionalString(alarm.RaisedTime),
+		"cleared_time":      OptionalString(alarm.ClearedTime),
+		"active":            types.BoolValue(alarm.Active),
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"terraform-provider-ipm/internal/ipm_pf"
 	"time"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -36,116 +39,178 @@ func CheckResourceState(ctx context.Context, client *ipm_pf.Client, queryString
 	return false, errors.New("The resource is not in \"Configured\" state")
 }
 
-func CheckNetworkState(ctx context.Context, client *ipm_pf.Client, queryStrings []string, endpointIds []string, tryCount ...int) ( found bool, error error ) {
-	numRetry := 1
-	if len(tryCount) > 0 {
-		numRetry = tryCount[0]
+func FindResource(client *ipm_pf.Client, queryString string) ( data map[string]interface{}, error error ) {
+	body, err := client.ExecuteIPMHttpCommand("GET", queryString, nil)
+	if err != nil {
+		return nil, errors.New("Can't get the resource: " + queryString)
 	}
+	var resps []interface{}
+	err = json.Unmarshal(body, &resps)
+	if err != nil {
+		return nil, errors.New("Can't unmarshall the resource's data: " + queryString)
+	}
+	if len(resps) > 0 {
+		return resps[0].(map[string]interface{}), nil
+	}
+	return nil, errors.New("Can't find resource for query string: " + queryString)
+}
 
-	tflog.Debug(ctx, "CheckNetworkState:  ", map[string]interface{}{"query 1": queryStrings[0], "query 2": queryStrings[1] })
+// WaitForResourceState polls the resource until all the conditions are met or the timeout expires.
+// A condition maps a dotted path in the resource data, e.g. "state.connectivityState", to its expected value.
+func WaitForResourceState(ctx context.Context, client *ipm_pf.Client, queryString string, conditions map[string]string, timeout time.Duration, pollInterval time.Duration) (map[string]interface{}, error) {
+	tflog.Debug(ctx, "WaitForResourceState:  ", map[string]interface{}{"query": queryString, "conditions": conditions, "timeout": timeout.String()})
 
-	query := queryStrings[0]
-	useQuery2 := false
-	for i := 1; i <= numRetry; i++ {
-		data, err := FindResource(client, query)
-		if err != nil && !useQuery2 {
-			useQuery2 = true
-			query = queryStrings[1]
-			data, err = FindResource(client, query)
+	var data map[string]interface{}
+	deadline := time.Now().Add(timeout)
+	for {
+		select {
+		case <-ctx.Done():
+			return data, ctx.Err()
+		case <-time.After(pollInterval):
 		}
-		if err == nil && data["state"] != nil {
-			networkState := data["state"].(map[string]interface{})
-			networkLCState := networkState["lifecycleState"]
-			if networkLCState != nil && (networkLCState.(string) == "configured" ||  networkLCState.(string) == "pendingConfiguration") {
-				leafModules := data["leafModules"].([]interface{})
-				if len(leafModules) > 0  {
-					for _, v := range leafModules {
-						leaf := v.(map[string]interface{})
-						state := leaf["state"].(map[string]interface{})
-						if state["lifecycleState"] == "configured" {
-							leafConfig := leaf["config"].(map[string]interface{})
-							if leafConfig["managedBy"].(string) != "host" {
-								leafConfigSelector := leafConfig["selector"].(map[string]interface{})
-								for k2, v2 := range leafConfigSelector {
-									leafSelector := v2.(map[string]interface{})
-									switch k2 {
-									case "moduleSelectorByModuleId":
-										id := leafSelector["moduleId"].(string)
-										if id == endpointIds[0] || id == endpointIds[1] {
-											return true, nil
-										}
-									case "moduleSelectorByModuleName":
-										id := leafSelector["moduleName"].(string)
-										if id == endpointIds[0] || id == endpointIds[1] {
-											return true, nil
-										}
-									case "moduleSelectorByModuleMAC":
-										id := leafSelector["moduleMAC"].(string)
-										if id == endpointIds[0] || id == endpointIds[1] {
-											return true, nil
-										}
-									case "moduleSelectorByModuleSerialNumber":
-										id := leafSelector["moduleSerialNumber"].(string)
-										if id == endpointIds[0] || id == endpointIds[1] {
-											return true, nil
-										}
-									case "hostPortSelectorByName":
-										id := leafSelector["hostName"].(string) + ":" + leafSelector["hostPortName"].(string)
-										if id == endpointIds[0] || id == endpointIds[1] {
-											return true, nil
-										}
-									case "hostPortSelectorByPortId":
-										id := leafSelector["chassisId"].(string) + ":" + leafSelector["portId"].(string)
-										if id == endpointIds[0] || id == endpointIds[1] {
-											return true, nil
-										}
-									case "hostPortSelectorBySysName":
-										id := leafSelector["sysName"].(string) + ":" + leafSelector["portId"].(string)
-										if id == endpointIds[0] || id == endpointIds[1] {
-											return true, nil
-										}
-									case "hostPortSelectorByPortSourceMAC":
-										id := leafSelector["portSourceMAC"].(string) 
-										if id == endpointIds[0] || id == endpointIds[1] {
-											return true, nil
-										}
-									}
-								}
-							} else {
-								leafModule := state["module"].(map[string]interface{})
-								if leafModule["moduleId"].(string) == endpointIds[0] || leafModule["moduleId"].(string) == endpointIds[1] || leafModule["moduleName"].(string) == endpointIds[0] || leafModule["moduleName"].(string) == endpointIds[1] || leafModule["macAddress"].(string) == endpointIds[0] || leafModule["macAddress"].(string) == endpointIds[1] || leafModule["serialNumber"].(string) == endpointIds[0] || leafModule["serialNumber"].(string) == endpointIds[1] {
-									return true, nil
-								}
-							}
-						}
-					}
-				} else {
-					return false, errors.New("Constellation has no leaf: " + networkState["name"].(string))
-				}
-			} 
-		} else {
-			return false, err
+		var err error
+		data, err = GetResource(client, queryString)
+		if err == nil {
+			err = CheckConditions(data, conditions)
+			if err == nil {
+				return data, nil
+			}
 		}
-		if i < numRetry {
-			time.Sleep(15 * time.Second)
+		tflog.Debug(ctx, "WaitForResourceState: not completed ", map[string]interface{}{"query": queryString, "cause": err.Error()})
+		if time.Now().After(deadline) {
+			return data, errors.New("Timeout after " + timeout.String() + " waiting for " + queryString + ": " + err.Error())
 		}
-	} 
-	return false, errors.New("The resource is not in \"Configured\" state")
+	}
 }
 
+// WaitForResourceStateChange waits until the resource leaves the state of the conditions, e.g. a device which restarts
+// gets disconnected, then polls it until the conditions are met again. A resource which cannot be read, e.g. a device
+// which is unreachable while it restarts, has left the state. Both phases share the timeout.
+func WaitForResourceStateChange(ctx context.Context, client *ipm_pf.Client, queryString string, conditions map[string]string, timeout time.Duration, pollInterval time.Duration, leaveInterval time.Duration) (map[string]interface{}, error) {
+	tflog.Debug(ctx, "WaitForResourceStateChange:  ", map[string]interface{}{"query": queryString, "conditions": conditions, "timeout": timeout.String()})
 
-func FindResource(client *ipm_pf.Client, queryString string) ( data map[string]interface{}, error error ) {
+	deadline := time.Now().Add(timeout)
+	for {
+		data, err := GetResource(client, queryString)
+		if err != nil || CheckConditions(data, conditions) != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return data, errors.New("Timeout after " + timeout.String() + " waiting for " + queryString + " to leave its state")
+		}
+		select {
+		case <-ctx.Done():
+			return data, ctx.Err()
+		case <-time.After(leaveInterval):
+		}
+	}
+	return WaitForResourceState(ctx, client, queryString, conditions, time.Until(deadline), pollInterval)
+}
+
+// CheckConditions returns an error naming the first condition which the resource data does not meet.
+func CheckConditions(data map[string]interface{}, conditions map[string]string) error {
+	for key, expected := range conditions {
+		value, found := LookupValue(data, key)
+		if !found {
+			return errors.New(key + " is not present, expected \"" + expected + "\"")
+		}
+		if value != expected {
+			return errors.New(key + " is \"" + value + "\", expected \"" + expected + "\"")
+		}
+	}
+	return nil
+}
+
+// LookupValue returns the value at a dotted path of the resource data as string. List elements are addressed by index.
+func LookupValue(data map[string]interface{}, key string) (string, bool) {
+	var value interface{} = data
+	for _, item := range strings.Split(key, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[item]
+		case []interface{}:
+			idx, err := strconv.Atoi(item)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return "", false
+			}
+			value = v[idx]
+		default:
+			return "", false
+		}
+		if value == nil {
+			return "", false
+		}
+	}
+	return fmt.Sprint(value), true
+}
+
+// MatchLabels tells whether the resource has all the labels, in its config or in its state.
+func MatchLabels(data map[string]interface{}, labels map[string]string) bool {
+	for key, value := range labels {
+		label, found := LookupValue(data, "config.labels."+key)
+		if !found {
+			label, found = LookupValue(data, "state.labels."+key)
+		}
+		if !found || label != value {
+			return false
+		}
+	}
+	return true
+}
+
+// GetResource returns the resource found by the query string, the first one when IPM returns a list.
+func GetResource(client *ipm_pf.Client, queryString string) ( data map[string]interface{}, error error ) {
 	body, err := client.ExecuteIPMHttpCommand("GET", queryString, nil)
 	if err != nil {
 		return nil, errors.New("Can't get the resource: " + queryString)
 	}
-	var resps []interface{}
-	err = json.Unmarshal(body, &resps)
+	var resp interface{}
+	err = json.Unmarshal(body, &resp)
 	if err != nil {
 		return nil, errors.New("Can't unmarshall the resource's data: " + queryString)
 	}
-	if len(resps) > 0 {
-		return resps[0].(map[string]interface{}), nil
+	switch resp := resp.(type) {
+	case []interface{}:
+		if len(resp) > 0 {
+			return resp[0].(map[string]interface{}), nil
+		}
+	case map[string]interface{}:
+		return resp, nil
 	}
 	return nil, errors.New("Can't find resource for query string: " + queryString)
-}
\ No newline at end of file
+}
+
+// GetResources returns the resources found by the query string, a single resource is returned as a list of one.
+func GetResources(client *ipm_pf.Client, queryString string) ( data []interface{}, error error ) {
+	body, err := client.ExecuteIPMHttpCommand("GET", queryString, nil)
+	if err != nil {
+		return nil, errors.New("Can't get the resources: " + queryString + ": " + err.Error())
+	}
+	var resp interface{}
+	err = json.Unmarshal(body, &resp)
+	if err != nil {
+		return nil, errors.New("Can't unmarshall the resources' data: " + queryString)
+	}
+	switch resp := resp.(type) {
+	case []interface{}:
+		return resp, nil
+	case map[string]interface{}:
+		return []interface{}{resp}, nil
+	}
+	return []interface{}{}, nil
+}
+
+// QueryString returns the expanded query of a collection, filtered by the conditions with a non empty value.
+func QueryString(conditions map[string]string) string {
+	filter := make(map[string]string)
+	for k, v := range conditions {
+		if v != "" {
+			filter[k] = v
+		}
+	}
+	if len(filter) == 0 {
+		return "?content=expanded"
+	}
+	q, _ := json.Marshal(filter)
+	return "?content=expanded&q=" + string(q)
+}
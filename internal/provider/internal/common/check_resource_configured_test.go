@@ -0,0 +1,129 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+)
+
+func testResource(t *testing.T) map[string]interface{} {
+	var data map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"id": "m1",
+		"state": {
+			"lifecycleState": "configured",
+			"capacity": 400,
+			"enabled": true,
+			"labels": {"site": "north"},
+			"endpoints": [{"moduleId": "a"}, {"moduleId": "b"}],
+			"description": null
+		}
+	}`), &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestLookupValue(t *testing.T) {
+	data := testResource(t)
+	tests := []struct {
+		key   string
+		value string
+		found bool
+	}{
+		{"id", "m1", true},
+		{"state.lifecycleState", "configured", true},
+		{"state.capacity", "400", true},
+		{"state.enabled", "true", true},
+		{"state.labels.site", "north", true},
+		{"state.endpoints.1.moduleId", "b", true},
+		{"state.endpoints.2.moduleId", "", false},
+		{"state.endpoints.-1.moduleId", "", false},
+		{"state.endpoints.first.moduleId", "", false},
+		{"state.description", "", false},
+		{"state.lifecycleState.value", "", false},
+		{"config.moduleName", "", false},
+	}
+	for _, test := range tests {
+		value, found := LookupValue(data, test.key)
+		if value != test.value || found != test.found {
+			t.Errorf("LookupValue(%q) = %q, %v, want %q, %v", test.key, value, found, test.value, test.found)
+		}
+	}
+}
+
+func TestCheckConditions(t *testing.T) {
+	data := testResource(t)
+	tests := []struct {
+		name       string
+		conditions map[string]string
+		hold       bool
+	}{
+		{"no conditions", map[string]string{}, true},
+		{"all match", map[string]string{"state.lifecycleState": "configured", "state.endpoints.0.moduleId": "a"}, true},
+		{"number", map[string]string{"state.capacity": "400"}, true},
+		{"mismatch", map[string]string{"state.lifecycleState": "configured", "state.enabled": "false"}, false},
+		{"missing", map[string]string{"state.operStatus": "up"}, false},
+	}
+	for _, test := range tests {
+		err := CheckConditions(data, test.conditions)
+		if (err == nil) != test.hold {
+			t.Errorf("%s: CheckConditions() = %v, want hold %v", test.name, err, test.hold)
+		}
+	}
+}
+
+// testDevice fakes the IPM view of a device: it answers with the responses in turn, an empty response is an unreachable
+// device, and keeps answering the last one.
+func testDevice(t *testing.T, responses ...string) (*ipm_pf.Client, *int) {
+	requests := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		response := responses[len(responses)-1]
+		if requests < len(responses) {
+			response = responses[requests]
+		}
+		requests++
+		if response == "" {
+			http.Error(w, "device unreachable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return &ipm_pf.Client{HostURL: strings.TrimPrefix(server.URL, "https://"), HTTPClient: server.Client()}, &requests
+}
+
+func TestWaitForResourceStateChange(t *testing.T) {
+	configured := `{"state": {"connectionState": "connected"}}`
+	disconnected := `{"state": {"connectionState": "disconnected"}}`
+	tests := []struct {
+		name      string
+		responses []string
+		completed bool
+		requests  int
+	}{
+		{"unreachable while restarting", []string{configured, "", "", configured}, true, 4},
+		{"disconnected while restarting", []string{configured, disconnected, configured}, true, 3},
+		{"unreachable at once", []string{"", configured}, true, 2},
+		{"never leaves the state", []string{configured}, false, 0},
+		{"never comes back", []string{configured, ""}, false, 0},
+	}
+	for _, test := range tests {
+		client, requests := testDevice(t, test.responses...)
+		_, err := WaitForResourceStateChange(context.Background(), client, "/modules/m1", map[string]string{"state.connectionState": "connected"},
+			200*time.Millisecond, time.Millisecond, time.Millisecond)
+		if (err == nil) != test.completed {
+			t.Errorf("%s: WaitForResourceStateChange() = %v, want completed %v", test.name, err, test.completed)
+		}
+		if test.completed && *requests != test.requests {
+			t.Errorf("%s: WaitForResourceStateChange() made %d requests, want %d", test.name, *requests, test.requests)
+		}
+	}
+}
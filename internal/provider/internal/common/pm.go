@@ -0,0 +1,227 @@
+package common
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// PMObject is an object whose PM counters are read: its type, such as line_ptp or carrier, and its identifier.
+type PMObject struct {
+	Type       types.String       `tfsdk:"type"`
+	Identifier ResourceIdentifier `tfsdk:"identifier"`
+}
+
+// PMDataSourceData is the query and the bins of the module and NDU PM data sources.
+type PMDataSourceData struct {
+	Objects     []PMObject   `tfsdk:"objects"`
+	Granularity types.String `tfsdk:"granularity"`
+	Current     types.Bool   `tfsdk:"current"`
+	StartTime   types.String `tfsdk:"start_time"`
+	EndTime     types.String `tfsdk:"end_time"`
+	Bins        types.List   `tfsdk:"bins"`
+}
+
+// PMBin is a current or historical bin of PM counters of an object.
+type PMBin struct {
+	Href        string
+	Type        string
+	Bin         string
+	Granularity string
+	StartTime   string
+	EndTime     string
+	Values      map[string]float64
+}
+
+// pmBinAttributes are the attributes of a bin which are not counters.
+var pmBinAttributes = map[string]bool{
+	"granularity": true, "startTime": true, "endTime": true, "id": true, "href": true, "parentId": true, "aid": true,
+}
+
+// PMDataSourceSchemaAttributes returns the schema of the module and NDU PM data sources, for the given object types.
+func PMDataSourceSchemaAttributes(objectTypes []string) map[string]schema.Attribute {
+	identifier := ResourceIdentifierAttribute()
+	identifier.Optional = false
+	identifier.Required = true
+	identifier.Description = "Identifier of the object: its href, or the device_id and its col ids"
+	return map[string]schema.Attribute{
+		"objects": schema.ListNestedAttribute{
+			Description: "The objects whose PM counters are read",
+			Required:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"type": schema.StringAttribute{
+						Description: "type of the object: " + strings.Join(objectTypes, ", "),
+						Required:    true,
+					},
+					"identifier": identifier,
+				},
+			},
+		},
+		"granularity": schema.StringAttribute{
+			Description: "granularity of the bins: 15min or 24h, 15min by default",
+			Optional:    true,
+		},
+		"current": schema.BoolAttribute{
+			Description: "whether the current bins are read, true by default",
+			Optional:    true,
+		},
+		"start_time": schema.StringAttribute{
+			Description: "start of the time range of the historical bins, in RFC 3339 format. The historical bins are read when start_time or end_time is set.",
+			Optional:    true,
+		},
+		"end_time": schema.StringAttribute{
+			Description: "end of the time range of the historical bins, in RFC 3339 format, now by default",
+			Optional:    true,
+		},
+		"bins": schema.ListAttribute{
+			Description: "current and historical bins of the objects",
+			Computed:    true,
+			ElementType: PMBinObjectType(),
+		},
+	}
+}
+
+// ReadPMBins reads the current bins, and the historical bins in the time range of the query, of the objects.
+// The href of an object is returned by objectHref, its PM counters are read from the pm/current and pm/history
+// collections under it.
+func ReadPMBins(client *ipm_pf.Client, query *PMDataSourceData, objectHref func(PMObject) (string, error)) ([]PMBin, error) {
+	granularity := "15min"
+	if !query.Granularity.IsNull() {
+		granularity = query.Granularity.ValueString()
+	}
+	var startTime, endTime time.Time
+	history := !query.StartTime.IsNull() || !query.EndTime.IsNull()
+	if !query.StartTime.IsNull() {
+		t, err := time.Parse(time.RFC3339, query.StartTime.ValueString())
+		if err != nil {
+			return nil, errors.New("start_time is not in RFC 3339 format: " + err.Error())
+		}
+		startTime = t
+	}
+	endTime = time.Now()
+	if !query.EndTime.IsNull() {
+		t, err := time.Parse(time.RFC3339, query.EndTime.ValueString())
+		if err != nil {
+			return nil, errors.New("end_time is not in RFC 3339 format: " + err.Error())
+		}
+		endTime = t
+	}
+	queryString := QueryString(map[string]string{"granularity": granularity})
+
+	bins := []PMBin{}
+	for _, object := range query.Objects {
+		href, err := objectHref(object)
+		if err != nil {
+			return nil, err
+		}
+		if query.Current.IsNull() || query.Current.ValueBool() {
+			data, err := GetResources(client, href+"/pm/current"+queryString)
+			if err != nil {
+				return nil, err
+			}
+			bins = append(bins, pmBins(href, object.Type.ValueString(), "current", data, time.Time{}, time.Time{})...)
+		}
+		if history {
+			data, err := GetResources(client, href+"/pm/history"+queryString)
+			if err != nil {
+				return nil, err
+			}
+			bins = append(bins, pmBins(href, object.Type.ValueString(), "history", data, startTime, endTime)...)
+		}
+	}
+	return bins, nil
+}
+
+// pmBins converts the bins returned by IPM, keeping the historical bins which start in the time range.
+func pmBins(href string, objectType string, bin string, data []interface{}, startTime time.Time, endTime time.Time) []PMBin {
+	bins := []PMBin{}
+	for _, v := range data {
+		record, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// the counters are in the state of the bin, if the bin has one
+		if state, ok := record["state"].(map[string]interface{}); ok {
+			record = state
+		}
+		pmBin := PMBin{Href: href, Type: objectType, Bin: bin, Values: make(map[string]float64)}
+		pmBin.Granularity, _ = record["granularity"].(string)
+		pmBin.StartTime, _ = record["startTime"].(string)
+		pmBin.EndTime, _ = record["endTime"].(string)
+		if bin == "history" && pmBin.StartTime != "" {
+			if t, err := time.Parse(time.RFC3339, pmBin.StartTime); err == nil && (t.Before(startTime) || t.After(endTime)) {
+				continue
+			}
+		}
+		for key, value := range record {
+			if !pmBinAttributes[key] {
+				flattenPMValues(key, value, pmBin.Values)
+			}
+		}
+		bins = append(bins, pmBin)
+	}
+	sort.SliceStable(bins, func(i, j int) bool { return bins[i].StartTime < bins[j].StartTime })
+	return bins
+}
+
+// flattenPMValues adds the numeric values of a counter, naming nested values by their dotted path such as preFecBer.avg.
+func flattenPMValues(name string, value interface{}, values map[string]float64) {
+	switch value := value.(type) {
+	case float64:
+		values[name] = value
+	case map[string]interface{}:
+		for key, v := range value {
+			flattenPMValues(name+"."+key, v, values)
+		}
+	}
+}
+
+func PMBinObjectType() types.ObjectType {
+	return types.ObjectType{
+		AttrTypes: PMBinAttributeType(),
+	}
+}
+
+func PMBinObjectsValue(bins []PMBin) []attr.Value {
+	values := []attr.Value{}
+	for _, bin := range bins {
+		values = append(values, types.ObjectValueMust(PMBinAttributeType(), PMBinAttributeValue(bin)))
+	}
+	return values
+}
+
+func PMBinAttributeType() map[string]attr.Type {
+	return map[string]attr.Type{
+		"href":        types.StringType,
+		"type":        types.StringType,
+		"bin":         types.StringType,
+		"granularity": types.StringType,
+		"start_time":  types.StringType,
+		"end_time":    types.StringType,
+		"values":      types.MapType{ElemType: types.Float64Type},
+	}
+}
+
+func PMBinAttributeValue(bin PMBin) map[string]attr.Value {
+	values := map[string]attr.Value{}
+	for k, v := range bin.Values {
+		values[k] = types.Float64Value(v)
+	}
+	return map[string]attr.Value{
+		"href":        types.StringValue(bin.Href),
+		"type":        types.StringValue(bin.Type),
+		"bin":         types.StringValue(bin.Bin),
+		"granularity": OptionalString(bin.Granularity),
+		"start_time":  OptionalString(bin.StartTime),
+		"end_time":    OptionalString(bin.EndTime),
+		"values":      types.MapValueMust(types.Float64Type, values),
+	}
+}
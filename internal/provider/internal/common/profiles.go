@@ -0,0 +1,421 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// NetworkProfile refers by name to the network config profile and the hub and leaf module config profiles of a constellation network.
+type NetworkProfile struct {
+	NetworkConfigProfile types.String `tfsdk:"network_config_profile"`
+	HubConfigProfile     types.String `tfsdk:"hub_config_profile"`
+	LeafConfigProfile    types.String `tfsdk:"leaf_config_profile"`
+}
+
+type NetworkConfigProfile struct {
+	ConstellationFrequency types.Int64  `tfsdk:"constellation_frequency"`
+	Modulation             types.String `tfsdk:"modulation"`
+	TcMode                 types.Bool   `tfsdk:"tc_mode"`
+	Topology               types.String `tfsdk:"topology"`
+	ManagedBy              types.String `tfsdk:"managed_by"`
+}
+
+type ModuleConfigProfile struct {
+	TrafficMode               types.String `tfsdk:"traffic_mode"`
+	FiberConnectionMode       types.String `tfsdk:"fiber_connection_mode"`
+	ManagedBy                 types.String `tfsdk:"managed_by"`
+	PlannedCapacity           types.String `tfsdk:"planned_capacity"`
+	RequestedNominalPsdOffset types.String `tfsdk:"requested_nominal_psd_offset"`
+	FecIterations             types.String `tfsdk:"fec_iterations"`
+	TxCLPtarget               types.Int64  `tfsdk:"tx_clp_target"`
+}
+
+type NCProfile struct {
+	ServiceMode               types.String `tfsdk:"service_mode"`
+	MC                        types.String `tfsdk:"mc"`
+	OuterVID                  types.String `tfsdk:"outer_vid"`
+	ImplicitTransportCapacity types.String `tfsdk:"implicit_transport_capacity"`
+	Labels                    types.Map    `tfsdk:"labels"`
+	EndpointCapacity          types.Int64  `tfsdk:"endpoint_capacity"`
+}
+
+type TCProfile struct {
+	CapacityMode     types.String `tfsdk:"capacity_mode"`
+	Labels           types.Map    `tfsdk:"labels"`
+	EndpointCapacity types.Int64  `tfsdk:"endpoint_capacity"`
+}
+
+// Profiles is the provider level profiles block. Resources refer to the profiles by name, the profiles are passed to them
+// in the provider data. The lookups of a nil Profiles find no profile.
+type Profiles struct {
+	NetworkProfiles       map[string]NetworkProfile       `tfsdk:"network_profiles"`
+	NetworkConfigProfiles map[string]NetworkConfigProfile `tfsdk:"network_config_profiles"`
+	ModuleConfigProfiles  map[string]ModuleConfigProfile  `tfsdk:"module_config_profiles"`
+	NCProfiles            map[string]NCProfile            `tfsdk:"nc_profiles"`
+	TCProfiles            map[string]TCProfile            `tfsdk:"tc_profiles"`
+}
+
+// Validate checks that the config profiles referred by the network profiles exist, and that the endpoint capacities of
+// the NC and TC profiles are positive. The errors are attached to the attribute in error under root, the profiles block.
+func (p *Profiles) Validate(root path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, name := range sortedKeys(p.NetworkProfiles) {
+		profile := p.NetworkProfiles[name]
+		attribute := root.AtName("network_profiles").AtMapKey(name)
+		p.checkReference(attribute.AtName("network_config_profile"), "network profile "+name+" network_config_profile", profile.NetworkConfigProfile, hasKey(p.NetworkConfigProfiles, profile.NetworkConfigProfile.ValueString()), &diags)
+		p.checkReference(attribute.AtName("hub_config_profile"), "network profile "+name+" hub_config_profile", profile.HubConfigProfile, hasKey(p.ModuleConfigProfiles, profile.HubConfigProfile.ValueString()), &diags)
+		p.checkReference(attribute.AtName("leaf_config_profile"), "network profile "+name+" leaf_config_profile", profile.LeafConfigProfile, hasKey(p.ModuleConfigProfiles, profile.LeafConfigProfile.ValueString()), &diags)
+	}
+	for _, name := range sortedKeys(p.NCProfiles) {
+		checkEndpointCapacity(root.AtName("nc_profiles").AtMapKey(name).AtName("endpoint_capacity"), "NC profile "+name, p.NCProfiles[name].EndpointCapacity, &diags)
+	}
+	for _, name := range sortedKeys(p.TCProfiles) {
+		checkEndpointCapacity(root.AtName("tc_profiles").AtMapKey(name).AtName("endpoint_capacity"), "TC profile "+name, p.TCProfiles[name].EndpointCapacity, &diags)
+	}
+	return diags
+}
+
+func (p *Profiles) checkReference(attribute path.Path, what string, reference types.String, found bool, diags *diag.Diagnostics) {
+	if reference.IsNull() || reference.IsUnknown() || found {
+		return
+	}
+	diags.AddAttributeError(attribute, "Invalid profile reference", what+" refers to "+reference.ValueString()+", which is not defined.")
+}
+
+func checkEndpointCapacity(attribute path.Path, what string, capacity types.Int64, diags *diag.Diagnostics) {
+	if capacity.IsNull() || capacity.IsUnknown() || capacity.ValueInt64() > 0 {
+		return
+	}
+	diags.AddAttributeError(attribute, "Invalid endpoint capacity", what+" endpoint_capacity must be greater than 0.")
+}
+
+// FindNetworkProfile returns the network profile with its network config profile and hub module config profile.
+func (p *Profiles) FindNetworkProfile(name string) (NetworkProfile, NetworkConfigProfile, ModuleConfigProfile, error) {
+	if p == nil {
+		p = &Profiles{}
+	}
+	profile, ok := p.NetworkProfiles[name]
+	if !ok {
+		return NetworkProfile{}, NetworkConfigProfile{}, ModuleConfigProfile{}, errors.New("Network profile " + name + " is not defined in the provider profiles.")
+	}
+	var networkConfig NetworkConfigProfile
+	if !profile.NetworkConfigProfile.IsNull() {
+		networkConfig, ok = p.NetworkConfigProfiles[profile.NetworkConfigProfile.ValueString()]
+		if !ok {
+			return profile, networkConfig, ModuleConfigProfile{}, errors.New("Network profile " + name + " refers to network config profile " + profile.NetworkConfigProfile.ValueString() + ", which is not defined.")
+		}
+	}
+	var hubConfig ModuleConfigProfile
+	if !profile.HubConfigProfile.IsNull() {
+		hubConfig, ok = p.ModuleConfigProfiles[profile.HubConfigProfile.ValueString()]
+		if !ok {
+			return profile, networkConfig, hubConfig, errors.New("Network profile " + name + " refers to hub config profile " + profile.HubConfigProfile.ValueString() + ", which is not defined.")
+		}
+	}
+	if !profile.LeafConfigProfile.IsNull() && !hasKey(p.ModuleConfigProfiles, profile.LeafConfigProfile.ValueString()) {
+		return profile, networkConfig, hubConfig, errors.New("Network profile " + name + " refers to leaf config profile " + profile.LeafConfigProfile.ValueString() + ", which is not defined.")
+	}
+	return profile, networkConfig, hubConfig, nil
+}
+
+// FindModuleConfigProfile returns the module config profile.
+func (p *Profiles) FindModuleConfigProfile(name string) (ModuleConfigProfile, error) {
+	if p == nil {
+		p = &Profiles{}
+	}
+	profile, ok := p.ModuleConfigProfiles[name]
+	if !ok {
+		return profile, errors.New("Module config profile " + name + " is not defined in the provider profiles.")
+	}
+	return profile, nil
+}
+
+// FindNCProfile returns the NC profile.
+func (p *Profiles) FindNCProfile(name string) (NCProfile, error) {
+	if p == nil {
+		p = &Profiles{}
+	}
+	profile, ok := p.NCProfiles[name]
+	if !ok {
+		return profile, errors.New("NC profile " + name + " is not defined in the provider profiles.")
+	}
+	return profile, nil
+}
+
+// FindTCProfile returns the TC profile.
+func (p *Profiles) FindTCProfile(name string) (TCProfile, error) {
+	if p == nil {
+		p = &Profiles{}
+	}
+	profile, ok := p.TCProfiles[name]
+	if !ok {
+		return profile, errors.New("TC profile " + name + " is not defined in the provider profiles.")
+	}
+	return profile, nil
+}
+
+// Request returns the IPM config settings of the profile.
+func (p NetworkConfigProfile) Request() map[string]interface{} {
+	request := make(map[string]interface{})
+	if !p.ConstellationFrequency.IsNull() {
+		request["constellationFrequency"] = p.ConstellationFrequency.ValueInt64()
+	}
+	if !p.Modulation.IsNull() {
+		request["modulation"] = p.Modulation.ValueString()
+	}
+	if !p.TcMode.IsNull() {
+		request["tcMode"] = p.TcMode.ValueBool()
+	}
+	if !p.Topology.IsNull() {
+		request["topology"] = p.Topology.ValueString()
+	}
+	if !p.ManagedBy.IsNull() {
+		request["managedBy"] = p.ManagedBy.ValueString()
+	}
+	return request
+}
+
+// Request returns the IPM module settings of the profile. managed_by is not a module setting, it is left to the caller.
+func (p ModuleConfigProfile) Request() map[string]interface{} {
+	request := make(map[string]interface{})
+	if !p.TrafficMode.IsNull() {
+		request["trafficMode"] = p.TrafficMode.ValueString()
+	}
+	if !p.FiberConnectionMode.IsNull() {
+		request["fiberConnectionMode"] = p.FiberConnectionMode.ValueString()
+	}
+	if !p.PlannedCapacity.IsNull() {
+		request["plannedCapacity"] = p.PlannedCapacity.ValueString()
+	}
+	if !p.RequestedNominalPsdOffset.IsNull() {
+		request["requestedNominalPsdOffset"] = p.RequestedNominalPsdOffset.ValueString()
+	}
+	if !p.FecIterations.IsNull() {
+		request["fecIterations"] = p.FecIterations.ValueString()
+	}
+	if !p.TxCLPtarget.IsNull() {
+		request["txCLPtarget"] = p.TxCLPtarget.ValueInt64()
+	}
+	return request
+}
+
+// Request returns the IPM NC settings of the profile.
+func (p NCProfile) Request(ctx context.Context) map[string]interface{} {
+	request := make(map[string]interface{})
+	if !p.ServiceMode.IsNull() {
+		request["serviceMode"] = p.ServiceMode.ValueString()
+	}
+	if !p.MC.IsNull() {
+		request["mc"] = p.MC.ValueString()
+	}
+	if !p.OuterVID.IsNull() {
+		request["outerVID"] = p.OuterVID.ValueString()
+	}
+	if !p.ImplicitTransportCapacity.IsNull() {
+		request["implicitTransportCapacity"] = p.ImplicitTransportCapacity.ValueString()
+	}
+	if labels := profileLabels(ctx, p.Labels); labels != nil {
+		request["labels"] = labels
+	}
+	return request
+}
+
+// Request returns the IPM TC config settings of the profile.
+func (p TCProfile) Request(ctx context.Context) map[string]interface{} {
+	request := make(map[string]interface{})
+	if !p.CapacityMode.IsNull() {
+		request["capacityMode"] = p.CapacityMode.ValueString()
+	}
+	if labels := profileLabels(ctx, p.Labels); labels != nil {
+		request["labels"] = labels
+	}
+	return request
+}
+
+// MergeProfile adds the profile settings which are not set in the request.
+func MergeProfile(request map[string]interface{}, profile map[string]interface{}) {
+	for k, v := range profile {
+		if _, ok := request[k]; !ok {
+			request[k] = v
+		}
+	}
+}
+
+func profileLabels(ctx context.Context, labels types.Map) map[string]string {
+	if labels.IsNull() || labels.IsUnknown() {
+		return nil
+	}
+	values := map[string]string{}
+	if diags := labels.ElementsAs(ctx, &values, true); diags.HasError() {
+		return nil
+	}
+	return values
+}
+
+func hasKey[V any](m map[string]V, key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ProfilesSchema is the schema of the provider profiles block.
+func ProfilesSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: "Named profiles which ipm_constellation_network, ipm_network_connection and ipm_transport_capacity refer by their profile attribute.",
+		Optional:    true,
+		Attributes: map[string]schema.Attribute{
+			"network_profiles": schema.MapNestedAttribute{
+				Description: "network profiles, refer to network_config_profiles and module_config_profiles by name",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"network_config_profile": schema.StringAttribute{
+							Description: "network_config_profile",
+							Optional:    true,
+						},
+						"hub_config_profile": schema.StringAttribute{
+							Description: "hub_config_profile",
+							Optional:    true,
+						},
+						"leaf_config_profile": schema.StringAttribute{
+							Description: "leaf_config_profile",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"network_config_profiles": schema.MapNestedAttribute{
+				Description: "network config profiles",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"constellation_frequency": schema.Int64Attribute{
+							Description: "constellation_frequency",
+							Optional:    true,
+						},
+						"modulation": schema.StringAttribute{
+							Description: "modulation",
+							Optional:    true,
+						},
+						"tc_mode": schema.BoolAttribute{
+							Description: "tc_mode",
+							Optional:    true,
+						},
+						"topology": schema.StringAttribute{
+							Description: "topology",
+							Optional:    true,
+						},
+						"managed_by": schema.StringAttribute{
+							Description: "managed_by",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"module_config_profiles": schema.MapNestedAttribute{
+				Description: "module config profiles",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"traffic_mode": schema.StringAttribute{
+							Description: "traffic_mode",
+							Optional:    true,
+						},
+						"fiber_connection_mode": schema.StringAttribute{
+							Description: "fiber_connection_mode",
+							Optional:    true,
+						},
+						"managed_by": schema.StringAttribute{
+							Description: "managed_by",
+							Optional:    true,
+						},
+						"planned_capacity": schema.StringAttribute{
+							Description: "planned_capacity",
+							Optional:    true,
+						},
+						"requested_nominal_psd_offset": schema.StringAttribute{
+							Description: "requested_nominal_psd_offset",
+							Optional:    true,
+						},
+						"fec_iterations": schema.StringAttribute{
+							Description: "fec_iterations",
+							Optional:    true,
+						},
+						"tx_clp_target": schema.Int64Attribute{
+							Description: "tx_clp_target",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"nc_profiles": schema.MapNestedAttribute{
+				Description: "network connection profiles",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"service_mode": schema.StringAttribute{
+							Description: "service_mode",
+							Optional:    true,
+						},
+						"mc": schema.StringAttribute{
+							Description: "mc",
+							Optional:    true,
+						},
+						"outer_vid": schema.StringAttribute{
+							Description: "outer_vid",
+							Optional:    true,
+						},
+						"implicit_transport_capacity": schema.StringAttribute{
+							Description: "implicit_transport_capacity",
+							Optional:    true,
+						},
+						"labels": schema.MapAttribute{
+							Description: "labels",
+							Optional:    true,
+							ElementType: types.StringType,
+						},
+						"endpoint_capacity": schema.Int64Attribute{
+							Description: "capacity of the endpoints which do not specify one",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"tc_profiles": schema.MapNestedAttribute{
+				Description: "transport capacity profiles",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"capacity_mode": schema.StringAttribute{
+							Description: "capacity_mode",
+							Optional:    true,
+						},
+						"labels": schema.MapAttribute{
+							Description: "labels",
+							Optional:    true,
+							ElementType: types.StringType,
+						},
+						"endpoint_capacity": schema.Int64Attribute{
+							Description: "capacity of the endpoints which do not specify one",
+							Optional:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
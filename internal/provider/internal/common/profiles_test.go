@@ -0,0 +1,90 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestProfilesValidate(t *testing.T) {
+	root := path.Root("profiles")
+	validNetwork := NetworkProfile{
+		NetworkConfigProfile: types.StringValue("p2mp"),
+		HubConfigProfile:     types.StringValue("hub"),
+		LeafConfigProfile:    types.StringNull(),
+	}
+	tests := []struct {
+		name     string
+		profiles Profiles
+		errors   []path.Path
+	}{
+		{"empty", Profiles{}, nil},
+		{"valid", Profiles{
+			NetworkProfiles:       map[string]NetworkProfile{"north": validNetwork},
+			NetworkConfigProfiles: map[string]NetworkConfigProfile{"p2mp": {}},
+			ModuleConfigProfiles:  map[string]ModuleConfigProfile{"hub": {}},
+			NCProfiles:            map[string]NCProfile{"nc": {EndpointCapacity: types.Int64Value(100)}},
+			TCProfiles:            map[string]TCProfile{"tc": {EndpointCapacity: types.Int64Null()}},
+		}, nil},
+		{"undefined references", Profiles{
+			NetworkProfiles: map[string]NetworkProfile{"north": {
+				NetworkConfigProfile: types.StringValue("p2mp"),
+				HubConfigProfile:     types.StringUnknown(),
+				LeafConfigProfile:    types.StringValue("leaf"),
+			}},
+		}, []path.Path{
+			root.AtName("network_profiles").AtMapKey("north").AtName("network_config_profile"),
+			root.AtName("network_profiles").AtMapKey("north").AtName("leaf_config_profile"),
+		}},
+		{"invalid endpoint capacities", Profiles{
+			NCProfiles: map[string]NCProfile{"nc": {EndpointCapacity: types.Int64Value(0)}},
+			TCProfiles: map[string]TCProfile{"tc": {EndpointCapacity: types.Int64Value(-100)}},
+		}, []path.Path{
+			root.AtName("nc_profiles").AtMapKey("nc").AtName("endpoint_capacity"),
+			root.AtName("tc_profiles").AtMapKey("tc").AtName("endpoint_capacity"),
+		}},
+	}
+	for _, test := range tests {
+		diags := test.profiles.Validate(root)
+		if diags.ErrorsCount() != len(test.errors) {
+			t.Errorf("%s: Validate() = %v, want %d errors", test.name, diags, len(test.errors))
+			continue
+		}
+		for i, d := range diags.Errors() {
+			withPath, ok := d.(interface{ Path() path.Path })
+			if !ok || !withPath.Path().Equal(test.errors[i]) {
+				t.Errorf("%s: error %d %v is not attached to %s", test.name, i, d, test.errors[i])
+			}
+		}
+	}
+}
+
+func TestMergeProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		request map[string]interface{}
+		profile map[string]interface{}
+		merged  map[string]interface{}
+	}{
+		{"empty profile",
+			map[string]interface{}{"mc": "matchAll"},
+			map[string]interface{}{},
+			map[string]interface{}{"mc": "matchAll"}},
+		{"adds missing settings",
+			map[string]interface{}{"mc": "matchAll"},
+			map[string]interface{}{"serviceMode": "XR-L1"},
+			map[string]interface{}{"mc": "matchAll", "serviceMode": "XR-L1"}},
+		{"keeps request settings",
+			map[string]interface{}{"mc": "matchAll", "labels": map[string]string{"site": "north"}},
+			map[string]interface{}{"mc": "matchOuterVID", "labels": map[string]string{"tier": "gold"}},
+			map[string]interface{}{"mc": "matchAll", "labels": map[string]string{"site": "north"}}},
+	}
+	for _, test := range tests {
+		MergeProfile(test.request, test.profile)
+		if !reflect.DeepEqual(test.request, test.merged) {
+			t.Errorf("%s: MergeProfile() = %v, want %v", test.name, test.request, test.merged)
+		}
+	}
+}
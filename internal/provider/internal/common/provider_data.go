@@ -0,0 +1,11 @@
+package common
+
+import (
+	"terraform-provider-ipm/internal/ipm_pf"
+)
+
+// ProviderData is what the configured provider passes to the Configure method of the resources, data sources and actions.
+type ProviderData struct {
+	Client   *ipm_pf.Client
+	Profiles *Profiles
+}
@@ -0,0 +1,387 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ResolvedIf is the module, and the client interface for interface and host port selectors, selected by a selector.
+type ResolvedIf struct {
+	ModuleId     string
+	ModuleName   string
+	SerialNumber string
+	MACAddress   string
+	ClientIfAid  string
+	HostName     string
+	HostPortName string
+	// constellation membership of the module, empty when the module is not in a constellation
+	NetworkId      string
+	NetworkName    string
+	Role           string
+	LifecycleState string
+}
+
+func (r *ResolvedIf) String() string {
+	s := "module " + r.ModuleName
+	if r.ClientIfAid != "" {
+		s = "client interface " + r.ClientIfAid + " of " + s
+	}
+	if r.HostName != "" {
+		s = s + " (host " + r.HostName + " port " + r.HostPortName + ")"
+	}
+	return s
+}
+
+// ConstellationMember is the hub or leaf of a constellation network.
+type ConstellationMember struct {
+	NetworkId    string
+	NetworkName  string
+	NetworkState string
+	Modulation   string
+	Role         string
+	Data         map[string]interface{}
+}
+
+// SelectorResolver resolves module and module interface selectors to modules and client interfaces.
+// The modules, hosts and constellations read from IPM are cached for the life of the resolver.
+type SelectorResolver struct {
+	client   *ipm_pf.Client
+	modules  map[string]map[string]interface{}
+	hosts    []interface{}
+	networks []interface{}
+}
+
+// NewSelectorResolver is a helper function to create a resolver for the duration of an operation.
+func NewSelectorResolver(client *ipm_pf.Client) *SelectorResolver {
+	return &SelectorResolver{client: client, modules: map[string]map[string]interface{}{}}
+}
+
+// IfSelectorValues returns the IPM name of the selector variant, and its values by IPM attribute name.
+// The variant is empty when no selector is set.
+func IfSelectorValues(selector *IfSelector) (string, map[string]types.String) {
+	switch {
+	case selector.ModuleIfSelectorByModuleId != nil:
+		return "moduleIfSelectorByModuleId", map[string]types.String{
+			"moduleId":          selector.ModuleIfSelectorByModuleId.ModuleId,
+			"moduleClientIfAid": selector.ModuleIfSelectorByModuleId.ModuleClientIfAid,
+		}
+	case selector.ModuleIfSelectorByModuleName != nil:
+		return "moduleIfSelectorByModuleName", map[string]types.String{
+			"moduleName":        selector.ModuleIfSelectorByModuleName.ModuleName,
+			"moduleClientIfAid": selector.ModuleIfSelectorByModuleName.ModuleClientIfAid,
+		}
+	case selector.ModuleIfSelectorByModuleMAC != nil:
+		return "moduleIfSelectorByModuleMAC", map[string]types.String{
+			"moduleMAC":         selector.ModuleIfSelectorByModuleMAC.ModuleMAC,
+			"moduleClientIfAid": selector.ModuleIfSelectorByModuleMAC.ModuleClientIfAid,
+		}
+	case selector.ModuleIfSelectorByModuleSerialNumber != nil:
+		return "moduleIfSelectorByModuleSerialNumber", map[string]types.String{
+			"moduleSerialNumber": selector.ModuleIfSelectorByModuleSerialNumber.ModuleSerialNumber,
+			"moduleClientIfAid":  selector.ModuleIfSelectorByModuleSerialNumber.ModuleClientIfAid,
+		}
+	}
+	return HostPortSelectorValues(selector.HostPortSelectorByName, selector.HostPortSelectorByPortId, selector.HostPortSelectorBySysName, selector.HostPortSelectorByPortSourceMAC)
+}
+
+// ModuleSelectorValues returns the IPM name of the module selector variant, and its values by IPM attribute name.
+func ModuleSelectorValues(byModuleId *ModuleSelectorByModuleId, byModuleName *ModuleSelectorByModuleName, byModuleMAC *ModuleSelectorByModuleMAC, byModuleSerialNumber *ModuleSelectorByModuleSerialNumber,
+	byName *HostPortSelectorByName, byPortId *HostPortSelectorByPortId, bySysName *HostPortSelectorBySysName, byPortSourceMAC *HostPortSelectorByPortSourceMAC) (string, map[string]types.String) {
+	switch {
+	case byModuleId != nil:
+		return "moduleSelectorByModuleId", map[string]types.String{"moduleId": byModuleId.ModuleId}
+	case byModuleName != nil:
+		return "moduleSelectorByModuleName", map[string]types.String{"moduleName": byModuleName.ModuleName}
+	case byModuleMAC != nil:
+		return "moduleSelectorByModuleMAC", map[string]types.String{"moduleMAC": byModuleMAC.ModuleMAC}
+	case byModuleSerialNumber != nil:
+		return "moduleSelectorByModuleSerialNumber", map[string]types.String{"moduleSerialNumber": byModuleSerialNumber.ModuleSerialNumber}
+	}
+	return HostPortSelectorValues(byName, byPortId, bySysName, byPortSourceMAC)
+}
+
+// HostPortSelectorValues returns the IPM name of the host port selector variant, and its values by IPM attribute name.
+func HostPortSelectorValues(byName *HostPortSelectorByName, byPortId *HostPortSelectorByPortId, bySysName *HostPortSelectorBySysName, byPortSourceMAC *HostPortSelectorByPortSourceMAC) (string, map[string]types.String) {
+	switch {
+	case byName != nil:
+		return "hostPortSelectorByName", map[string]types.String{
+			"hostName":     byName.HostName,
+			"hostPortName": byName.HostPortName,
+		}
+	case byPortId != nil:
+		return "hostPortSelectorByPortId", map[string]types.String{
+			"chassisIdSubtype": byPortId.ChassisIdSubtype,
+			"chassisId":        byPortId.ChassisId,
+			"portIdSubtype":    byPortId.PortIdSubtype,
+			"portId":           byPortId.PortId,
+		}
+	case bySysName != nil:
+		return "hostPortSelectorBySysName", map[string]types.String{
+			"sysName":       bySysName.SysName,
+			"portIdSubtype": bySysName.PortIdSubtype,
+			"portId":        bySysName.PortId,
+		}
+	case byPortSourceMAC != nil:
+		return "hostPortSelectorByPortSourceMAC", map[string]types.String{
+			"portSourceMAC": byPortSourceMAC.PortSourceMAC,
+		}
+	}
+	return "", nil
+}
+
+// SelectorKnown tells whether a selector is set and all its values are known.
+func SelectorKnown(variant string, values map[string]types.String) bool {
+	if variant == "" {
+		return false
+	}
+	for _, v := range values {
+		if v.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
+
+// IfSelectorKnown tells whether an interface selector is set and all its values are known.
+func IfSelectorKnown(selector *IfSelector) bool {
+	return SelectorKnown(IfSelectorValues(selector))
+}
+
+// SelectorString returns the selector as text for the diagnostics, e.g. moduleIfSelectorByModuleName{moduleClientIfAid=XR-T1, moduleName=HUB}.
+func SelectorString(variant string, values map[string]types.String) string {
+	items := []string{}
+	for k, v := range values {
+		items = append(items, k+"="+v.ValueString())
+	}
+	sort.Strings(items)
+	return variant + "{" + strings.Join(items, ", ") + "}"
+}
+
+// SelectorRequest returns the selector of an IPM request, e.g. {"moduleSelectorByModuleName": {"moduleName": "HUB"}}.
+func SelectorRequest(variant string, values map[string]types.String) map[string]interface{} {
+	aSelector := make(map[string]interface{})
+	for k, v := range values {
+		if !v.IsNull() {
+			aSelector[k] = v.ValueString()
+		}
+	}
+	return map[string]interface{}{variant: aSelector}
+}
+
+// ResolveIfSelector returns the module client interface selected by the interface selector.
+func (r *SelectorResolver) ResolveIfSelector(selector *IfSelector) (*ResolvedIf, error) {
+	return r.Resolve(IfSelectorValues(selector))
+}
+
+// Resolve returns the module, and the client interface for interface and host port selectors, selected by the selector. Module selectors are
+// resolved through /modules, host port selectors through the module interfaces which LLDP found on the ports of /hosts.
+func (r *SelectorResolver) Resolve(variant string, values map[string]types.String) (*ResolvedIf, error) {
+	if !SelectorKnown(variant, values) {
+		return nil, errors.New("the selector is not specified or not known")
+	}
+	resolved := &ResolvedIf{}
+	if strings.HasPrefix(variant, "module") {
+		var queryString string
+		switch {
+		case !values["moduleId"].IsNull():
+			queryString = "/modules/" + values["moduleId"].ValueString() + "?content=expanded"
+		case !values["moduleName"].IsNull():
+			queryString = "/modules?content=expanded&q={\"state.moduleName\":\"" + values["moduleName"].ValueString() + "\"}"
+		case !values["moduleMAC"].IsNull():
+			queryString = "/modules?content=expanded&q={\"state.hwDescription.macAddress\":\"" + values["moduleMAC"].ValueString() + "\"}"
+		case !values["moduleSerialNumber"].IsNull():
+			queryString = "/modules?content=expanded&q={\"state.hwDescription.serialNumber\":\"" + values["moduleSerialNumber"].ValueString() + "\"}"
+		default:
+			return nil, errors.New("the selector " + SelectorString(variant, values) + " has no module value")
+		}
+		module, err := GetResource(r.client, queryString)
+		if err != nil {
+			return nil, errors.New("no module matches " + SelectorString(variant, values))
+		}
+		resolved.ModuleId, _ = module["id"].(string)
+		r.modules[resolved.ModuleId] = module
+		resolved.ClientIfAid = values["moduleClientIfAid"].ValueString()
+	} else {
+		port, host, err := r.findHostPort(variant, values)
+		if err != nil {
+			return nil, err
+		}
+		resolved.HostName = hostValue(host, "name")
+		resolved.HostPortName = hostValue(port, "name")
+		resolved.ModuleId, _ = LookupValue(port, "state.moduleIf.moduleId")
+		if resolved.ModuleId == "" {
+			return nil, errors.New("port " + resolved.HostPortName + " of host " + resolved.HostName + " is not connected to a module")
+		}
+		resolved.ClientIfAid, _ = LookupValue(port, "state.moduleIf.clientIfAid")
+	}
+
+	module, err := r.module(resolved.ModuleId)
+	if err != nil {
+		return nil, err
+	}
+	resolved.ModuleName, _ = LookupValue(module, "state.moduleName")
+	resolved.MACAddress, _ = LookupValue(module, "state.hwDescription.macAddress")
+	resolved.SerialNumber, _ = LookupValue(module, "state.hwDescription.serialNumber")
+
+	member, err := r.ConstellationMember(resolved.ModuleId)
+	if err != nil {
+		return nil, err
+	}
+	if member != nil {
+		resolved.NetworkId = member.NetworkId
+		resolved.NetworkName = member.NetworkName
+		resolved.Role = member.Role
+		resolved.LifecycleState, _ = LookupValue(member.Data, "state.lifecycleState")
+	}
+	return resolved, nil
+}
+
+// ConstellationMember returns the hub or leaf of a constellation which is the module, or nil.
+func (r *SelectorResolver) ConstellationMember(moduleId string) (*ConstellationMember, error) {
+	if r.networks == nil {
+		networks, err := GetResources(r.client, "/xr-networks?content=expanded")
+		if err != nil {
+			return nil, err
+		}
+		r.networks = networks
+	}
+	for _, n := range r.networks {
+		network, _ := n.(map[string]interface{})
+		members := []interface{}{network["hubModule"]}
+		leaves, _ := network["leafModules"].([]interface{})
+		members = append(members, leaves...)
+		for i, m := range members {
+			data, _ := m.(map[string]interface{})
+			if id, _ := LookupValue(data, "state.module.moduleId"); data == nil || id != moduleId {
+				continue
+			}
+			member := &ConstellationMember{Role: "leaf", Data: data}
+			if i == 0 {
+				member.Role = "hub"
+			}
+			member.NetworkId, _ = network["id"].(string)
+			member.NetworkName, _ = LookupValue(network, "config.name")
+			member.NetworkState, _ = LookupValue(network, "state.lifecycleState")
+			if member.Modulation, _ = LookupValue(network, "state.modulation"); member.Modulation == "" {
+				member.Modulation, _ = LookupValue(network, "config.modulation")
+			}
+			return member, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *SelectorResolver) module(moduleId string) (map[string]interface{}, error) {
+	if module, ok := r.modules[moduleId]; ok {
+		return module, nil
+	}
+	module, err := GetResource(r.client, "/modules/"+moduleId+"?content=expanded")
+	if err != nil {
+		return nil, errors.New("module " + moduleId + " is not found")
+	}
+	r.modules[moduleId] = module
+	return module, nil
+}
+
+// findHostPort returns the host port selected by the host port selector, and its host.
+func (r *SelectorResolver) findHostPort(variant string, values map[string]types.String) (map[string]interface{}, map[string]interface{}, error) {
+	if r.hosts == nil {
+		hosts, err := GetResources(r.client, "/hosts?content=expanded")
+		if err != nil {
+			return nil, nil, err
+		}
+		r.hosts = hosts
+	}
+	for _, h := range r.hosts {
+		host, _ := h.(map[string]interface{})
+		ports, _ := host["ports"].([]interface{})
+		for _, p := range ports {
+			port, _ := p.(map[string]interface{})
+			if hostPortMatches(host, port, variant, values) {
+				return port, host, nil
+			}
+		}
+	}
+	return nil, nil, errors.New("no host port matches " + SelectorString(variant, values))
+}
+
+// hostPortMatches tells whether the host port is selected by the host port selector.
+func hostPortMatches(host map[string]interface{}, port map[string]interface{}, variant string, values map[string]types.String) bool {
+	switch variant {
+	case "hostPortSelectorByName":
+		return hostValue(host, "name") == values["hostName"].ValueString() && hostValue(port, "name") == values["hostPortName"].ValueString()
+	case "hostPortSelectorByPortId":
+		return hostValue(host, "chassisId") == values["chassisId"].ValueString() && hostValue(host, "chassisIdSubtype") == values["chassisIdSubtype"].ValueString() &&
+			hostValue(port, "portId") == values["portId"].ValueString() && hostValue(port, "portIdSubtype") == values["portIdSubtype"].ValueString()
+	case "hostPortSelectorBySysName":
+		return hostValue(host, "sysName") == values["sysName"].ValueString() &&
+			hostValue(port, "portId") == values["portId"].ValueString() && hostValue(port, "portIdSubtype") == values["portIdSubtype"].ValueString()
+	case "hostPortSelectorByPortSourceMAC":
+		return hostValue(port, "portSourceMAC") == values["portSourceMAC"].ValueString()
+	}
+	return false
+}
+
+// hostValue returns the value of a host or host port, from its state or else its config.
+func hostValue(data map[string]interface{}, key string) string {
+	if value, found := LookupValue(data, "state."+key); found {
+		return value
+	}
+	value, _ := LookupValue(data, "config."+key)
+	return value
+}
+
+// CheckMembersConfigured checks that the selectors resolve to members of the same constellation, which is configured or
+// pending configuration, and that the leaves among them are configured. It retries every 15 seconds while a member is not configured.
+func CheckMembersConfigured(ctx context.Context, client *ipm_pf.Client, selectors []*IfSelector, tryCount ...int) error {
+	numRetry := 1
+	if len(tryCount) > 0 {
+		numRetry = tryCount[0]
+	}
+	var err error
+	for i := 1; i <= numRetry; i++ {
+		err = checkMembersConfigured(NewSelectorResolver(client), selectors)
+		if err == nil {
+			return nil
+		}
+		tflog.Debug(ctx, "CheckMembersConfigured: not configured ", map[string]interface{}{"try": i, "cause": err.Error()})
+		if i < numRetry {
+			time.Sleep(15 * time.Second)
+		}
+	}
+	return err
+}
+
+func checkMembersConfigured(resolver *SelectorResolver, selectors []*IfSelector) error {
+	networkId := ""
+	for i, selector := range selectors {
+		resolved, err := resolver.ResolveIfSelector(selector)
+		if err != nil {
+			return errors.New("endpoint " + strconv.Itoa(i) + ": " + err.Error())
+		}
+		if resolved.NetworkId == "" {
+			return errors.New(resolved.String() + " is neither the hub nor a leaf of a constellation")
+		}
+		if networkId != "" && networkId != resolved.NetworkId {
+			return errors.New("the endpoints are in different constellations")
+		}
+		networkId = resolved.NetworkId
+		member, _ := resolver.ConstellationMember(resolved.ModuleId)
+		if member.NetworkState != "configured" && member.NetworkState != "pendingConfiguration" {
+			return errors.New("constellation " + member.NetworkName + " is " + member.NetworkState)
+		}
+		if resolved.Role == "leaf" && resolved.LifecycleState != "configured" {
+			return errors.New("leaf " + resolved.String() + " of constellation " + member.NetworkName + " is " + resolved.LifecycleState)
+		}
+	}
+	return nil
+}
@@ -273,3 +273,11 @@ func GetId(s string, pattern string) string {
 	}
 	return ""
 }
+
+// OptionalString returns the value, or null when it is empty.
+func OptionalString(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
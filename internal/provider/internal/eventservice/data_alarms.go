@@ -0,0 +1,164 @@
+package eventservice
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &AlarmsDataSource{}
+	_ datasource.DataSourceWithConfigure = &AlarmsDataSource{}
+)
+
+// NewAlarmsDataSource is a helper function to simplify the provider implementation.
+func NewAlarmsDataSource() datasource.DataSource {
+	return &AlarmsDataSource{}
+}
+
+// AlarmsDataSource queries the active alarms, and optionally the alarm history, of IPM.
+type AlarmsDataSource struct {
+	client *ipm_pf.Client
+}
+
+type AlarmsDataSourceData struct {
+	DeviceId       types.String   `tfsdk:"device_id"`
+	Resource       types.String   `tfsdk:"resource"`
+	Severities     []types.String `tfsdk:"severities"`
+	ProbableCause  types.String   `tfsdk:"probable_cause"`
+	StartTime      types.String   `tfsdk:"start_time"`
+	EndTime        types.String   `tfsdk:"end_time"`
+	IncludeHistory types.Bool     `tfsdk:"include_history"`
+	AlarmCount     types.Int64    `tfsdk:"alarm_count"`
+	Alarms         types.List     `tfsdk:"alarms"`
+}
+
+// Metadata returns the data source type name.
+func (r *AlarmsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_alarms"
+}
+
+func (d *AlarmsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the active alarms, and optionally the alarm history, filtered by device, resource, severity, probable cause and time window",
+		Attributes: map[string]schema.Attribute{
+			"device_id": schema.StringAttribute{
+				Description: "ID of the module or NDU which raised the alarms",
+				Optional:    true,
+			},
+			"resource": schema.StringAttribute{
+				Description: "href of the resource the alarms are raised on, which also matches the alarms of its children",
+				Optional:    true,
+			},
+			"severities": schema.ListAttribute{
+				Description: "severities of the alarms, such as critical, major, minor or warning",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"probable_cause": schema.StringAttribute{
+				Description: "probable cause of the alarms",
+				Optional:    true,
+			},
+			"start_time": schema.StringAttribute{
+				Description: "alarms raised at or after this time, in RFC 3339 format",
+				Optional:    true,
+			},
+			"end_time": schema.StringAttribute{
+				Description: "alarms raised at or before this time, in RFC 3339 format",
+				Optional:    true,
+			},
+			"include_history": schema.BoolAttribute{
+				Description: "whether the cleared alarms of the alarm history are included",
+				Optional:    true,
+			},
+			"alarm_count": schema.Int64Attribute{
+				Description: "number of alarms found",
+				Computed:    true,
+			},
+			"alarms": schema.ListAttribute{
+				Description: "alarms found, the most recently raised first and the alarms without a raised time last",
+				Computed:    true,
+				ElementType: common.AlarmObjectType(),
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *AlarmsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+func (d *AlarmsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	query := AlarmsDataSourceData{}
+	diags := req.Config.Get(ctx, &query)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Debug(ctx, "AlarmsDataSource: get alarms", map[string]interface{}{"query": query})
+
+	filter := common.AlarmFilter{
+		DeviceId:      query.DeviceId.ValueString(),
+		Resource:      query.Resource.ValueString(),
+		ProbableCause: query.ProbableCause.ValueString(),
+		Severities:    common.ListValue(query.Severities),
+		History:       query.IncludeHistory.ValueBool(),
+	}
+	var err error
+	if !query.StartTime.IsNull() {
+		filter.StartTime, err = time.Parse(time.RFC3339, query.StartTime.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("start_time"), "AlarmsDataSource: Invalid start_time", "The time is not in RFC 3339 format: "+err.Error())
+			return
+		}
+	}
+	if !query.EndTime.IsNull() {
+		filter.EndTime, err = time.Parse(time.RFC3339, query.EndTime.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("end_time"), "AlarmsDataSource: Invalid end_time", "The time is not in RFC 3339 format: "+err.Error())
+			return
+		}
+	}
+
+	alarms, err := common.GetAlarms(d.client, filter)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"AlarmsDataSource: read ##: Error Get alarms",
+			"Get:Could not get the alarms, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	if !filter.StartTime.IsZero() || !filter.EndTime.IsZero() {
+		unparsed := []string{}
+		for _, alarm := range alarms {
+			if alarm.Raised.IsZero() {
+				unparsed = append(unparsed, alarm.Id)
+			}
+		}
+		if len(unparsed) > 0 {
+			resp.Diagnostics.AddWarning("AlarmsDataSource: Alarms without raised time",
+				"The raised time of the alarms "+strings.Join(unparsed, ", ")+" is not in RFC 3339 format, they are listed last whatever start_time and end_time.")
+		}
+	}
+	query.AlarmCount = types.Int64Value(int64(len(alarms)))
+	query.Alarms = types.ListValueMust(common.AlarmObjectType(), common.AlarmObjectsValue(alarms))
+
+	diags = resp.State.Set(ctx, query)
+	resp.Diagnostics.Append(diags...)
+	tflog.Debug(ctx, "AlarmsDataSource: get ", map[string]interface{}{"count": len(alarms)})
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -71,7 +72,7 @@ func (d *FoundEventsDataSource) Configure(_ context.Context, req datasource.Conf
 		return
 	}
 
-	d.client = req.ProviderData.(*ipm_pf.Client)
+	d.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (d *FoundEventsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
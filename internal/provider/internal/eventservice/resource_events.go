@@ -77,7 +77,7 @@ func (r *EventResource) Configure(_ context.Context, req resource.ConfigureReque
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r EventResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -0,0 +1,256 @@
+package host
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &HostDiscoveryDataSource{}
+	_ datasource.DataSourceWithConfigure = &HostDiscoveryDataSource{}
+)
+
+// NewHostDiscoveryDataSource is a helper function to simplify the provider implementation.
+func NewHostDiscoveryDataSource() datasource.DataSource {
+	return &HostDiscoveryDataSource{}
+}
+
+// HostDiscoveryDataSource lists the LLDP neighbors learned by the ethernet clients of the modules.
+type HostDiscoveryDataSource struct {
+	client *ipm_pf.Client
+}
+
+type HostDiscoveryDataSourceData struct {
+	ModuleLabels    types.Map    `tfsdk:"module_labels"`
+	ModuleNameRegex types.String `tfsdk:"module_name_regex"`
+	Neighbors       types.List   `tfsdk:"neighbors"`
+}
+
+// lldpNeighbor is a host port learned by LLDP on an ethernet client of a module.
+type lldpNeighbor struct {
+	moduleId         string
+	moduleName       string
+	clientHref       string
+	clientIfAid      string
+	chassisIdSubtype string
+	chassisId        string
+	sysName          string
+	sysDescr         string
+	portIdSubtype    string
+	portId           string
+	portDescr        string
+	portSourceMAC    string
+	sysTTL           string
+}
+
+// Metadata returns the data source type name.
+func (r *HostDiscoveryDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_host_discovery"
+}
+
+func (d *HostDiscoveryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the LLDP neighbors learned by the ethernet clients of the modules. The host database of a module is cleared with the flushLldpHostDb action.",
+		Attributes: map[string]schema.Attribute{
+			"module_labels": schema.MapAttribute{
+				Description: "labels the modules must have",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"module_name_regex": schema.StringAttribute{
+				Description: "regular expression the module names must match",
+				Optional:    true,
+			},
+			"neighbors": schema.ListAttribute{
+				Description: "the LLDP neighbors, by module name and client interface",
+				Computed:    true,
+				ElementType: LLDPNeighborObjectType(),
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *HostDiscoveryDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+func (d *HostDiscoveryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	query := HostDiscoveryDataSourceData{}
+	diags := req.Config.Get(ctx, &query)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Debug(ctx, "HostDiscoveryDataSource: get neighbors", map[string]interface{}{"module_labels": query.ModuleLabels, "module_name_regex": query.ModuleNameRegex})
+
+	var nameRegex *regexp.Regexp
+	if !query.ModuleNameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(query.ModuleNameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("module_name_regex"), "HostDiscoveryDataSource: Invalid module_name_regex", err.Error())
+			return
+		}
+	}
+	labels := make(map[string]string)
+	if !query.ModuleLabels.IsNull() {
+		resp.Diagnostics.Append(query.ModuleLabels.ElementsAs(ctx, &labels, false)...)
+	}
+
+	neighbors, err := discoverNeighbors(d.client, labels, nameRegex)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"HostDiscoveryDataSource: read ##: Error Get LLDP neighbors",
+			"Get:Could not get the LLDP neighbors, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	query.Neighbors = types.ListValueMust(LLDPNeighborObjectType(), LLDPNeighborObjectsValue(neighbors))
+
+	diags = resp.State.Set(ctx, query)
+	resp.Diagnostics.Append(diags...)
+	tflog.Debug(ctx, "HostDiscoveryDataSource: get ", map[string]interface{}{"neighbors": len(neighbors)})
+}
+
+// discoverNeighbors returns the LLDP neighbors of the ethernet clients of the modules which have the labels and whose name
+// matches the regular expression, sorted by module name and client interface.
+func discoverNeighbors(client *ipm_pf.Client, labels map[string]string, nameRegex *regexp.Regexp) ([]lldpNeighbor, error) {
+	modules, err := common.GetResources(client, "/modules?content=expanded")
+	if err != nil {
+		return nil, err
+	}
+	neighbors := []lldpNeighbor{}
+	for _, m := range modules {
+		module, ok := m.(map[string]interface{})
+		if !ok || !common.MatchLabels(module, labels) {
+			continue
+		}
+		moduleId, _ := common.LookupValue(module, "id")
+		moduleName, found := common.LookupValue(module, "state.moduleName")
+		if !found {
+			moduleName, _ = common.LookupValue(module, "config.moduleName")
+		}
+		if moduleId == "" || (nameRegex != nil && !nameRegex.MatchString(moduleName)) {
+			continue
+		}
+		clients, err := common.GetResources(client, "/modules/"+moduleId+"/ethernetClients?content=expanded")
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range clients {
+			eClient, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			clientHref, _ := common.LookupValue(eClient, "href")
+			clientIfAid, _ := common.LookupValue(eClient, "state.clientIfAid")
+			lldp, _ := eClient["state"].(map[string]interface{})
+			lldp, _ = lldp["lldp"].(map[string]interface{})
+			learned, _ := lldp["neighbors"].([]interface{})
+			for _, n := range learned {
+				neighbor, ok := n.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				value := func(key string) string {
+					v, _ := common.LookupValue(neighbor, key)
+					return v
+				}
+				neighbors = append(neighbors, lldpNeighbor{
+					moduleId:         moduleId,
+					moduleName:       moduleName,
+					clientHref:       clientHref,
+					clientIfAid:      clientIfAid,
+					chassisIdSubtype: value("chassisIdSubtype"),
+					chassisId:        value("chassisId"),
+					sysName:          value("sysName"),
+					sysDescr:         value("sysDescr"),
+					portIdSubtype:    value("portIdSubtype"),
+					portId:           value("portId"),
+					portDescr:        value("portDescr"),
+					portSourceMAC:    value("portSourceMAC"),
+					sysTTL:           value("sysTTL"),
+				})
+			}
+		}
+	}
+	sort.SliceStable(neighbors, func(i, j int) bool {
+		if neighbors[i].moduleName != neighbors[j].moduleName {
+			return neighbors[i].moduleName < neighbors[j].moduleName
+		}
+		return neighbors[i].clientIfAid < neighbors[j].clientIfAid
+	})
+	return neighbors, nil
+}
+
+func LLDPNeighborObjectType() types.ObjectType {
+	return types.ObjectType{
+		AttrTypes: LLDPNeighborAttributeType(),
+	}
+}
+
+func LLDPNeighborObjectsValue(neighbors []lldpNeighbor) []attr.Value {
+	values := []attr.Value{}
+	for _, neighbor := range neighbors {
+		values = append(values, types.ObjectValueMust(LLDPNeighborAttributeType(), LLDPNeighborAttributeValue(neighbor)))
+	}
+	return values
+}
+
+func LLDPNeighborAttributeType() map[string]attr.Type {
+	return map[string]attr.Type{
+		"module_id":          types.StringType,
+		"module_name":        types.StringType,
+		"client_href":        types.StringType,
+		"client_if_aid":      types.StringType,
+		"chassis_id_subtype": types.StringType,
+		"chassis_id":         types.StringType,
+		"sys_name":           types.StringType,
+		"sys_descr":          types.StringType,
+		"port_id_subtype":    types.StringType,
+		"port_id":            types.StringType,
+		"port_descr":         types.StringType,
+		"port_source_mac":    types.StringType,
+		"sys_ttl":            types.Int64Type,
+	}
+}
+
+func LLDPNeighborAttributeValue(neighbor lldpNeighbor) map[string]attr.Value {
+	sysTTL := types.Int64Null()
+	if ttl, err := strconv.ParseFloat(neighbor.sysTTL, 64); err == nil {
+		sysTTL = types.Int64Value(int64(ttl))
+	}
+	return map[string]attr.Value{
+		"module_id":          types.StringValue(neighbor.moduleId),
+		"module_name":        common.OptionalString(neighbor.moduleName),
+		"client_href":        common.OptionalString(neighbor.clientHref),
+		"client_if_aid":      common.OptionalString(neighbor.clientIfAid),
+		"chassis_id_subtype": common.OptionalString(neighbor.chassisIdSubtype),
+		"chassis_id":         common.OptionalString(neighbor.chassisId),
+		"sys_name":           common.OptionalString(neighbor.sysName),
+		"sys_descr":          common.OptionalString(neighbor.sysDescr),
+		"port_id_subtype":    common.OptionalString(neighbor.portIdSubtype),
+		"port_id":            common.OptionalString(neighbor.portId),
+		"port_descr":         common.OptionalString(neighbor.portDescr),
+		"port_source_mac":    common.OptionalString(neighbor.portSourceMAC),
+		"sys_ttl":            sysTTL,
+	}
+}
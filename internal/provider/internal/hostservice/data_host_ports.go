@@ -6,6 +6,8 @@ import (
 
 	"terraform-provider-ipm/internal/ipm_pf"
 
+	"terraform-provider-ipm/internal/provider/internal/common"
+
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -60,7 +62,7 @@ func (d *HostPortsDataSource) Configure(_ context.Context, req datasource.Config
 		return
 	}
 
-	d.client = req.ProviderData.(*ipm_pf.Client)
+	d.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (d *HostPortsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -7,6 +7,8 @@ import (
 
 	"terraform-provider-ipm/internal/ipm_pf"
 
+	"terraform-provider-ipm/internal/provider/internal/common"
+
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -61,7 +63,7 @@ func (d *HostsDataSource) Configure(_ context.Context, req datasource.ConfigureR
 		return
 	}
 
-	d.client = req.ProviderData.(*ipm_pf.Client)
+	d.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (d *HostsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
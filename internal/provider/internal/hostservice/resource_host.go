@@ -87,7 +87,7 @@ func (r *HostResource) Configure(_ context.Context, req resource.ConfigureReques
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r HostResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -75,7 +75,7 @@ func (r *HostPortResource) Configure(_ context.Context, req resource.ConfigureRe
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r HostPortResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
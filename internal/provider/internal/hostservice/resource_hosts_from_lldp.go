@@ -0,0 +1,813 @@
+package host
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &HostsFromLLDPResource{}
+	_ resource.ResourceWithConfigure      = &HostsFromLLDPResource{}
+	_ resource.ResourceWithValidateConfig = &HostsFromLLDPResource{}
+	_ resource.ResourceWithModifyPlan     = &HostsFromLLDPResource{}
+)
+
+// NewHostsFromLLDPResource is a helper function to simplify the provider implementation.
+func NewHostsFromLLDPResource() resource.Resource {
+	return &HostsFromLLDPResource{}
+}
+
+// HostsFromLLDPResource creates and updates the hosts and host ports learned by LLDP on the ethernet clients of the modules.
+type HostsFromLLDPResource struct {
+	client *ipm_pf.Client
+}
+
+type LLDPHostPort struct {
+	Id            types.String `tfsdk:"id"`
+	Href          types.String `tfsdk:"href"`
+	PortIdSubtype types.String `tfsdk:"port_id_subtype"`
+	PortId        types.String `tfsdk:"port_id"`
+	PortSourceMAC types.String `tfsdk:"port_source_mac"`
+	ModuleName    types.String `tfsdk:"module_name"`
+	ClientIfAid   types.String `tfsdk:"client_if_aid"`
+	InSync        types.Bool   `tfsdk:"in_sync"`
+}
+
+type LLDPHost struct {
+	Id               types.String            `tfsdk:"id"`
+	Href             types.String            `tfsdk:"href"`
+	ChassisIdSubtype types.String            `tfsdk:"chassis_id_subtype"`
+	ChassisId        types.String            `tfsdk:"chassis_id"`
+	SysName          types.String            `tfsdk:"sys_name"`
+	InSync           types.Bool              `tfsdk:"in_sync"`
+	Ports            map[string]LLDPHostPort `tfsdk:"ports"`
+}
+
+type HostsFromLLDPResourceData struct {
+	Id               types.String        `tfsdk:"id"`
+	ModuleLabels     types.Map           `tfsdk:"module_labels"`
+	ModuleNameRegex  types.String        `tfsdk:"module_name_regex"`
+	HostNameTemplate types.String        `tfsdk:"host_name_template"`
+	PortNameTemplate types.String        `tfsdk:"port_name_template"`
+	ManagedBy        types.String        `tfsdk:"managed_by"`
+	Labels           types.Map           `tfsdk:"labels"`
+	Hosts            map[string]LLDPHost `tfsdk:"hosts"`
+}
+
+// lldpHost is a host learned by LLDP, with the IPM host which has its chassis id when there is one.
+type lldpHost struct {
+	key              string
+	chassisIdSubtype string
+	chassisId        string
+	sysName          string
+	id               string
+	href             string
+	labels           map[string]string
+	inSync           bool
+	ports            []lldpHostPort
+}
+
+// lldpHostPort is a host port learned by LLDP, with the IPM host port which has its port id when there is one.
+type lldpHostPort struct {
+	key      string
+	neighbor lldpNeighbor
+	id       string
+	href     string
+	labels   map[string]string
+	inSync   bool
+}
+
+const (
+	defaultHostNameTemplate = "{sys_name}"
+	defaultPortNameTemplate = "{port_id}"
+)
+
+var templatePlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// the placeholders of the host name template. The port name template can use them too.
+var hostPlaceholders = []string{"sys_name", "chassis_id", "chassis_id_subtype"}
+
+// the placeholders of the port name template only
+var portPlaceholders = []string{"port_id", "port_id_subtype", "port_descr", "port_source_mac", "module_name", "client_if_aid"}
+
+// Metadata returns the data source type name.
+func (r *HostsFromLLDPResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hosts_from_lldp"
+}
+
+// Schema defines the schema for the data source.
+func (r *HostsFromLLDPResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Creates and updates a host for each chassis, and a host port for each port, learned by LLDP on the ethernet clients of the selected modules. " +
+			"The hosts and ports are named from templates, and are matched to the existing hosts by chassis id and to the existing ports by port id or port source MAC. " +
+			"The neighbors are read at each refresh, the new or renamed hosts and ports are applied at the next apply. " +
+			"Hosts which are no longer learned, and the hosts on destroy, are left as they are.",
+		Attributes: HostsFromLLDPSchemaAttributes(),
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *HostsFromLLDPResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+// ValidateConfig checks the module name regular expression and the placeholders of the naming templates.
+func (r *HostsFromLLDPResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data HostsFromLLDPResourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !data.ModuleNameRegex.IsNull() && !data.ModuleNameRegex.IsUnknown() {
+		if _, err := regexp.Compile(data.ModuleNameRegex.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("module_name_regex"), "HostsFromLLDPResource: Invalid module_name_regex", err.Error())
+		}
+	}
+	if !data.HostNameTemplate.IsUnknown() {
+		if err := checkTemplate(templateValue(data.HostNameTemplate, defaultHostNameTemplate), hostPlaceholders); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("host_name_template"), "HostsFromLLDPResource: Invalid host_name_template", err.Error())
+		}
+	}
+	if !data.PortNameTemplate.IsUnknown() {
+		if err := checkTemplate(templateValue(data.PortNameTemplate, defaultPortNameTemplate), append(hostPlaceholders, portPlaceholders...)); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("port_name_template"), "HostsFromLLDPResource: Invalid port_name_template", err.Error())
+		}
+	}
+}
+
+func (r *HostsFromLLDPResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data HostsFromLLDPResourceData
+
+	diags := req.Config.Get(ctx, &data)
+
+	tflog.Debug(ctx, "HostsFromLLDPResource: Create - ", map[string]interface{}{"HostsFromLLDPResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(uuid.New().String())
+	r.apply(&data, ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *HostsFromLLDPResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data HostsFromLLDPResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "HostsFromLLDPResource: Read - ", map[string]interface{}{"HostsFromLLDPResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.read(&data, ctx, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *HostsFromLLDPResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan HostsFromLLDPResourceData
+	var state HostsFromLLDPResourceData
+
+	// the planned hosts are unknown, the settings are read from the config
+	diags := req.Config.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Debug(ctx, "HostsFromLLDPResource: Update", map[string]interface{}{"plan": plan, "state": state})
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = state.Id
+	r.apply(&plan, ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *HostsFromLLDPResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data HostsFromLLDPResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "HostsFromLLDPResource: Delete", map[string]interface{}{"HostsFromLLDPResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+// ModifyPlan plans an update when hosts or ports were learned, or when a host or port is not named or labelled as the templates say.
+func (r *HostsFromLLDPResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() || r.client == nil {
+		return
+	}
+	var plan HostsFromLLDPResourceData
+	if diags := req.Config.Get(ctx, &plan); diags.HasError() {
+		return
+	}
+	// the neighbors are evaluated at apply when the settings are computed by other resources
+	if plan.ModuleLabels.IsUnknown() || plan.ModuleNameRegex.IsUnknown() || plan.HostNameTemplate.IsUnknown() || plan.PortNameTemplate.IsUnknown() ||
+		plan.ManagedBy.IsUnknown() || plan.Labels.IsUnknown() {
+		return
+	}
+	var state HostsFromLLDPResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hosts, collisions, err := r.evaluate(&plan, ctx)
+	if err != nil {
+		resp.Diagnostics.AddWarning("HostsFromLLDPResource: Hosts not evaluated", "Could not evaluate the LLDP neighbors: "+err.Error())
+		return
+	}
+	addCollisionWarnings(collisions, &resp.Diagnostics)
+	changed := len(hosts) != len(state.Hosts)
+	for _, host := range hosts {
+		stateHost, ok := state.Hosts[host.key]
+		if !ok || host.id == "" || !host.inSync || len(host.ports) != len(stateHost.Ports) {
+			changed = true
+			continue
+		}
+		for _, port := range host.ports {
+			if _, ok := stateHost.Ports[port.key]; !ok || port.id == "" || !port.inSync {
+				changed = true
+			}
+		}
+	}
+	tflog.Debug(ctx, "HostsFromLLDPResource: ModifyPlan ## ", map[string]interface{}{"hosts": len(hosts), "changed": changed})
+	if changed {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("hosts"), types.MapUnknown(types.ObjectType{AttrTypes: lldpHostAttributeType()}))...)
+	}
+}
+
+// apply creates the learned hosts and ports which are not in IPM, and updates the ones which are not named or labelled as the templates say.
+func (r *HostsFromLLDPResource) apply(plan *HostsFromLLDPResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	hosts, collisions, err := r.evaluate(plan, ctx)
+	if err != nil {
+		diags.AddError(
+			"HostsFromLLDPResource: apply ##: Error Evaluate LLDP neighbors",
+			"Apply: Could not evaluate the LLDP neighbors, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	addCollisionWarnings(collisions, diags)
+
+	hostResource := HostResource{client: r.client}
+	hostPortResource := HostPortResource{client: r.client}
+	for _, host := range hosts {
+		hostData := HostResourceData{
+			Config: &HostConfig{
+				Name:      types.StringValue(host.key),
+				ManagedBy: plan.ManagedBy,
+				Labels:    labelsValue(host.labels),
+			},
+		}
+		if host.id == "" {
+			tflog.Debug(ctx, "HostsFromLLDPResource: apply ## create host", map[string]interface{}{"host": host.key, "chassisId": host.chassisId})
+			hostData.Config.Selector.HostSelectorByHostChassisId = &common.HostSelectorByHostChassisId{
+				ChassisIdSubtype: types.StringValue(host.chassisIdSubtype),
+				ChassisId:        types.StringValue(host.chassisId),
+			}
+			hostResource.create(&hostData, ctx, diags)
+		} else if !host.inSync {
+			tflog.Debug(ctx, "HostsFromLLDPResource: apply ## update host", map[string]interface{}{"host": host.key, "id": host.id})
+			hostData.Id = types.StringValue(host.id)
+			hostResource.update(&hostData, ctx, diags)
+		} else {
+			hostData.Id = types.StringValue(host.id)
+		}
+		if diags.HasError() {
+			return
+		}
+
+		for _, port := range host.ports {
+			portData := HostPortResourceData{
+				HostId: hostData.Id,
+				Config: HostPortConfig{
+					Name:      types.StringValue(port.key),
+					ManagedBy: plan.ManagedBy,
+					Labels:    labelsValue(port.labels),
+				},
+			}
+			if port.id == "" {
+				tflog.Debug(ctx, "HostsFromLLDPResource: apply ## create host port", map[string]interface{}{"host": host.key, "port": port.key})
+				portData.Config.Selector = port.neighbor.selector()
+				hostPortResource.create(&portData, ctx, diags)
+			} else if !port.inSync {
+				tflog.Debug(ctx, "HostsFromLLDPResource: apply ## update host port", map[string]interface{}{"host": host.key, "port": port.key, "id": port.id})
+				portData.Id = types.StringValue(port.id)
+				hostPortResource.update(&portData, ctx, diags)
+			}
+			if diags.HasError() {
+				return
+			}
+		}
+	}
+
+	r.read(plan, ctx, diags)
+}
+
+// evaluate returns the hosts learned by LLDP, sorted by name, with the IPM hosts and ports which have their chassis and port ids.
+// A host or port whose rendered name is already taken is named by its chassis or port id instead, the collisions are returned.
+func (r *HostsFromLLDPResource) evaluate(plan *HostsFromLLDPResourceData, ctx context.Context) ([]lldpHost, []string, error) {
+	var nameRegex *regexp.Regexp
+	if !plan.ModuleNameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(plan.ModuleNameRegex.ValueString())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	moduleLabels := make(map[string]string)
+	if !plan.ModuleLabels.IsNull() {
+		plan.ModuleLabels.ElementsAs(ctx, &moduleLabels, false)
+	}
+	labels := make(map[string]string)
+	if !plan.Labels.IsNull() {
+		plan.Labels.ElementsAs(ctx, &labels, false)
+	}
+	hostTemplate := templateValue(plan.HostNameTemplate, defaultHostNameTemplate)
+	portTemplate := templateValue(plan.PortNameTemplate, defaultPortNameTemplate)
+
+	neighbors, err := discoverNeighbors(r.client, moduleLabels, nameRegex)
+	if err != nil {
+		return nil, nil, err
+	}
+	existingHosts, err := common.GetResources(r.client, "/hosts?content=expanded")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hosts := []*lldpHost{}
+	byChassis := make(map[string]*lldpHost)
+	chassisByName := make(map[string]string)
+	collisions := []string{}
+	for _, neighbor := range neighbors {
+		if neighbor.chassisId == "" || (neighbor.portId == "" && neighbor.portSourceMAC == "") {
+			continue
+		}
+		host, ok := byChassis[neighbor.chassisIdSubtype+"/"+neighbor.chassisId]
+		if !ok {
+			host = &lldpHost{
+				key:              renderTemplate(hostTemplate, neighbor),
+				chassisIdSubtype: neighbor.chassisIdSubtype,
+				chassisId:        neighbor.chassisId,
+				sysName:          neighbor.sysName,
+			}
+			if key, ok := uniqueName(chassisByName, host.key, neighbor.chassisId, neighbor.chassisIdSubtype+"/"+neighbor.chassisId); ok {
+				if host.key != "" {
+					collisions = append(collisions, "the host name template names \""+host.key+"\" both chassis "+chassisByName[host.key]+" and "+host.chassisId+", the latter is named \""+key+"\"")
+				}
+				host.key = key
+			}
+			existing := findHost(existingHosts, host.chassisIdSubtype, host.chassisId)
+			host.id, _ = common.LookupValue(existing, "id")
+			host.href, _ = common.LookupValue(existing, "href")
+			host.labels, host.inSync = desiredConfig(existing, host.key, plan.ManagedBy, labels)
+			byChassis[neighbor.chassisIdSubtype+"/"+neighbor.chassisId] = host
+			chassisByName[host.key] = host.chassisId
+			hosts = append(hosts, host)
+		}
+
+		port := lldpHostPort{key: renderTemplate(portTemplate, neighbor), neighbor: neighbor}
+		duplicate := false
+		portNames := make(map[string]string)
+		for _, other := range host.ports {
+			duplicate = duplicate || other.neighbor.samePort(neighbor)
+			portNames[other.key] = other.neighbor.portId + other.neighbor.portSourceMAC
+		}
+		if duplicate {
+			continue
+		}
+		if key, ok := uniqueName(portNames, port.key, neighbor.portId+neighbor.portSourceMAC, neighbor.portIdSubtype+"/"+neighbor.portId+neighbor.portSourceMAC); ok {
+			if port.key != "" {
+				collisions = append(collisions, "the port name template names \""+port.key+"\" two ports of host "+host.key+", the latter is named \""+key+"\"")
+			}
+			port.key = key
+		}
+		existing := findHostPort(existingHosts, host.id, neighbor)
+		port.id, _ = common.LookupValue(existing, "id")
+		port.href, _ = common.LookupValue(existing, "href")
+		port.labels, port.inSync = desiredConfig(existing, port.key, plan.ManagedBy, labels)
+		host.ports = append(host.ports, port)
+	}
+
+	result := make([]lldpHost, 0, len(hosts))
+	for _, host := range hosts {
+		sort.Slice(host.ports, func(i, j int) bool { return host.ports[i].key < host.ports[j].key })
+		result = append(result, *host)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].key < result[j].key })
+	return result, collisions, nil
+}
+
+// uniqueName returns the first of the fallback names which is not taken when the name is empty or taken, and whether the
+// name is replaced. The last fallback is returned when all are taken.
+func uniqueName(taken map[string]string, name string, fallbacks ...string) (string, bool) {
+	if _, ok := taken[name]; !ok && name != "" {
+		return name, false
+	}
+	for _, fallback := range fallbacks {
+		if _, ok := taken[fallback]; !ok {
+			return fallback, true
+		}
+	}
+	return fallbacks[len(fallbacks)-1], true
+}
+
+// addCollisionWarnings reports the hosts and ports which are not named by the templates because their name is taken.
+func addCollisionWarnings(collisions []string, diags *diag.Diagnostics) {
+	for _, collision := range collisions {
+		diags.AddWarning("HostsFromLLDPResource: Name collision", "The "+collision+".")
+	}
+}
+
+// read reports the hosts and ports learned by LLDP now, and whether they are in IPM as the templates say.
+func (r *HostsFromLLDPResource) read(state *HostsFromLLDPResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	hosts, _, err := r.evaluate(state, ctx)
+	if err != nil {
+		diags.AddError(
+			"HostsFromLLDPResource: read ##: Error Get LLDP neighbors",
+			"Read:Could not evaluate the LLDP neighbors, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	previous := state.Hosts
+	state.Hosts = make(map[string]LLDPHost)
+	for _, host := range hosts {
+		if _, ok := previous[host.key]; !ok && previous != nil {
+			tflog.Info(ctx, "HostsFromLLDPResource: read ## host learned", map[string]interface{}{"host": host.key})
+		}
+		ports := make(map[string]LLDPHostPort)
+		for _, port := range host.ports {
+			ports[port.key] = LLDPHostPort{
+				Id:            common.OptionalString(port.id),
+				Href:          common.OptionalString(port.href),
+				PortIdSubtype: common.OptionalString(port.neighbor.portIdSubtype),
+				PortId:        common.OptionalString(port.neighbor.portId),
+				PortSourceMAC: common.OptionalString(port.neighbor.portSourceMAC),
+				ModuleName:    common.OptionalString(port.neighbor.moduleName),
+				ClientIfAid:   common.OptionalString(port.neighbor.clientIfAid),
+				InSync:        types.BoolValue(port.id != "" && port.inSync),
+			}
+		}
+		state.Hosts[host.key] = LLDPHost{
+			Id:               common.OptionalString(host.id),
+			Href:             common.OptionalString(host.href),
+			ChassisIdSubtype: common.OptionalString(host.chassisIdSubtype),
+			ChassisId:        types.StringValue(host.chassisId),
+			SysName:          common.OptionalString(host.sysName),
+			InSync:           types.BoolValue(host.id != "" && host.inSync),
+			Ports:            ports,
+		}
+	}
+	for key := range previous {
+		if _, ok := state.Hosts[key]; !ok {
+			tflog.Info(ctx, "HostsFromLLDPResource: read ## host no longer learned", map[string]interface{}{"host": key})
+		}
+	}
+
+	tflog.Debug(ctx, "HostsFromLLDPResource: read SUCCESS ", map[string]interface{}{"state": state})
+}
+
+// samePort tells whether the neighbors are the same host port, by port id or else by port source MAC.
+func (n lldpNeighbor) samePort(other lldpNeighbor) bool {
+	if n.portId != "" || other.portId != "" {
+		return n.portIdSubtype == other.portIdSubtype && n.portId == other.portId
+	}
+	return n.portSourceMAC == other.portSourceMAC
+}
+
+// selector returns the host port selector of the neighbor, by port id or else by port source MAC.
+func (n lldpNeighbor) selector() common.IfSelector {
+	if n.portId != "" {
+		return common.IfSelector{
+			HostPortSelectorByPortId: &common.HostPortSelectorByPortId{
+				ChassisIdSubtype: types.StringValue(n.chassisIdSubtype),
+				ChassisId:        types.StringValue(n.chassisId),
+				PortIdSubtype:    types.StringValue(n.portIdSubtype),
+				PortId:           types.StringValue(n.portId),
+			},
+		}
+	}
+	return common.IfSelector{
+		HostPortSelectorByPortSourceMAC: &common.HostPortSelectorByPortSourceMAC{
+			PortSourceMAC: types.StringValue(n.portSourceMAC),
+		},
+	}
+}
+
+// findHost returns the IPM host with the chassis id, from its state or else its selector, or nil.
+func findHost(hosts []interface{}, chassisIdSubtype string, chassisId string) map[string]interface{} {
+	for _, h := range hosts {
+		host, ok := h.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id := firstValue(host, "state.chassisId", "config.selector.hostPortSelectorByChassisId.chassisId", "config.selector.hostSelectorByChassisId.chassisId")
+		subtype := firstValue(host, "state.chassisIdSubtype", "config.selector.hostPortSelectorByChassisId.chassisIdSubtype", "config.selector.hostSelectorByChassisId.chassisIdSubtype")
+		if id == chassisId && (subtype == "" || chassisIdSubtype == "" || subtype == chassisIdSubtype) {
+			return host
+		}
+	}
+	return nil
+}
+
+// findHostPort returns the port of the IPM host with the port id, or the port source MAC, of the neighbor, or nil.
+func findHostPort(hosts []interface{}, hostId string, neighbor lldpNeighbor) map[string]interface{} {
+	if hostId == "" {
+		return nil
+	}
+	for _, h := range hosts {
+		host, ok := h.(map[string]interface{})
+		if id, _ := common.LookupValue(host, "id"); !ok || id != hostId {
+			continue
+		}
+		ports, _ := host["ports"].([]interface{})
+		for _, p := range ports {
+			port, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			portNeighbor := lldpNeighbor{
+				portIdSubtype: firstValue(port, "state.portIdSubtype", "config.selector.hostPortSelectorByPortId.portIdSubtype"),
+				portId:        firstValue(port, "state.portId", "config.selector.hostPortSelectorByPortId.portId"),
+				portSourceMAC: firstValue(port, "state.portSourceMAC", "config.selector.hostPortSelectorByPortSourceMAC.portSourceMAC"),
+			}
+			if neighbor.portId != "" && portNeighbor.portId == neighbor.portId &&
+				(portNeighbor.portIdSubtype == "" || neighbor.portIdSubtype == "" || portNeighbor.portIdSubtype == neighbor.portIdSubtype) {
+				return port
+			}
+			if neighbor.portId == "" && portNeighbor.portSourceMAC != "" && strings.EqualFold(portNeighbor.portSourceMAC, neighbor.portSourceMAC) {
+				return port
+			}
+		}
+	}
+	return nil
+}
+
+// desiredConfig returns the labels of the IPM host or port merged with the template labels, and whether the host or port
+// already has the name, the managed by and the labels.
+func desiredConfig(data map[string]interface{}, name string, managedBy types.String, labels map[string]string) (map[string]string, bool) {
+	merged := make(map[string]string)
+	if config, ok := data["config"].(map[string]interface{}); ok {
+		if current, ok := config["labels"].(map[string]interface{}); ok {
+			for k, v := range current {
+				merged[k], _ = v.(string)
+			}
+		}
+	}
+	inSync := data != nil
+	if current, _ := common.LookupValue(data, "config.name"); current != name {
+		inSync = false
+	}
+	if !managedBy.IsNull() {
+		if current, _ := common.LookupValue(data, "config.managedBy"); current != managedBy.ValueString() {
+			inSync = false
+		}
+	}
+	for k, v := range labels {
+		if current, ok := merged[k]; !ok || current != v {
+			merged[k] = v
+			inSync = false
+		}
+	}
+	return merged, inSync
+}
+
+// firstValue returns the first value found at the dotted paths, or an empty string.
+func firstValue(data map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, found := common.LookupValue(data, key); found && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func labelsValue(labels map[string]string) types.Map {
+	if len(labels) == 0 {
+		return types.MapNull(types.StringType)
+	}
+	values := make(map[string]attr.Value)
+	for k, v := range labels {
+		values[k] = types.StringValue(v)
+	}
+	return types.MapValueMust(types.StringType, values)
+}
+
+func templateValue(template types.String, defaultTemplate string) string {
+	if template.IsNull() || template.ValueString() == "" {
+		return defaultTemplate
+	}
+	return template.ValueString()
+}
+
+// checkTemplate returns an error when the template has a placeholder which is not one of the placeholders.
+func checkTemplate(template string, placeholders []string) error {
+	for _, placeholder := range templatePlaceholder.FindAllString(template, -1) {
+		if !common.Contains(placeholders, strings.Trim(placeholder, "{}")) {
+			return errors.New("unknown placeholder " + placeholder + ", the placeholders are {" + strings.Join(placeholders, "}, {") + "}")
+		}
+	}
+	return nil
+}
+
+// renderTemplate returns the template with the placeholders replaced by the values of the neighbor.
+func renderTemplate(template string, n lldpNeighbor) string {
+	return strings.TrimSpace(strings.NewReplacer(
+		"{sys_name}", n.sysName,
+		"{chassis_id}", n.chassisId,
+		"{chassis_id_subtype}", n.chassisIdSubtype,
+		"{port_id}", n.portId,
+		"{port_id_subtype}", n.portIdSubtype,
+		"{port_descr}", n.portDescr,
+		"{port_source_mac}", n.portSourceMAC,
+		"{module_name}", n.moduleName,
+		"{client_if_aid}", n.clientIfAid,
+	).Replace(template))
+}
+
+func lldpHostPortAttributeType() map[string]attr.Type {
+	return map[string]attr.Type{
+		"id":              types.StringType,
+		"href":            types.StringType,
+		"port_id_subtype": types.StringType,
+		"port_id":         types.StringType,
+		"port_source_mac": types.StringType,
+		"module_name":     types.StringType,
+		"client_if_aid":   types.StringType,
+		"in_sync":         types.BoolType,
+	}
+}
+
+func lldpHostAttributeType() map[string]attr.Type {
+	return map[string]attr.Type{
+		"id":                 types.StringType,
+		"href":               types.StringType,
+		"chassis_id_subtype": types.StringType,
+		"chassis_id":         types.StringType,
+		"sys_name":           types.StringType,
+		"in_sync":            types.BoolType,
+		"ports":              types.MapType{ElemType: types.ObjectType{AttrTypes: lldpHostPortAttributeType()}},
+	}
+}
+
+func HostsFromLLDPSchemaAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Description: "Identifier of the discovery",
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"module_labels": schema.MapAttribute{
+			Description: "labels the modules must have",
+			Optional:    true,
+			ElementType: types.StringType,
+		},
+		"module_name_regex": schema.StringAttribute{
+			Description: "regular expression the module names must match",
+			Optional:    true,
+		},
+		"host_name_template": schema.StringAttribute{
+			Description: "name of the hosts, " + defaultHostNameTemplate + " by default. The placeholders are {" + strings.Join(hostPlaceholders, "}, {") +
+				"}. The chassis id is the name when the template renders an empty name, or a name which another host already has.",
+			Optional: true,
+		},
+		"port_name_template": schema.StringAttribute{
+			Description: "name of the host ports, " + defaultPortNameTemplate + " by default. The placeholders are the host ones and {" + strings.Join(portPlaceholders, "}, {") +
+				"}. The port id, or port source MAC, is the name when the template renders an empty name, or a name which another port of the host already has.",
+			Optional: true,
+		},
+		"managed_by": schema.StringAttribute{
+			Description: "managed_by of the hosts and host ports",
+			Optional:    true,
+		},
+		"labels": schema.MapAttribute{
+			Description: "labels added to the hosts and host ports, or replacing the value of their labels with the same key",
+			Optional:    true,
+			ElementType: types.StringType,
+		},
+		"hosts": schema.MapNestedAttribute{
+			Description: "The hosts learned by LLDP, by host name",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Description: "id of the host",
+						Computed:    true,
+					},
+					"href": schema.StringAttribute{
+						Description: "href of the host",
+						Computed:    true,
+					},
+					"chassis_id_subtype": schema.StringAttribute{
+						Description: "chassis_id_subtype",
+						Computed:    true,
+					},
+					"chassis_id": schema.StringAttribute{
+						Description: "chassis_id",
+						Computed:    true,
+					},
+					"sys_name": schema.StringAttribute{
+						Description: "sys_name",
+						Computed:    true,
+					},
+					"in_sync": schema.BoolAttribute{
+						Description: "whether the host is in IPM with the name, managed_by and labels of the templates",
+						Computed:    true,
+					},
+					"ports": schema.MapNestedAttribute{
+						Description: "the ports of the host learned by LLDP, by port name",
+						Computed:    true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"id": schema.StringAttribute{
+									Description: "id of the host port",
+									Computed:    true,
+								},
+								"href": schema.StringAttribute{
+									Description: "href of the host port",
+									Computed:    true,
+								},
+								"port_id_subtype": schema.StringAttribute{
+									Description: "port_id_subtype",
+									Computed:    true,
+								},
+								"port_id": schema.StringAttribute{
+									Description: "port_id",
+									Computed:    true,
+								},
+								"port_source_mac": schema.StringAttribute{
+									Description: "port_source_mac",
+									Computed:    true,
+								},
+								"module_name": schema.StringAttribute{
+									Description: "name of the module which learned the port",
+									Computed:    true,
+								},
+								"client_if_aid": schema.StringAttribute{
+									Description: "client interface of the module which learned the port",
+									Computed:    true,
+								},
+								"in_sync": schema.BoolAttribute{
+									Description: "whether the host port is in IPM with the name, managed_by and labels of the templates",
+									Computed:    true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,208 @@
+package host
+
+import (
+	"reflect"
+	"testing"
+
+	common "terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCheckTemplate(t *testing.T) {
+	allPlaceholders := append(append([]string{}, hostPlaceholders...), portPlaceholders...)
+	tests := []struct {
+		name         string
+		template     string
+		placeholders []string
+		valid        bool
+	}{
+		{"default host name", defaultHostNameTemplate, hostPlaceholders, true},
+		{"default port name", defaultPortNameTemplate, allPlaceholders, true},
+		{"no placeholder", "host", hostPlaceholders, true},
+		{"several placeholders", "{sys_name}-{chassis_id}", hostPlaceholders, true},
+		{"host placeholder in port name", "{sys_name}:{port_id}", allPlaceholders, true},
+		{"port placeholder in host name", "{sys_name}:{port_id}", hostPlaceholders, false},
+		{"unknown placeholder", "{hostname}", allPlaceholders, false},
+		{"empty placeholder", "host-{}", allPlaceholders, false},
+	}
+	for _, test := range tests {
+		if err := checkTemplate(test.template, test.placeholders); (err == nil) != test.valid {
+			t.Errorf("%s: checkTemplate(%q) = %v, want valid %v", test.name, test.template, err, test.valid)
+		}
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	neighbor := lldpNeighbor{
+		moduleName:       "hub-1",
+		clientIfAid:      "XR-T1",
+		chassisIdSubtype: "macAddress",
+		chassisId:        "00:11:22:33:44:55",
+		sysName:          "router-1",
+		portIdSubtype:    "interfaceName",
+		portId:           "Ethernet1/1",
+		portDescr:        "uplink",
+		portSourceMAC:    "00:11:22:33:44:66",
+	}
+	tests := []struct {
+		template string
+		name     string
+	}{
+		{"{sys_name}", "router-1"},
+		{"{port_id}", "Ethernet1/1"},
+		{"{sys_name}-{chassis_id}", "router-1-00:11:22:33:44:55"},
+		{"{module_name}/{client_if_aid} {port_descr}", "hub-1/XR-T1 uplink"},
+		{"{chassis_id_subtype}:{port_id_subtype}:{port_source_mac}", "macAddress:interfaceName:00:11:22:33:44:66"},
+		{" {sys_name} ", "router-1"},
+		{"{sys_name} {hostname}", "router-1 {hostname}"},
+	}
+	for _, test := range tests {
+		if name := renderTemplate(test.template, neighbor); name != test.name {
+			t.Errorf("renderTemplate(%q) = %q, want %q", test.template, name, test.name)
+		}
+	}
+}
+
+func TestFindHost(t *testing.T) {
+	hosts := []interface{}{
+		"not a host",
+		map[string]interface{}{"id": "h1", "state": map[string]interface{}{"chassisIdSubtype": "macAddress", "chassisId": "00:11:22:33:44:55"}},
+		map[string]interface{}{"id": "h2", "config": map[string]interface{}{"selector": map[string]interface{}{
+			"hostSelectorByChassisId": map[string]interface{}{"chassisIdSubtype": "local", "chassisId": "router-2"}}}},
+		map[string]interface{}{"id": "h3", "config": map[string]interface{}{"selector": map[string]interface{}{
+			"hostPortSelectorByChassisId": map[string]interface{}{"chassisId": "router-3"}}}},
+	}
+	tests := []struct {
+		name             string
+		chassisIdSubtype string
+		chassisId        string
+		id               string
+	}{
+		{"by state", "macAddress", "00:11:22:33:44:55", "h1"},
+		{"other subtype", "local", "00:11:22:33:44:55", ""},
+		{"any neighbor subtype", "", "00:11:22:33:44:55", "h1"},
+		{"by host selector", "local", "router-2", "h2"},
+		{"by host port selector without subtype", "local", "router-3", "h3"},
+		{"unknown chassis", "local", "router-4", ""},
+	}
+	for _, test := range tests {
+		id, _ := common.LookupValue(findHost(hosts, test.chassisIdSubtype, test.chassisId), "id")
+		if id != test.id {
+			t.Errorf("%s: findHost() = %q, want %q", test.name, id, test.id)
+		}
+	}
+}
+
+func TestFindHostPort(t *testing.T) {
+	hosts := []interface{}{
+		map[string]interface{}{"id": "h1", "ports": []interface{}{
+			"not a port",
+			map[string]interface{}{"id": "p1", "state": map[string]interface{}{"portIdSubtype": "interfaceName", "portId": "Ethernet1/1"}},
+			map[string]interface{}{"id": "p2", "config": map[string]interface{}{"selector": map[string]interface{}{
+				"hostPortSelectorByPortId": map[string]interface{}{"portId": "Ethernet1/2"}}}},
+			map[string]interface{}{"id": "p3", "config": map[string]interface{}{"selector": map[string]interface{}{
+				"hostPortSelectorByPortSourceMAC": map[string]interface{}{"portSourceMAC": "00:11:22:33:44:aa"}}}},
+		}},
+		map[string]interface{}{"id": "h2", "ports": []interface{}{
+			map[string]interface{}{"id": "p4", "state": map[string]interface{}{"portId": "Ethernet1/1"}},
+		}},
+	}
+	tests := []struct {
+		name     string
+		hostId   string
+		neighbor lldpNeighbor
+		id       string
+	}{
+		{"by port id", "h1", lldpNeighbor{portIdSubtype: "interfaceName", portId: "Ethernet1/1"}, "p1"},
+		{"other port id subtype", "h1", lldpNeighbor{portIdSubtype: "local", portId: "Ethernet1/1"}, ""},
+		{"any neighbor subtype", "h1", lldpNeighbor{portId: "Ethernet1/1"}, "p1"},
+		{"any port subtype", "h1", lldpNeighbor{portIdSubtype: "local", portId: "Ethernet1/2"}, "p2"},
+		{"by port source MAC", "h1", lldpNeighbor{portSourceMAC: "00:11:22:33:44:aa"}, "p3"},
+		{"port source MAC case", "h1", lldpNeighbor{portSourceMAC: "00:11:22:33:44:AA"}, "p3"},
+		{"port id before port source MAC", "h1", lldpNeighbor{portId: "Ethernet1/3", portSourceMAC: "00:11:22:33:44:aa"}, ""},
+		{"port of other host", "h2", lldpNeighbor{portIdSubtype: "interfaceName", portId: "Ethernet1/1"}, "p4"},
+		{"host not in IPM", "", lldpNeighbor{portId: "Ethernet1/1"}, ""},
+	}
+	for _, test := range tests {
+		id, _ := common.LookupValue(findHostPort(hosts, test.hostId, test.neighbor), "id")
+		if id != test.id {
+			t.Errorf("%s: findHostPort() = %q, want %q", test.name, id, test.id)
+		}
+	}
+}
+
+func TestDesiredConfig(t *testing.T) {
+	current := map[string]interface{}{"config": map[string]interface{}{
+		"name":      "router-1",
+		"managedBy": "host",
+		"labels":    map[string]interface{}{"site": "a"},
+	}}
+	tests := []struct {
+		name      string
+		data      map[string]interface{}
+		hostName  string
+		managedBy types.String
+		labels    map[string]string
+		merged    map[string]string
+		inSync    bool
+	}{
+		{"in sync", current, "router-1", types.StringValue("host"), map[string]string{"site": "a"}, map[string]string{"site": "a"}, true},
+		{"managed by not set", current, "router-1", types.StringNull(), nil, map[string]string{"site": "a"}, true},
+		{"other name", current, "router-2", types.StringNull(), nil, map[string]string{"site": "a"}, false},
+		{"other managed by", current, "router-1", types.StringValue("lldp"), nil, map[string]string{"site": "a"}, false},
+		{"label added", current, "router-1", types.StringNull(), map[string]string{"role": "core"}, map[string]string{"site": "a", "role": "core"}, false},
+		{"label changed", current, "router-1", types.StringNull(), map[string]string{"site": "b"}, map[string]string{"site": "b"}, false},
+		{"not in IPM", nil, "router-1", types.StringNull(), map[string]string{"site": "a"}, map[string]string{"site": "a"}, false},
+	}
+	for _, test := range tests {
+		merged, inSync := desiredConfig(test.data, test.hostName, test.managedBy, test.labels)
+		if inSync != test.inSync || !reflect.DeepEqual(merged, test.merged) {
+			t.Errorf("%s: desiredConfig() = %v, %v, want %v, %v", test.name, merged, inSync, test.merged, test.inSync)
+		}
+	}
+}
+
+func TestSamePort(t *testing.T) {
+	tests := []struct {
+		name  string
+		n     lldpNeighbor
+		other lldpNeighbor
+		same  bool
+	}{
+		{"same port id", lldpNeighbor{portIdSubtype: "interfaceName", portId: "Ethernet1/1"}, lldpNeighbor{portIdSubtype: "interfaceName", portId: "Ethernet1/1"}, true},
+		{"other port id", lldpNeighbor{portIdSubtype: "interfaceName", portId: "Ethernet1/1"}, lldpNeighbor{portIdSubtype: "interfaceName", portId: "Ethernet1/2"}, false},
+		{"other port id subtype", lldpNeighbor{portIdSubtype: "interfaceName", portId: "1"}, lldpNeighbor{portIdSubtype: "local", portId: "1"}, false},
+		{"port id and port source MAC", lldpNeighbor{portId: "Ethernet1/1", portSourceMAC: "00:11:22:33:44:66"}, lldpNeighbor{portSourceMAC: "00:11:22:33:44:66"}, false},
+		{"same port source MAC", lldpNeighbor{portSourceMAC: "00:11:22:33:44:66"}, lldpNeighbor{portSourceMAC: "00:11:22:33:44:66"}, true},
+		{"other port source MAC", lldpNeighbor{portSourceMAC: "00:11:22:33:44:66"}, lldpNeighbor{portSourceMAC: "00:11:22:33:44:77"}, false},
+	}
+	for _, test := range tests {
+		if same := test.n.samePort(test.other); same != test.same {
+			t.Errorf("%s: samePort() = %v, want %v", test.name, same, test.same)
+		}
+	}
+}
+
+func TestUniqueName(t *testing.T) {
+	taken := map[string]string{"router-1": "c1", "c2": "c2", "macAddress/c2": "c2"}
+	tests := []struct {
+		name     string
+		hostName string
+		unique   string
+		replaced bool
+	}{
+		{"free", "router-2", "router-2", false},
+		{"empty", "", "c3", true},
+		{"taken", "router-1", "c3", true},
+	}
+	for _, test := range tests {
+		unique, replaced := uniqueName(taken, test.hostName, "c3", "macAddress/c3")
+		if unique != test.unique || replaced != test.replaced {
+			t.Errorf("%s: uniqueName() = %q, %v, want %q, %v", test.name, unique, replaced, test.unique, test.replaced)
+		}
+	}
+	if unique, _ := uniqueName(taken, "router-1", "c2", "macAddress/c2"); unique != "macAddress/c2" {
+		t.Errorf("all taken: uniqueName() = %q, want %q", unique, "macAddress/c2")
+	}
+}
@@ -7,6 +7,8 @@ import (
 
 	"terraform-provider-ipm/internal/ipm_pf"
 
+	"terraform-provider-ipm/internal/provider/internal/common"
+
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -71,7 +73,7 @@ func (d *ACsDataSource) Configure(_ context.Context, req datasource.ConfigureReq
 		return
 	}
 
-	d.client = req.ProviderData.(*ipm_pf.Client)
+	d.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (d *ACsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
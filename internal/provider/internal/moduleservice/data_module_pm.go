@@ -0,0 +1,77 @@
+package moduleservice
+
+import (
+	"context"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &ModulePMDataSource{}
+	_ datasource.DataSourceWithConfigure = &ModulePMDataSource{}
+)
+
+// NewModulePMDataSource is a helper function to simplify the provider implementation.
+func NewModulePMDataSource() datasource.DataSource {
+	return &ModulePMDataSource{}
+}
+
+// ModulePMDataSource reads the PM counters of module line PTPs, carriers, DSCs and ethernet clients.
+type ModulePMDataSource struct {
+	client *ipm_pf.Client
+}
+
+// Metadata returns the data source type name.
+func (r *ModulePMDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_module_pm"
+}
+
+func (d *ModulePMDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the current and historical PM bins of module line PTPs, carriers, DSCs and ethernet clients: optical power, pre-FEC BER, OSNR, Q-factor and ethernet counters. " +
+			"A line PTP is identified by its col_id, a carrier by the line PTP col id as parent_col_id, a DSC by the line PTP col id as grand_parent_col_id and the carrier col id as parent_col_id.",
+		Attributes: common.PMDataSourceSchemaAttributes([]string{moduleLinePTP, moduleCarrier, moduleDSC, moduleEthernetClient}),
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *ModulePMDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+func (d *ModulePMDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	query := common.PMDataSourceData{}
+	diags := req.Config.Get(ctx, &query)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Debug(ctx, "ModulePMDataSource: get PM", map[string]interface{}{"query": query})
+
+	bins, err := common.ReadPMBins(d.client, &query, func(object common.PMObject) (string, error) {
+		return moduleObjectHref(object.Type.ValueString(), object.Identifier)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"ModulePMDataSource: read ##: Error Get PM",
+			"Get:Could not get the PM bins, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	query.Bins = types.ListValueMust(common.PMBinObjectType(), common.PMBinObjectsValue(bins))
+
+	diags = resp.State.Set(ctx, query)
+	resp.Diagnostics.Append(diags...)
+	tflog.Debug(ctx, "ModulePMDataSource: get ", map[string]interface{}{"bins": len(bins)})
+}
@@ -0,0 +1,43 @@
+package moduleservice
+
+import (
+	"fmt"
+
+	"terraform-provider-ipm/internal/provider/internal/common"
+)
+
+// the types of the module objects addressed by a common.ResourceIdentifier
+const (
+	moduleLinePTP        = "line_ptp"
+	moduleCarrier        = "carrier"
+	moduleDSC            = "dsc"
+	moduleEthernetClient = "ethernet_client"
+)
+
+// moduleObjectHref returns the href of a module object, from its identifier href or from its col ids.
+func moduleObjectHref(objectType string, id common.ResourceIdentifier) (string, error) {
+	if !id.Href.IsNull() {
+		return id.Href.ValueString(), nil
+	}
+	if id.DeviceId.IsNull() || id.ColId.IsNull() {
+		return "", fmt.Errorf("the %s identifier needs an href or a device_id and col ids", objectType)
+	}
+	module := "/modules/" + id.DeviceId.ValueString()
+	switch objectType {
+	case moduleLinePTP:
+		return module + "/linePtps/" + id.ColId.ValueString(), nil
+	case moduleCarrier:
+		if id.ParentColId.IsNull() {
+			return "", fmt.Errorf("the carrier identifier needs the line PTP col id as parent_col_id")
+		}
+		return module + "/linePtps/" + id.ParentColId.ValueString() + "/carriers/" + id.ColId.ValueString(), nil
+	case moduleDSC:
+		if id.ParentColId.IsNull() || id.GrandParentColId.IsNull() {
+			return "", fmt.Errorf("the DSC identifier needs the line PTP col id as grand_parent_col_id and the carrier col id as parent_col_id")
+		}
+		return module + "/linePtps/" + id.GrandParentColId.ValueString() + "/carriers/" + id.ParentColId.ValueString() + "/dscs/" + id.ColId.ValueString(), nil
+	case moduleEthernetClient:
+		return module + "/ethernetClients/" + id.ColId.ValueString(), nil
+	}
+	return "", fmt.Errorf("the type %q is not one of %s, %s, %s or %s", objectType, moduleLinePTP, moduleCarrier, moduleDSC, moduleEthernetClient)
+}
@@ -74,7 +74,7 @@ func (r *CarrierResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r CarrierResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
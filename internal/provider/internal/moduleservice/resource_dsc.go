@@ -73,7 +73,7 @@ func (r *DSCResource) Configure(_ context.Context, req resource.ConfigureRequest
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r DSCResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
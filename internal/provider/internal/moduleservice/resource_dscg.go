@@ -62,7 +62,7 @@ func (r *DSCGResource) Configure(_ context.Context, req resource.ConfigureReques
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r DSCGResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -85,7 +85,7 @@ func (r *EClientResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r EClientResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -912,6 +912,8 @@ func EClientLLDPNeighborAttributeValue(neighbors map[string]interface{}) map[str
 			chassisId = types.StringValue(v.(string))
 		case "sysName":
 			sysName = types.StringValue(v.(string))
+		case "portId":
+			portId = types.StringValue(v.(string))
 		case "portIdSubtype":
 			portIdSubtype = types.StringValue(v.(string))
 		case "portSourceMAC":
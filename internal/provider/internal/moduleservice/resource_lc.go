@@ -62,7 +62,7 @@ func (r *LCResource) Configure(_ context.Context, req resource.ConfigureRequest,
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r LCResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -0,0 +1,517 @@
+package moduleservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &LinkTestResource{}
+	_ resource.ResourceWithConfigure      = &LinkTestResource{}
+	_ resource.ResourceWithValidateConfig = &LinkTestResource{}
+)
+
+// NewLinkTestResource is a helper function to simplify the provider implementation.
+func NewLinkTestResource() resource.Resource {
+	return &LinkTestResource{}
+}
+
+// LinkTestResource runs a loopback or PRBS turn-up test on carriers, DSCs and ethernet clients.
+type LinkTestResource struct {
+	client *ipm_pf.Client
+}
+
+type LinkTestTarget struct {
+	Type       types.String              `tfsdk:"type"`
+	Identifier common.ResourceIdentifier `tfsdk:"identifier"`
+	Loopback   types.String              `tfsdk:"loopback"`
+	PRBS       types.Bool                `tfsdk:"prbs"`
+	TestSignal types.String              `tfsdk:"test_signal"`
+}
+
+type LinkTestResult struct {
+	Type        types.String `tfsdk:"type"`
+	Href        types.String `tfsdk:"href"`
+	PRBSSync    types.Bool   `tfsdk:"prbs_sync"`
+	PRBSErrors  types.Int64  `tfsdk:"prbs_errors"`
+	Diagnostics types.Map    `tfsdk:"diagnostics"`
+	Passed      types.Bool   `tfsdk:"passed"`
+}
+
+type LinkTestResourceData struct {
+	Id         types.String     `tfsdk:"id"`
+	Targets    []LinkTestTarget `tfsdk:"targets"`
+	Duration   types.Int64      `tfsdk:"duration"`
+	Triggers   types.Map        `tfsdk:"triggers"`
+	StartedAt  types.String     `tfsdk:"started_at"`
+	FinishedAt types.String     `tfsdk:"finished_at"`
+	Results    []LinkTestResult `tfsdk:"results"`
+	Passed     types.Bool       `tfsdk:"passed"`
+}
+
+// linkTestLoopbackMargin is the time in seconds the loopback is held past the duration, so that it is still applied when the
+// results are read.
+const linkTestLoopbackMargin = 60
+
+// linkTestOff holds the value which disables a diagnostics setting, used to restore settings the object did not report.
+var linkTestOff = map[string]interface{}{
+	"termLB":            "none",
+	"termLBDuration":    0,
+	"termTestSignalGen": "none",
+	"facPRBSGen":        false,
+	"facPRBSMon":        false,
+}
+
+// linkTestRun is a target as it is tested: its href, the diagnostics settings which enable the test and the ones they replaced.
+type linkTestRun struct {
+	target   LinkTestTarget
+	href     string
+	enable   map[string]interface{}
+	original map[string]interface{}
+	enabled  bool
+}
+
+// Metadata returns the data source type name.
+func (r *LinkTestResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_link_test"
+}
+
+// Schema defines the schema for the data source.
+func (r *LinkTestResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Runs a turn-up test when created: enables loopback or PRBS on the targets in order, waits for the duration, collects the PRBS " +
+			"sync and error results from the state of the targets and restores their original diagnostics settings in reverse order, also when the test fails. " +
+			"Any change of the arguments runs the test again.",
+		Attributes: LinkTestSchemaAttributes(),
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *LinkTestResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+// ValidateConfig checks that the test of each target applies to its type.
+func (r *LinkTestResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var targets []LinkTestTarget
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("targets"), &targets)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for i, target := range targets {
+		if target.Type.IsUnknown() {
+			continue
+		}
+		if err := linkTestValidateTarget(target); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("targets").AtListIndex(i), "LinkTestResource: Invalid target", err.Error())
+		}
+	}
+}
+
+func (r *LinkTestResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data LinkTestResourceData
+
+	diags := req.Config.Get(ctx, &data)
+
+	tflog.Debug(ctx, "LinkTestResource: Create - ", map[string]interface{}{"LinkTestResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.run(&data, ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *LinkTestResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data LinkTestResourceData
+
+	// the results are the ones of the run, the test is not repeated at refresh
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *LinkTestResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data LinkTestResourceData
+
+	// every argument requires a replacement, so there is nothing to update
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *LinkTestResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data LinkTestResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "LinkTestResource: Delete", map[string]interface{}{"LinkTestResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the settings are restored at the end of the run
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *LinkTestResource) run(plan *LinkTestResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	tflog.Debug(ctx, "LinkTestResource: run ## ", map[string]interface{}{"plan": plan})
+
+	runs := []*linkTestRun{}
+	for _, target := range plan.Targets {
+		if err := linkTestValidateTarget(target); err != nil {
+			diags.AddError("LinkTestResource: run ##: Invalid target", err.Error())
+			return
+		}
+		href, err := moduleObjectHref(target.Type.ValueString(), target.Identifier)
+		if err != nil {
+			diags.AddError("LinkTestResource: run ##: Invalid target", err.Error())
+			return
+		}
+		data, err := common.GetResource(r.client, href+"?content=expanded")
+		if err != nil {
+			diags.AddError(
+				"LinkTestResource: run ##: Error Get target",
+				"Run: Could not get the target "+href+", unexpected error: "+err.Error(),
+			)
+			return
+		}
+		config, _ := data["config"].(map[string]interface{})
+		original, _ := config["diagnostics"].(map[string]interface{})
+		runs = append(runs, &linkTestRun{
+			target:   target,
+			href:     href,
+			enable:   linkTestEnableSettings(target, plan.Duration.ValueInt64()),
+			original: original,
+		})
+	}
+
+	startedAt := time.Now()
+	plan.Id = types.StringValue(strconv.FormatInt(startedAt.UnixNano(), 10))
+	plan.StartedAt = types.StringValue(startedAt.UTC().Format(time.RFC3339))
+
+	// the original settings are restored whatever the outcome of the test
+	defer r.restore(runs, ctx, diags)
+
+	for _, run := range runs {
+		// the target is restored even if the request fails, as the module may have applied it
+		run.enabled = true
+		if err := r.setDiagnostics(run.href, run.enable); err != nil {
+			diags.AddError(
+				"LinkTestResource: run ##: Error enable test",
+				"Run: Could not enable the test on "+run.href+", unexpected error: "+err.Error(),
+			)
+			return
+		}
+		tflog.Debug(ctx, "LinkTestResource: run ## enabled", map[string]interface{}{"href": run.href, "settings": run.enable})
+	}
+
+	select {
+	case <-ctx.Done():
+		diags.AddError("LinkTestResource: run ##: Test interrupted", "Run: The test was interrupted before the end of its duration.")
+		return
+	case <-time.After(time.Duration(plan.Duration.ValueInt64()) * time.Second):
+	}
+
+	// the verdict is the one of the targets which have a result
+	plan.Passed = types.BoolNull()
+	plan.Results = []LinkTestResult{}
+	for _, run := range runs {
+		data, err := common.GetResource(r.client, run.href+"?content=expanded")
+		if err != nil {
+			diags.AddError(
+				"LinkTestResource: run ##: Error Get results",
+				"Run: Could not get the results of "+run.href+", unexpected error: "+err.Error(),
+			)
+			return
+		}
+		result := linkTestResult(ctx, run, data)
+		if !result.Passed.IsNull() {
+			plan.Passed = types.BoolValue(result.Passed.ValueBool() && (plan.Passed.IsNull() || plan.Passed.ValueBool()))
+		}
+		plan.Results = append(plan.Results, result)
+	}
+	plan.FinishedAt = types.StringValue(time.Now().UTC().Format(time.RFC3339))
+}
+
+// restore sets back the original diagnostics settings of the enabled targets, in reverse order.
+func (r *LinkTestResource) restore(runs []*linkTestRun, ctx context.Context, diags *diag.Diagnostics) {
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		if !run.enabled {
+			continue
+		}
+		settings := make(map[string]interface{})
+		for key := range run.enable {
+			if value, ok := run.original[key]; ok {
+				settings[key] = value
+			} else {
+				settings[key] = linkTestOff[key]
+			}
+		}
+		tflog.Debug(ctx, "LinkTestResource: restore ## ", map[string]interface{}{"href": run.href, "settings": settings})
+		if err := r.setDiagnostics(run.href, settings); err != nil {
+			diags.AddError(
+				"LinkTestResource: restore ##: Error restore settings",
+				"Restore: Could not restore the diagnostics settings of "+run.href+", unexpected error: "+err.Error(),
+			)
+		}
+	}
+}
+
+func (r *LinkTestResource) setDiagnostics(href string, settings map[string]interface{}) error {
+	rb, err := json.Marshal(map[string]interface{}{"diagnostics": settings})
+	if err != nil {
+		return err
+	}
+	_, err = r.client.ExecuteIPMHttpCommand("PUT", href, rb)
+	return err
+}
+
+func linkTestValidateTarget(target LinkTestTarget) error {
+	loopback := !target.Loopback.IsNull()
+	prbs := !target.PRBS.IsNull() && target.PRBS.ValueBool()
+	testSignal := !target.TestSignal.IsNull()
+	switch target.Type.ValueString() {
+	case moduleCarrier:
+		if !loopback || prbs || testSignal {
+			return fmt.Errorf("a carrier is tested with a loopback only")
+		}
+	case moduleDSC:
+		if !prbs || loopback || testSignal {
+			return fmt.Errorf("a DSC is tested with PRBS only")
+		}
+	case moduleEthernetClient:
+		if loopback == testSignal || prbs {
+			return fmt.Errorf("an ethernet client is tested with either a loopback or a test signal")
+		}
+	default:
+		return fmt.Errorf("the type %q is not one of %s, %s or %s", target.Type.ValueString(), moduleCarrier, moduleDSC, moduleEthernetClient)
+	}
+	return nil
+}
+
+// linkTestEnableSettings returns the diagnostics settings which start the test of the target.
+func linkTestEnableSettings(target LinkTestTarget, duration int64) map[string]interface{} {
+	settings := make(map[string]interface{})
+	if !target.Loopback.IsNull() {
+		// the module ends the loopback by itself should the restore fail, after the results are read
+		settings["termLB"] = target.Loopback.ValueString()
+		settings["termLBDuration"] = duration + linkTestLoopbackMargin
+	}
+	if !target.PRBS.IsNull() && target.PRBS.ValueBool() {
+		settings["facPRBSGen"] = true
+		settings["facPRBSMon"] = true
+	}
+	if !target.TestSignal.IsNull() {
+		settings["termTestSignalGen"] = target.TestSignal.ValueString()
+	}
+	return settings
+}
+
+// linkTestResult collects the diagnostics state of a target. The PRBS sync and error counter are the diagnostics
+// state values whose names contain "sync" and "err"; a PRBS test passes when it is in sync without errors. A loopback or
+// a test signal passes when the diagnostics state reports it. passed is null when the state does not report the result.
+func linkTestResult(ctx context.Context, run *linkTestRun, data map[string]interface{}) LinkTestResult {
+	result := LinkTestResult{
+		Type:       run.target.Type,
+		Href:       types.StringValue(run.href),
+		PRBSSync:   types.BoolNull(),
+		PRBSErrors: types.Int64Null(),
+		Passed:     types.BoolNull(),
+	}
+	state, _ := data["state"].(map[string]interface{})
+	diagnostics, _ := state["diagnostics"].(map[string]interface{})
+
+	values := make(map[string]string)
+	keys := make([]string, 0, len(diagnostics))
+	for key := range diagnostics {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		switch value := diagnostics[key].(type) {
+		case string, bool, float64:
+			values[key] = fmt.Sprint(value)
+		default:
+			continue
+		}
+		lower := strings.ToLower(key)
+		switch {
+		case strings.Contains(lower, "prbs") && strings.Contains(lower, "sync"):
+			switch value := diagnostics[key].(type) {
+			case bool:
+				result.PRBSSync = types.BoolValue(value)
+			case string:
+				sync := strings.ToLower(value)
+				result.PRBSSync = types.BoolValue(strings.Contains(sync, "sync") && !strings.Contains(sync, "out") && !strings.Contains(sync, "loss") && !strings.Contains(sync, "no"))
+			}
+		case strings.Contains(lower, "prbs") && strings.Contains(lower, "err"):
+			if value, ok := diagnostics[key].(float64); ok {
+				result.PRBSErrors = types.Int64Value(int64(value))
+			}
+		}
+	}
+	result.Diagnostics, _ = types.MapValueFrom(ctx, types.StringType, values)
+
+	if run.target.Type.ValueString() == moduleDSC {
+		if !result.PRBSSync.IsNull() {
+			result.Passed = types.BoolValue(result.PRBSSync.ValueBool() && result.PRBSErrors.ValueInt64() == 0)
+		}
+		return result
+	}
+	for _, key := range []string{"termLB", "termTestSignalGen"} {
+		if enabled, ok := run.enable[key]; ok {
+			if applied, found := values[key]; found {
+				result.Passed = types.BoolValue(applied == fmt.Sprint(enabled))
+			}
+		}
+	}
+	return result
+}
+
+func LinkTestSchemaAttributes() map[string]schema.Attribute {
+	identifier := common.ResourceIdentifierAttribute()
+	identifier.Optional = false
+	identifier.Required = true
+	identifier.Description = "Identifier of the target: its href, or the device_id and col ids. A carrier needs the line PTP col id as parent_col_id, " +
+		"a DSC needs the line PTP col id as grand_parent_col_id and the carrier col id as parent_col_id."
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Description: "Identifier of the test run",
+			Computed:    true,
+		},
+		"targets": schema.ListNestedAttribute{
+			Description: "The carriers, DSCs and ethernet clients to test, enabled in this order",
+			Required:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"type": schema.StringAttribute{
+						Description: "carrier, dsc or ethernet_client",
+						Required:    true,
+					},
+					"identifier": identifier,
+					"loopback": schema.StringAttribute{
+						Description: "term_lb setting of a carrier or an ethernet client during the test",
+						Optional:    true,
+					},
+					"prbs": schema.BoolAttribute{
+						Description: "enables the PRBS generator and monitor of a DSC during the test",
+						Optional:    true,
+					},
+					"test_signal": schema.StringAttribute{
+						Description: "term_test_signal_gen setting of an ethernet client during the test",
+						Optional:    true,
+					},
+				},
+			},
+			PlanModifiers: []planmodifier.List{
+				listplanmodifier.RequiresReplace(),
+			},
+		},
+		"duration": schema.Int64Attribute{
+			Description: "duration of the test in seconds",
+			Required:    true,
+			PlanModifiers: []planmodifier.Int64{
+				int64planmodifier.RequiresReplace(),
+			},
+		},
+		"triggers": schema.MapAttribute{
+			Description: "arbitrary values which run the test again when they change",
+			Optional:    true,
+			ElementType: types.StringType,
+			PlanModifiers: []planmodifier.Map{
+				mapplanmodifier.RequiresReplace(),
+			},
+		},
+		"started_at": schema.StringAttribute{
+			Description: "start time of the test",
+			Computed:    true,
+		},
+		"finished_at": schema.StringAttribute{
+			Description: "time the results were collected",
+			Computed:    true,
+		},
+		"results": schema.ListNestedAttribute{
+			Description: "results per target, in the order of the targets",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"type": schema.StringAttribute{
+						Description: "type of the target",
+						Computed:    true,
+					},
+					"href": schema.StringAttribute{
+						Description: "href of the target",
+						Computed:    true,
+					},
+					"prbs_sync": schema.BoolAttribute{
+						Description: "whether the PRBS monitor was in sync, when reported",
+						Computed:    true,
+					},
+					"prbs_errors": schema.Int64Attribute{
+						Description: "PRBS error count, when reported",
+						Computed:    true,
+					},
+					"diagnostics": schema.MapAttribute{
+						Description: "diagnostics state of the target at the end of the test",
+						Computed:    true,
+						ElementType: types.StringType,
+					},
+					"passed": schema.BoolAttribute{
+						Description: "whether the target passed: DSCs pass when in PRBS sync without errors, loopbacks and test signals when the state reports them applied. " +
+							"Null when the state of the target does not report the result",
+						Computed: true,
+					},
+				},
+			},
+		},
+		"passed": schema.BoolAttribute{
+			Description: "whether every target with a result passed, for use in postconditions. Null when no target has a result",
+			Computed:    true,
+		},
+	}
+}
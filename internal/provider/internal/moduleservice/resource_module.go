@@ -73,7 +73,7 @@ func (r *ModuleResource) Configure(_ context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r ModuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -0,0 +1,499 @@
+package moduleservice
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &ModulePolicyResource{}
+	_ resource.ResourceWithConfigure      = &ModulePolicyResource{}
+	_ resource.ResourceWithValidateConfig = &ModulePolicyResource{}
+	_ resource.ResourceWithModifyPlan     = &ModulePolicyResource{}
+)
+
+// NewModulePolicyResource is a helper function to simplify the provider implementation.
+func NewModulePolicyResource() resource.Resource {
+	return &ModulePolicyResource{}
+}
+
+// ModulePolicyResource applies a config template to all the modules which match a selector.
+type ModulePolicyResource struct {
+	client *ipm_pf.Client
+}
+
+type ModulePolicySelector struct {
+	Labels    types.Map    `tfsdk:"labels"`
+	NameRegex types.String `tfsdk:"name_regex"`
+}
+
+type ModulePolicyConfig struct {
+	Labels          types.Map    `tfsdk:"labels"`
+	MVLANMode       types.String `tfsdk:"m_vlan_mode"`
+	DebugPortAccess types.String `tfsdk:"debug_port_access"`
+}
+
+type ModulePolicyModule struct {
+	ModuleId types.String   `tfsdk:"module_id"`
+	Href     types.String   `tfsdk:"href"`
+	InSync   types.Bool     `tfsdk:"in_sync"`
+	Drift    []types.String `tfsdk:"drift"`
+}
+
+type ModulePolicyResourceData struct {
+	Id       types.String                  `tfsdk:"id"`
+	Selector *ModulePolicySelector         `tfsdk:"selector"`
+	Config   *ModulePolicyConfig           `tfsdk:"config"`
+	Modules  map[string]ModulePolicyModule `tfsdk:"modules"`
+}
+
+// policyModule is a module which matches the selector, with the settings of the template it does not have.
+type policyModule struct {
+	key    string
+	id     string
+	href   string
+	drift  []string
+	update map[string]interface{}
+}
+
+// Metadata returns the data source type name.
+func (r *ModulePolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_module_policy"
+}
+
+// Schema defines the schema for the data source.
+func (r *ModulePolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Applies a config template to all the modules which match a label or name selector. The selector is evaluated at each refresh, " +
+			"the modules which joined the set or drifted from the template are updated at the next apply. The template labels are merged with the labels of the module. " +
+			"Destroying the resource leaves the module configs as they are.",
+		Attributes: ModulePolicySchemaAttributes(),
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *ModulePolicyResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+// ValidateConfig checks the selector and that the template has a setting.
+func (r *ModulePolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data ModulePolicyResourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.Selector != nil {
+		if data.Selector.Labels.IsNull() && data.Selector.NameRegex.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("selector"), "ModulePolicyResource: Invalid selector",
+				"At least one of labels and name_regex must be specified, a selector matching all the modules is not allowed.")
+		}
+		if !data.Selector.NameRegex.IsNull() && !data.Selector.NameRegex.IsUnknown() {
+			if _, err := regexp.Compile(data.Selector.NameRegex.ValueString()); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("selector").AtName("name_regex"), "ModulePolicyResource: Invalid name_regex", err.Error())
+			}
+		}
+	}
+	if data.Config != nil && data.Config.Labels.IsNull() && data.Config.MVLANMode.IsNull() && data.Config.DebugPortAccess.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("config"), "ModulePolicyResource: Empty config", "At least one of labels, m_vlan_mode and debug_port_access must be specified.")
+	}
+}
+
+func (r *ModulePolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ModulePolicyResourceData
+
+	diags := req.Config.Get(ctx, &data)
+
+	tflog.Debug(ctx, "ModulePolicyResource: Create - ", map[string]interface{}{"ModulePolicyResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(uuid.New().String())
+	r.apply(&data, ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ModulePolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ModulePolicyResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "ModulePolicyResource: Read - ", map[string]interface{}{"ModulePolicyResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.read(&data, ctx, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ModulePolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ModulePolicyResourceData
+	var state ModulePolicyResourceData
+
+	// the planned modules are unknown, the settings are read from the config
+	diags := req.Config.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Debug(ctx, "ModulePolicyResource: Update", map[string]interface{}{"plan": plan, "state": state})
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = state.Id
+	r.apply(&plan, ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ModulePolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ModulePolicyResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "ModulePolicyResource: Delete", map[string]interface{}{"ModulePolicyResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+// ModifyPlan plans an update when modules joined or left the set, or drifted from the template.
+func (r *ModulePolicyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() || r.client == nil {
+		return
+	}
+	var plan ModulePolicyResourceData
+	if diags := req.Config.Get(ctx, &plan); diags.HasError() || plan.Selector == nil || plan.Config == nil {
+		return
+	}
+	var state ModulePolicyResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	modules, err := r.evaluate(&plan, ctx)
+	if err != nil {
+		resp.Diagnostics.AddWarning("ModulePolicyResource: Policy not evaluated", "Could not evaluate the selector: "+err.Error())
+		return
+	}
+	changed := len(modules) != len(state.Modules)
+	for _, module := range modules {
+		if _, ok := state.Modules[module.key]; !ok || len(module.update) > 0 {
+			changed = true
+		}
+	}
+	tflog.Debug(ctx, "ModulePolicyResource: ModifyPlan ## ", map[string]interface{}{"modules": len(modules), "changed": changed})
+	if changed {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("modules"), types.MapUnknown(types.ObjectType{AttrTypes: modulePolicyModuleAttributeType()}))...)
+	}
+}
+
+// apply updates the matching modules which drifted from the template.
+func (r *ModulePolicyResource) apply(plan *ModulePolicyResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	if plan.Selector == nil || plan.Config == nil {
+		diags.AddError(
+			"Error Apply ModulePolicyResource",
+			"Apply: Could not apply the policy, selector and config must be specified",
+		)
+		return
+	}
+
+	modules, err := r.evaluate(plan, ctx)
+	if err != nil {
+		diags.AddError(
+			"ModulePolicyResource: apply ##: Error Evaluate selector",
+			"Apply: Could not evaluate the selector, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	for _, module := range modules {
+		if len(module.update) == 0 {
+			continue
+		}
+		tflog.Debug(ctx, "ModulePolicyResource: apply ## ", map[string]interface{}{"module": module.key, "Update Request": module.update})
+		rb, err := json.Marshal(module.update)
+		if err != nil {
+			diags.AddError(
+				"ModulePolicyResource: apply ##: Error Update Module",
+				"Apply: Could not Marshal Module config, unexpected error: "+err.Error(),
+			)
+			return
+		}
+		_, err = r.client.ExecuteIPMHttpCommand("PUT", module.href, rb)
+		if err != nil && !strings.Contains(err.Error(), "status: 202") {
+			diags.AddError(
+				"ModulePolicyResource: apply ##: Error Update Module",
+				"Apply: Could not update Module "+module.key+", unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	r.read(plan, ctx, diags)
+}
+
+// evaluate returns the modules which match the selector, sorted by key.
+func (r *ModulePolicyResource) evaluate(plan *ModulePolicyResourceData, ctx context.Context) ([]policyModule, error) {
+	var nameRegex *regexp.Regexp
+	if !plan.Selector.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(plan.Selector.NameRegex.ValueString())
+		if err != nil {
+			return nil, err
+		}
+	}
+	selectorLabels := make(map[string]string)
+	if !plan.Selector.Labels.IsNull() {
+		plan.Selector.Labels.ElementsAs(ctx, &selectorLabels, false)
+	}
+
+	data, err := common.GetResources(r.client, "/modules?content=expanded")
+	if err != nil {
+		return nil, err
+	}
+	modules := []policyModule{}
+	for _, v := range data {
+		module, ok := v.(map[string]interface{})
+		if !ok || !common.MatchLabels(module, selectorLabels) {
+			continue
+		}
+		name, found := common.LookupValue(module, "state.moduleName")
+		if !found {
+			name, _ = common.LookupValue(module, "config.moduleName")
+		}
+		if nameRegex != nil && !nameRegex.MatchString(name) {
+			continue
+		}
+		modules = append(modules, plan.Config.compare(module, name, ctx))
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].key < modules[j].key })
+	return modules, nil
+}
+
+// compare returns the module with the template settings it does not have, as drift messages and as an update request.
+func (config *ModulePolicyConfig) compare(data map[string]interface{}, name string, ctx context.Context) policyModule {
+	module := policyModule{key: name, drift: []string{}, update: make(map[string]interface{})}
+	module.id, _ = common.LookupValue(data, "id")
+	module.href, _ = common.LookupValue(data, "href")
+	if module.key == "" {
+		module.key = module.id
+	}
+
+	compare := func(attribute string, key string, expected types.String) {
+		if expected.IsNull() || expected.IsUnknown() {
+			return
+		}
+		value, _ := common.LookupValue(data, "config."+key)
+		if value != expected.ValueString() {
+			module.drift = append(module.drift, attribute+" is \""+value+"\", expected \""+expected.ValueString()+"\"")
+			module.update[key] = expected.ValueString()
+		}
+	}
+	compare("m_vlan_mode", "mvlanMode", config.MVLANMode)
+	compare("debug_port_access", "debugPortAccess", config.DebugPortAccess)
+
+	if !config.Labels.IsNull() {
+		expected := make(map[string]string)
+		config.Labels.ElementsAs(ctx, &expected, false)
+		labels := make(map[string]string)
+		if moduleConfig, ok := data["config"].(map[string]interface{}); ok {
+			if current, ok := moduleConfig["labels"].(map[string]interface{}); ok {
+				for k, v := range current {
+					labels[k], _ = v.(string)
+				}
+			}
+		}
+		keys := make([]string, 0, len(expected))
+		for k := range expected {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		drifted := false
+		for _, k := range keys {
+			if value, ok := labels[k]; !ok || value != expected[k] {
+				module.drift = append(module.drift, "label "+k+" is \""+value+"\", expected \""+expected[k]+"\"")
+				labels[k] = expected[k]
+				drifted = true
+			}
+		}
+		if drifted {
+			module.update["labels"] = labels
+		}
+	}
+	return module
+}
+
+// read reports the modules which match the selector now, and their drift from the template.
+func (r *ModulePolicyResource) read(state *ModulePolicyResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	if state.Selector == nil || state.Config == nil {
+		diags.AddError(
+			"ModulePolicyResource: Error read ModulePolicyResource",
+			"Read: Could not read. selector and config are not specified",
+		)
+		return
+	}
+
+	modules, err := r.evaluate(state, ctx)
+	if err != nil {
+		diags.AddError(
+			"ModulePolicyResource: read ##: Error Get Modules",
+			"Read:Could not evaluate the selector, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	previous := state.Modules
+	state.Modules = make(map[string]ModulePolicyModule)
+	for _, module := range modules {
+		if _, ok := previous[module.key]; !ok && previous != nil {
+			tflog.Info(ctx, "ModulePolicyResource: read ## module joined the set", map[string]interface{}{"module": module.key})
+		}
+		drift := make([]types.String, 0, len(module.drift))
+		for _, d := range module.drift {
+			drift = append(drift, types.StringValue(d))
+		}
+		state.Modules[module.key] = ModulePolicyModule{
+			ModuleId: types.StringValue(module.id),
+			Href:     types.StringValue(module.href),
+			InSync:   types.BoolValue(len(module.drift) == 0),
+			Drift:    drift,
+		}
+	}
+	for key := range previous {
+		if _, ok := state.Modules[key]; !ok {
+			tflog.Info(ctx, "ModulePolicyResource: read ## module left the set", map[string]interface{}{"module": key})
+		}
+	}
+
+	tflog.Debug(ctx, "ModulePolicyResource: read SUCCESS ", map[string]interface{}{"state": state})
+}
+
+func modulePolicyModuleAttributeType() map[string]attr.Type {
+	return map[string]attr.Type{
+		"module_id": types.StringType,
+		"href":      types.StringType,
+		"in_sync":   types.BoolType,
+		"drift":     types.ListType{ElemType: types.StringType},
+	}
+}
+
+func ModulePolicySchemaAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Description: "Identifier of the policy",
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"selector": schema.SingleNestedAttribute{
+			Description: "Selects the modules which have all the labels and whose name matches the regular expression",
+			Required:    true,
+			Attributes: map[string]schema.Attribute{
+				"labels": schema.MapAttribute{
+					Description: "labels the modules must have",
+					Optional:    true,
+					ElementType: types.StringType,
+				},
+				"name_regex": schema.StringAttribute{
+					Description: "regular expression the module names must match",
+					Optional:    true,
+				},
+			},
+		},
+		"config": schema.SingleNestedAttribute{
+			Description: "The config template applied to the selected modules",
+			Required:    true,
+			Attributes: map[string]schema.Attribute{
+				"labels": schema.MapAttribute{
+					Description: "labels added to the modules, or replacing the value of the module labels with the same key",
+					Optional:    true,
+					ElementType: types.StringType,
+				},
+				"m_vlan_mode": schema.StringAttribute{
+					Description: "m_vlan_mode",
+					Optional:    true,
+				},
+				"debug_port_access": schema.StringAttribute{
+					Description: "debug_port_access",
+					Optional:    true,
+				},
+			},
+		},
+		"modules": schema.MapNestedAttribute{
+			Description: "The selected modules by module name, or by module id when the module has no name",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"module_id": schema.StringAttribute{
+						Description: "module id",
+						Computed:    true,
+					},
+					"href": schema.StringAttribute{
+						Description: "href of the module",
+						Computed:    true,
+					},
+					"in_sync": schema.BoolAttribute{
+						Description: "whether the module config matches the template",
+						Computed:    true,
+					},
+					"drift": schema.ListAttribute{
+						Description: "the settings of the module which differ from the template",
+						Computed:    true,
+						ElementType: types.StringType,
+					},
+				},
+			},
+		},
+	}
+}
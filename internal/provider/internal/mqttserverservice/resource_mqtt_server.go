@@ -76,7 +76,7 @@ func (r *MQTTResource) Configure(_ context.Context, req resource.ConfigureReques
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r MQTTResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
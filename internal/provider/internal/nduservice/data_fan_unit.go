@@ -0,0 +1,93 @@
+package nduservice
+
+import (
+	"context"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &FanUnitDataSource{}
+	_ datasource.DataSourceWithConfigure = &FanUnitDataSource{}
+)
+
+// NewFanUnitDataSource is a helper function to simplify the provider implementation.
+func NewFanUnitDataSource() datasource.DataSource {
+	return &FanUnitDataSource{}
+}
+
+// FanUnitDataSource reads the fan units of an NDU.
+type FanUnitDataSource struct {
+	client *ipm_pf.Client
+}
+
+type FanUnitDataSourceData struct {
+	NDUId    types.String `tfsdk:"ndu_id"`
+	FanUnits types.List   `tfsdk:"fan_units"`
+}
+
+// Metadata returns the data source type name.
+func (r *FanUnitDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ndu_fan_unit"
+}
+
+func (d *FanUnitDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the fan units of an NDU",
+		Attributes: map[string]schema.Attribute{
+			"ndu_id": schema.StringAttribute{
+				Description: "ndu ID",
+				Required:    true,
+			},
+			"fan_units": schema.ListAttribute{
+				Computed:    true,
+				ElementType: FanUnitObjectType(),
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *FanUnitDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+func (d *FanUnitDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	query := FanUnitDataSourceData{}
+	diags := req.Config.Get(ctx, &query)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Debug(ctx, "FanUnitDataSource: get fan units", map[string]interface{}{"ndu_id": query.NDUId.ValueString()})
+
+	data, err := common.GetResources(d.client, nduChildrenQuery(query.NDUId.ValueString(), "fanUnit"))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"FanUnitDataSource: read ##: Error Get fan units",
+			"Get:Could not get the NDU fan units, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	query.FanUnits = types.ListValueMust(FanUnitObjectType(), FanUnitObjectsValue(data))
+
+	diags = resp.State.Set(ctx, query)
+	resp.Diagnostics.Append(diags...)
+	tflog.Debug(ctx, "FanUnitDataSource: get ", map[string]interface{}{"FanUnits": query})
+}
+
+// nduChildrenQuery returns the expanded query of an NDU child collection such as fanUnit, pem or leds.
+func nduChildrenQuery(nduId string, collection string) string {
+	return "/ndus/" + nduId + "/" + collection + "?content=expanded"
+}
@@ -0,0 +1,88 @@
+package nduservice
+
+import (
+	"context"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &LEDsDataSource{}
+	_ datasource.DataSourceWithConfigure = &LEDsDataSource{}
+)
+
+// NewLEDsDataSource is a helper function to simplify the provider implementation.
+func NewLEDsDataSource() datasource.DataSource {
+	return &LEDsDataSource{}
+}
+
+// LEDsDataSource reads the LEDs of an NDU.
+type LEDsDataSource struct {
+	client *ipm_pf.Client
+}
+
+type LEDsDataSourceData struct {
+	NDUId types.String `tfsdk:"ndu_id"`
+	LEDs  types.List   `tfsdk:"leds"`
+}
+
+// Metadata returns the data source type name.
+func (r *LEDsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ndu_leds"
+}
+
+func (d *LEDsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the LEDs of an NDU",
+		Attributes: map[string]schema.Attribute{
+			"ndu_id": schema.StringAttribute{
+				Description: "ndu ID",
+				Required:    true,
+			},
+			"leds": schema.ListAttribute{
+				Computed:    true,
+				ElementType: LEDsObjectType(),
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *LEDsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+func (d *LEDsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	query := LEDsDataSourceData{}
+	diags := req.Config.Get(ctx, &query)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Debug(ctx, "LEDsDataSource: get LEDs", map[string]interface{}{"ndu_id": query.NDUId.ValueString()})
+
+	data, err := common.GetResources(d.client, nduChildrenQuery(query.NDUId.ValueString(), "leds"))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"LEDsDataSource: read ##: Error Get LEDs",
+			"Get:Could not get the NDU LEDs, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	query.LEDs = types.ListValueMust(LEDsObjectType(), LEDsObjectsValue(data))
+
+	diags = resp.State.Set(ctx, query)
+	resp.Diagnostics.Append(diags...)
+	tflog.Debug(ctx, "LEDsDataSource: get ", map[string]interface{}{"LEDs": query})
+}
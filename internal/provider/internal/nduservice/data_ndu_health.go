@@ -0,0 +1,322 @@
+package nduservice
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &NDUHealthDataSource{}
+	_ datasource.DataSourceWithConfigure = &NDUHealthDataSource{}
+)
+
+// NewNDUHealthDataSource is a helper function to simplify the provider implementation.
+func NewNDUHealthDataSource() datasource.DataSource {
+	return &NDUHealthDataSource{}
+}
+
+// NDUHealthDataSource summarizes the fan units, PEMs, LEDs and alarms of an NDU into one verdict.
+type NDUHealthDataSource struct {
+	client *ipm_pf.Client
+}
+
+type NDUHealthDataSourceData struct {
+	NDUId         types.String `tfsdk:"ndu_id"`
+	Verdict       types.String `tfsdk:"verdict"`
+	Healthy       types.Bool   `tfsdk:"healthy"`
+	Issues        types.List   `tfsdk:"issues"`
+	FanCount      types.Int64  `tfsdk:"fan_count"`
+	FailedFans    types.Int64  `tfsdk:"failed_fans"`
+	MinFanSpeed   types.Int64  `tfsdk:"min_fan_speed"`
+	MaxFanSpeed   types.Int64  `tfsdk:"max_fan_speed"`
+	PEMFeedStatus types.Map    `tfsdk:"pem_feed_status"`
+	LEDStates     types.Map    `tfsdk:"led_states"`
+	AlarmCounts   types.Map    `tfsdk:"alarm_counts"`
+}
+
+const (
+	nduHealthOk       = "ok"
+	nduHealthDegraded = "degraded"
+	nduHealthCritical = "critical"
+)
+
+// nduHealthRank orders the verdicts from best to worst.
+var nduHealthRank = map[string]int{nduHealthOk: 0, nduHealthDegraded: 1, nduHealthCritical: 2}
+
+// Metadata returns the data source type name.
+func (r *NDUHealthDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ndu_health"
+}
+
+func (d *NDUHealthDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Summarizes the fan units, PEMs, LEDs and active alarms of an NDU into a single health verdict, for use in check blocks and postconditions.",
+		Attributes: map[string]schema.Attribute{
+			"ndu_id": schema.StringAttribute{
+				Description: "ndu ID",
+				Required:    true,
+			},
+			"verdict": schema.StringAttribute{
+				Description: "overall health of the NDU: ok, degraded or critical",
+				Computed:    true,
+			},
+			"healthy": schema.BoolAttribute{
+				Description: "true when the verdict is ok",
+				Computed:    true,
+			},
+			"issues": schema.ListAttribute{
+				Description: "the findings that lowered the verdict",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"fan_count": schema.Int64Attribute{
+				Description: "number of fans of all fan units",
+				Computed:    true,
+			},
+			"failed_fans": schema.Int64Attribute{
+				Description: "number of fans that are faulty or stopped",
+				Computed:    true,
+			},
+			"min_fan_speed": schema.Int64Attribute{
+				Description: "lowest reported fan speed",
+				Computed:    true,
+			},
+			"max_fan_speed": schema.Int64Attribute{
+				Description: "highest reported fan speed",
+				Computed:    true,
+			},
+			"pem_feed_status": schema.MapAttribute{
+				Description: "feed status per PEM AID, the feeds of a PEM joined by commas",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"led_states": schema.MapAttribute{
+				Description: "state per LED ID",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"alarm_counts": schema.MapAttribute{
+				Description: "number of active alarms of the NDU per severity",
+				Computed:    true,
+				ElementType: types.Int64Type,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *NDUHealthDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+func (d *NDUHealthDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	query := NDUHealthDataSourceData{}
+	diags := req.Config.Get(ctx, &query)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	nduId := query.NDUId.ValueString()
+	tflog.Debug(ctx, "NDUHealthDataSource: get health", map[string]interface{}{"ndu_id": nduId})
+
+	collections := map[string][]interface{}{}
+	for _, collection := range []string{"fanUnit", "pem", "leds"} {
+		data, err := common.GetResources(d.client, nduChildrenQuery(nduId, collection))
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"NDUHealthDataSource: read ##: Error Get "+collection,
+				"Get:Could not get the NDU "+collection+", unexpected error: "+err.Error(),
+			)
+			return
+		}
+		collections[collection] = data
+	}
+	alarms, err := common.GetAlarms(d.client, common.AlarmFilter{DeviceId: nduId})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"NDUHealthDataSource: read ##: Error Get alarms",
+			"Get:Could not get the NDU alarms, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	tflog.Debug(ctx, "NDUHealthDataSource: read ## ", map[string]interface{}{"collections": collections, "alarms": alarms})
+
+	health := nduHealth{verdict: nduHealthOk}
+	health.checkFanUnits(ctx, collections["fanUnit"], &query)
+	health.checkPEMs(ctx, collections["pem"], &query)
+	health.checkLEDs(ctx, collections["leds"], &query)
+	health.checkAlarms(ctx, alarms, &query)
+
+	query.Verdict = types.StringValue(health.verdict)
+	query.Healthy = types.BoolValue(health.verdict == nduHealthOk)
+	query.Issues, diags = types.ListValueFrom(ctx, types.StringType, health.issues)
+	resp.Diagnostics.Append(diags...)
+
+	diags = resp.State.Set(ctx, query)
+	resp.Diagnostics.Append(diags...)
+	tflog.Debug(ctx, "NDUHealthDataSource: get ", map[string]interface{}{"NDUHealth": query})
+}
+
+// nduHealth collects the findings of the health checks and the worst verdict among them.
+type nduHealth struct {
+	verdict string
+	issues  []string
+}
+
+func (h *nduHealth) report(verdict string, issue string) {
+	if nduHealthRank[verdict] > nduHealthRank[h.verdict] {
+		h.verdict = verdict
+	}
+	h.issues = append(h.issues, issue)
+}
+
+func (h *nduHealth) checkFanUnits(ctx context.Context, fanUnits []interface{}, query *NDUHealthDataSourceData) {
+	fanCount, failedFans := 0, 0
+	minSpeed, maxSpeed := types.Int64Null(), types.Int64Null()
+	if len(fanUnits) == 0 {
+		h.report(nduHealthCritical, "no fan unit reported")
+	}
+	for _, v := range fanUnits {
+		fanUnit, _ := v.(map[string]interface{})
+		aid, _ := common.LookupValue(fanUnit, "state.fanAid")
+		if state, ok := common.LookupValue(fanUnit, "state.state"); ok && faultyHardwareState(state) {
+			h.report(nduHealthCritical, "fan unit "+aid+" is "+state)
+		}
+		unitState, _ := fanUnit["state"].(map[string]interface{})
+		fans, _ := unitState["fans"].([]interface{})
+		unitFailed := 0
+		for i, f := range fans {
+			fan, _ := f.(map[string]interface{})
+			fanCount++
+			if state, _ := fan["state"].(string); faultyHardwareState(state) {
+				unitFailed++
+				h.report(nduHealthDegraded, fmt.Sprintf("fan %d of fan unit %s is %s", i+1, aid, state))
+				continue
+			}
+			speed, ok := fan["speed"].(float64)
+			if !ok {
+				continue
+			}
+			if speed <= 0 {
+				unitFailed++
+				h.report(nduHealthDegraded, fmt.Sprintf("fan %d of fan unit %s is stopped", i+1, aid))
+			}
+			if minSpeed.IsNull() || int64(speed) < minSpeed.ValueInt64() {
+				minSpeed = types.Int64Value(int64(speed))
+			}
+			if maxSpeed.IsNull() || int64(speed) > maxSpeed.ValueInt64() {
+				maxSpeed = types.Int64Value(int64(speed))
+			}
+		}
+		if len(fans) > 0 && unitFailed == len(fans) {
+			h.report(nduHealthCritical, "all fans of fan unit "+aid+" failed")
+		}
+		failedFans += unitFailed
+	}
+	query.FanCount = types.Int64Value(int64(fanCount))
+	query.FailedFans = types.Int64Value(int64(failedFans))
+	query.MinFanSpeed = minSpeed
+	query.MaxFanSpeed = maxSpeed
+}
+
+func (h *nduHealth) checkPEMs(ctx context.Context, pems []interface{}, query *NDUHealthDataSourceData) {
+	feedStatus := map[string]string{}
+	if len(pems) == 0 {
+		h.report(nduHealthCritical, "no PEM reported")
+	}
+	for _, v := range pems {
+		pem, _ := v.(map[string]interface{})
+		aid, _ := common.LookupValue(pem, "state.pemAid")
+		pemState, _ := pem["state"].(map[string]interface{})
+		feeds, _ := pemState["feedStatus"].([]interface{})
+		statuses := []string{}
+		failed := 0
+		for i, f := range feeds {
+			status, _ := f.(string)
+			statuses = append(statuses, status)
+			if faultyHardwareState(status) {
+				failed++
+				h.report(nduHealthDegraded, fmt.Sprintf("feed %d of PEM %s is %s", i+1, aid, status))
+			}
+		}
+		if len(feeds) > 0 && failed == len(feeds) {
+			h.report(nduHealthCritical, "all feeds of PEM "+aid+" failed")
+		}
+		feedStatus[aid] = strings.Join(statuses, ",")
+	}
+	query.PEMFeedStatus, _ = types.MapValueFrom(ctx, types.StringType, feedStatus)
+}
+
+func (h *nduHealth) checkLEDs(ctx context.Context, leds []interface{}, query *NDUHealthDataSourceData) {
+	ledStates := map[string]string{}
+	for _, v := range leds {
+		led, _ := v.(map[string]interface{})
+		ledsState, _ := led["state"].(map[string]interface{})
+		states, _ := ledsState["ledStates"].([]interface{})
+		for _, s := range states {
+			ledState, _ := s.(map[string]interface{})
+			id, _ := ledState["ledId"].(string)
+			state, _ := ledState["state"].(string)
+			ledStates[id] = state
+			lower := strings.ToLower(state)
+			switch {
+			case strings.Contains(lower, "red"):
+				h.report(nduHealthCritical, "LED "+id+" is "+state)
+			case strings.Contains(lower, "amber") || strings.Contains(lower, "yellow"):
+				h.report(nduHealthDegraded, "LED "+id+" is "+state)
+			}
+		}
+	}
+	query.LEDStates, _ = types.MapValueFrom(ctx, types.StringType, ledStates)
+}
+
+func (h *nduHealth) checkAlarms(ctx context.Context, alarms []common.Alarm, query *NDUHealthDataSourceData) {
+	counts := map[string]int64{}
+	for _, alarm := range alarms {
+		severity := alarm.Severity
+		if severity == "" {
+			severity = "unknown"
+		}
+		counts[severity]++
+	}
+	severities := make([]string, 0, len(counts))
+	for severity := range counts {
+		severities = append(severities, severity)
+	}
+	sort.Strings(severities)
+	for _, severity := range severities {
+		switch severity {
+		case "critical":
+			h.report(nduHealthCritical, fmt.Sprintf("%d critical alarms", counts[severity]))
+		case "major":
+			h.report(nduHealthDegraded, fmt.Sprintf("%d major alarms", counts[severity]))
+		}
+	}
+	query.AlarmCounts, _ = types.MapValueFrom(ctx, types.Int64Type, counts)
+}
+
+// faultyHardwareState reports whether a fan, fan unit or PEM feed state denotes a fault.
+func faultyHardwareState(state string) bool {
+	lower := strings.ToLower(state)
+	for _, fault := range []string{"fail", "fault", "absent", "missing", "down", "lost", "alarm"} {
+		if strings.Contains(lower, fault) {
+			return true
+		}
+	}
+	return false
+}
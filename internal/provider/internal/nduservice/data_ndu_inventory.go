@@ -0,0 +1,316 @@
+package nduservice
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &NDUInventoryDataSource{}
+	_ datasource.DataSourceWithConfigure = &NDUInventoryDataSource{}
+)
+
+// NewNDUInventoryDataSource is a helper function to simplify the provider implementation.
+func NewNDUInventoryDataSource() datasource.DataSource {
+	return &NDUInventoryDataSource{}
+}
+
+// NDUInventoryDataSource compares the hardware expected in the ports of an NDU with the plugged hardware.
+type NDUInventoryDataSource struct {
+	client *ipm_pf.Client
+}
+
+type NDUInventoryDataSourceData struct {
+	NDUId      types.String `tfsdk:"ndu_id"`
+	Slots      types.List   `tfsdk:"slots"`
+	Mismatches types.List   `tfsdk:"mismatches"`
+	Verified   types.Bool   `tfsdk:"verified"`
+}
+
+// inventorySlot is a port, or a TOM, XR, EDFA or VOA of a port, with its expected and plugged hardware.
+type inventorySlot struct {
+	port            string
+	kind            string
+	href            string
+	name            string
+	requiredType    string
+	requiredSubType string
+	actualType      string
+	actualSubType   string
+	partNumber      string
+	serialNumber    string
+	hardwareVersion string
+	firmwareVersion string
+	vendor          string
+	lifecycleState  string
+}
+
+// inventoryMismatch is a slot whose plugged hardware is not the expected one.
+type inventoryMismatch struct {
+	port     string
+	kind     string
+	href     string
+	reason   string
+	expected string
+	actual   string
+}
+
+// the port collections of the children, by kind
+var inventoryCollections = map[string]string{
+	chassisTOM:  "toms",
+	chassisXR:   "xrs",
+	chassisEDFA: "edfas",
+	chassisVOA:  "voas",
+}
+
+// Metadata returns the data source type name.
+func (r *NDUInventoryDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ndu_inventory"
+}
+
+func (d *NDUInventoryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the ports of an NDU and their TOMs, XRs, EDFAs and VOAs, with the expected type and the plugged hardware, and the slots whose hardware is missing or is not the expected one",
+		Attributes: map[string]schema.Attribute{
+			"ndu_id": schema.StringAttribute{
+				Description: "ndu ID",
+				Required:    true,
+			},
+			"slots": schema.ListAttribute{
+				Description: "the ports and their TOMs, XRs, EDFAs and VOAs, by port col id",
+				Computed:    true,
+				ElementType: InventorySlotObjectType(),
+			},
+			"mismatches": schema.ListAttribute{
+				Description: "the slots whose hardware is missing, or whose type or sub type is not the required one. The reason is missing, type or sub_type.",
+				Computed:    true,
+				ElementType: InventoryMismatchObjectType(),
+			},
+			"verified": schema.BoolAttribute{
+				Description: "whether the plugged hardware is the expected one in all the slots",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *NDUInventoryDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+func (d *NDUInventoryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	query := NDUInventoryDataSourceData{}
+	diags := req.Config.Get(ctx, &query)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Debug(ctx, "NDUInventoryDataSource: get inventory", map[string]interface{}{"ndu_id": query.NDUId.ValueString()})
+
+	ports, err := common.GetResources(d.client, nduChildrenQuery(query.NDUId.ValueString(), "ports"))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"NDUInventoryDataSource: read ##: Error Get ports",
+			"Get:Could not get the NDU ports, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	slots := inventorySlots(ports)
+	mismatches := []inventoryMismatch{}
+	for _, slot := range slots {
+		if mismatch, ok := slot.compare(); ok {
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+
+	query.Slots = types.ListValueMust(InventorySlotObjectType(), InventorySlotObjectsValue(slots))
+	query.Mismatches = types.ListValueMust(InventoryMismatchObjectType(), InventoryMismatchObjectsValue(mismatches))
+	query.Verified = types.BoolValue(len(mismatches) == 0)
+
+	diags = resp.State.Set(ctx, query)
+	resp.Diagnostics.Append(diags...)
+	tflog.Debug(ctx, "NDUInventoryDataSource: get ", map[string]interface{}{"slots": len(slots), "mismatches": len(mismatches)})
+}
+
+// inventorySlots returns the ports and their children, sorted by port col id.
+func inventorySlots(ports []interface{}) []inventorySlot {
+	slots := []inventorySlot{}
+	for _, p := range ports {
+		port, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		portColId := ""
+		if colId, ok := port["colid"].(float64); ok {
+			portColId = strconv.FormatInt(int64(colId), 10)
+		}
+		slot := inventorySlotOf(portColId, "port", port)
+		slot.actualType, _ = common.LookupValue(port, "state.portType")
+		slots = append(slots, slot)
+		for _, kind := range chassisChildKinds {
+			children, _ := port[inventoryCollections[kind]].([]interface{})
+			for _, c := range children {
+				if child, ok := c.(map[string]interface{}); ok {
+					slots = append(slots, inventorySlotOf(portColId, kind, child))
+				}
+			}
+		}
+	}
+	sort.SliceStable(slots, func(i, j int) bool {
+		a, _ := strconv.Atoi(slots[i].port)
+		b, _ := strconv.Atoi(slots[j].port)
+		return a < b
+	})
+	return slots
+}
+
+func inventorySlotOf(port string, kind string, data map[string]interface{}) inventorySlot {
+	value := func(keys ...string) string {
+		for _, key := range keys {
+			if v, ok := common.LookupValue(data, key); ok && v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	return inventorySlot{
+		port:            port,
+		kind:            kind,
+		href:            value("href"),
+		name:            value("config.name", "state.name"),
+		requiredType:    value("config.requiredType", "state.requiredType"),
+		requiredSubType: value("config.requiredSubType", "state.requiredSubType"),
+		actualType:      value("state.inventory.actualType"),
+		actualSubType:   value("state.inventory.actualSubType"),
+		partNumber:      value("state.inventory.partNumber"),
+		serialNumber:    value("state.inventory.serialNumber"),
+		hardwareVersion: value("state.inventory.hardwareVersion"),
+		firmwareVersion: value("state.inventory.firmwareVersion", "state.firmwareVersion"),
+		vendor:          value("state.inventory.vendor"),
+		lifecycleState:  value("state.lifecycleState"),
+	}
+}
+
+// compare returns the mismatch of a slot which expects hardware, when the plugged hardware is missing or has another type.
+func (slot inventorySlot) compare() (inventoryMismatch, bool) {
+	mismatch := inventoryMismatch{port: slot.port, kind: slot.kind, href: slot.href}
+	switch {
+	case slot.kind == "port" || slot.requiredType == "":
+		return mismatch, false
+	case slot.actualType == "" && slot.serialNumber == "":
+		mismatch.reason, mismatch.expected = "missing", slot.requiredType
+	case !strings.EqualFold(slot.requiredType, slot.actualType):
+		mismatch.reason, mismatch.expected, mismatch.actual = "type", slot.requiredType, slot.actualType
+	case slot.requiredSubType != "" && !strings.EqualFold(slot.requiredSubType, slot.actualSubType):
+		mismatch.reason, mismatch.expected, mismatch.actual = "sub_type", slot.requiredSubType, slot.actualSubType
+	default:
+		return mismatch, false
+	}
+	return mismatch, true
+}
+
+func InventorySlotObjectType() types.ObjectType {
+	return types.ObjectType{
+		AttrTypes: InventorySlotAttributeType(),
+	}
+}
+
+func InventorySlotObjectsValue(slots []inventorySlot) []attr.Value {
+	values := []attr.Value{}
+	for _, slot := range slots {
+		values = append(values, types.ObjectValueMust(InventorySlotAttributeType(), InventorySlotAttributeValue(slot)))
+	}
+	return values
+}
+
+func InventorySlotAttributeType() map[string]attr.Type {
+	return map[string]attr.Type{
+		"port_col_id":       types.StringType,
+		"kind":              types.StringType,
+		"href":              types.StringType,
+		"name":              types.StringType,
+		"required_type":     types.StringType,
+		"required_sub_type": types.StringType,
+		"actual_type":       types.StringType,
+		"actual_sub_type":   types.StringType,
+		"part_number":       types.StringType,
+		"serial_number":     types.StringType,
+		"hardware_version":  types.StringType,
+		"firmware_version":  types.StringType,
+		"vendor":            types.StringType,
+		"lifecycle_state":   types.StringType,
+	}
+}
+
+func InventorySlotAttributeValue(slot inventorySlot) map[string]attr.Value {
+	return map[string]attr.Value{
+		"port_col_id":       common.OptionalString(slot.port),
+		"kind":              types.StringValue(slot.kind),
+		"href":              common.OptionalString(slot.href),
+		"name":              common.OptionalString(slot.name),
+		"required_type":     common.OptionalString(slot.requiredType),
+		"required_sub_type": common.OptionalString(slot.requiredSubType),
+		"actual_type":       common.OptionalString(slot.actualType),
+		"actual_sub_type":   common.OptionalString(slot.actualSubType),
+		"part_number":       common.OptionalString(slot.partNumber),
+		"serial_number":     common.OptionalString(slot.serialNumber),
+		"hardware_version":  common.OptionalString(slot.hardwareVersion),
+		"firmware_version":  common.OptionalString(slot.firmwareVersion),
+		"vendor":            common.OptionalString(slot.vendor),
+		"lifecycle_state":   common.OptionalString(slot.lifecycleState),
+	}
+}
+
+func InventoryMismatchObjectType() types.ObjectType {
+	return types.ObjectType{
+		AttrTypes: InventoryMismatchAttributeType(),
+	}
+}
+
+func InventoryMismatchObjectsValue(mismatches []inventoryMismatch) []attr.Value {
+	values := []attr.Value{}
+	for _, mismatch := range mismatches {
+		values = append(values, types.ObjectValueMust(InventoryMismatchAttributeType(), InventoryMismatchAttributeValue(mismatch)))
+	}
+	return values
+}
+
+func InventoryMismatchAttributeType() map[string]attr.Type {
+	return map[string]attr.Type{
+		"port_col_id": types.StringType,
+		"kind":        types.StringType,
+		"href":        types.StringType,
+		"reason":      types.StringType,
+		"expected":    types.StringType,
+		"actual":      types.StringType,
+	}
+}
+
+func InventoryMismatchAttributeValue(mismatch inventoryMismatch) map[string]attr.Value {
+	return map[string]attr.Value{
+		"port_col_id": common.OptionalString(mismatch.port),
+		"kind":        types.StringValue(mismatch.kind),
+		"href":        common.OptionalString(mismatch.href),
+		"reason":      types.StringValue(mismatch.reason),
+		"expected":    common.OptionalString(mismatch.expected),
+		"actual":      common.OptionalString(mismatch.actual),
+	}
+}
@@ -0,0 +1,41 @@
+package nduservice
+
+import (
+	"testing"
+)
+
+func TestInventorySlotCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		slot     inventorySlot
+		mismatch bool
+		reason   string
+		expected string
+		actual   string
+	}{
+		{"port", inventorySlot{kind: "port", requiredType: "QSFP28", actualType: "QSFP-DD"}, false, "", "", ""},
+		{"nothing expected", inventorySlot{kind: "tom", actualType: "QSFP28"}, false, "", "", ""},
+		{"expected hardware", inventorySlot{kind: "tom", requiredType: "QSFP28", actualType: "qsfp28", serialNumber: "S1"}, false, "", "", ""},
+		{"expected sub type", inventorySlot{kind: "tom", requiredType: "QSFP28", requiredSubType: "LR4", actualType: "QSFP28", actualSubType: "lr4"}, false, "", "", ""},
+		{"any sub type", inventorySlot{kind: "tom", requiredType: "QSFP28", actualType: "QSFP28", actualSubType: "SR4"}, false, "", "", ""},
+		{"missing", inventorySlot{kind: "tom", requiredType: "QSFP28"}, true, "missing", "QSFP28", ""},
+		{"plugged without type", inventorySlot{kind: "edfa", requiredType: "EDFA", serialNumber: "S1"}, true, "type", "EDFA", ""},
+		{"other type", inventorySlot{kind: "tom", requiredType: "QSFP28", actualType: "QSFP-DD"}, true, "type", "QSFP28", "QSFP-DD"},
+		{"other sub type", inventorySlot{kind: "tom", requiredType: "QSFP28", requiredSubType: "LR4", actualType: "QSFP28", actualSubType: "SR4"}, true, "sub_type", "LR4", "SR4"},
+	}
+	for _, test := range tests {
+		test.slot.port, test.slot.href = "1", "/ndus/n1/ports/1/toms/1"
+		mismatch, found := test.slot.compare()
+		if found != test.mismatch {
+			t.Errorf("%s: compare() found = %v, want %v", test.name, found, test.mismatch)
+			continue
+		}
+		if !found {
+			continue
+		}
+		want := inventoryMismatch{port: "1", kind: test.slot.kind, href: test.slot.href, reason: test.reason, expected: test.expected, actual: test.actual}
+		if mismatch != want {
+			t.Errorf("%s: compare() = %+v, want %+v", test.name, mismatch, want)
+		}
+	}
+}
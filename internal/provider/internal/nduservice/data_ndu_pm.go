@@ -0,0 +1,115 @@
+package nduservice
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &NDUPMDataSource{}
+	_ datasource.DataSourceWithConfigure = &NDUPMDataSource{}
+)
+
+// NewNDUPMDataSource is a helper function to simplify the provider implementation.
+func NewNDUPMDataSource() datasource.DataSource {
+	return &NDUPMDataSource{}
+}
+
+// NDUPMDataSource reads the PM counters of NDU line PTPs, carriers, ethernet clients and EDFAs.
+type NDUPMDataSource struct {
+	client *ipm_pf.Client
+}
+
+// the types of the NDU objects addressed by a common.ResourceIdentifier
+const (
+	nduLinePTP        = "line_ptp"
+	nduCarrier        = "carrier"
+	nduEthernetClient = "ethernet_client"
+	nduEDFA           = "edfa"
+)
+
+// Metadata returns the data source type name.
+func (r *NDUPMDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ndu_pm"
+}
+
+func (d *NDUPMDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the current and historical PM bins of NDU line PTPs, carriers, ethernet clients and EDFAs. " +
+			"A line PTP and an EDFA are identified by the port col id as parent_col_id, a carrier by the port col id as grand_parent_col_id and the line PTP col id as parent_col_id.",
+		Attributes: common.PMDataSourceSchemaAttributes([]string{nduLinePTP, nduCarrier, nduEthernetClient, nduEDFA}),
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *NDUPMDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+func (d *NDUPMDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	query := common.PMDataSourceData{}
+	diags := req.Config.Get(ctx, &query)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Debug(ctx, "NDUPMDataSource: get PM", map[string]interface{}{"query": query})
+
+	bins, err := common.ReadPMBins(d.client, &query, func(object common.PMObject) (string, error) {
+		return nduObjectHref(object.Type.ValueString(), object.Identifier)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"NDUPMDataSource: read ##: Error Get PM",
+			"Get:Could not get the PM bins, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	query.Bins = types.ListValueMust(common.PMBinObjectType(), common.PMBinObjectsValue(bins))
+
+	diags = resp.State.Set(ctx, query)
+	resp.Diagnostics.Append(diags...)
+	tflog.Debug(ctx, "NDUPMDataSource: get ", map[string]interface{}{"bins": len(bins)})
+}
+
+// nduObjectHref returns the href of an NDU object, from its identifier href or from its col ids.
+func nduObjectHref(objectType string, id common.ResourceIdentifier) (string, error) {
+	if !id.Href.IsNull() {
+		return id.Href.ValueString(), nil
+	}
+	if id.DeviceId.IsNull() || id.ColId.IsNull() {
+		return "", fmt.Errorf("the %s identifier needs an href or a device_id and col ids", objectType)
+	}
+	ndu := "/ndus/" + id.DeviceId.ValueString()
+	switch objectType {
+	case nduLinePTP, nduEDFA:
+		if id.ParentColId.IsNull() {
+			return "", fmt.Errorf("the %s identifier needs the port col id as parent_col_id", objectType)
+		}
+		if objectType == nduEDFA {
+			return ndu + "/ports/" + id.ParentColId.ValueString() + "/edfa/" + id.ColId.ValueString(), nil
+		}
+		return ndu + "/ports/" + id.ParentColId.ValueString() + "/linePtps/" + id.ColId.ValueString(), nil
+	case nduCarrier:
+		if id.ParentColId.IsNull() || id.GrandParentColId.IsNull() {
+			return "", fmt.Errorf("the carrier identifier needs the port col id as grand_parent_col_id and the line PTP col id as parent_col_id")
+		}
+		return ndu + "/ports/" + id.GrandParentColId.ValueString() + "/linePtps/" + id.ParentColId.ValueString() + "/carrier/" + id.ColId.ValueString(), nil
+	case nduEthernetClient:
+		return ndu + "/ethernets/" + id.ColId.ValueString(), nil
+	}
+	return "", fmt.Errorf("the type %q is not one of %s, %s, %s or %s", objectType, nduLinePTP, nduCarrier, nduEthernetClient, nduEDFA)
+}
@@ -0,0 +1,88 @@
+package nduservice
+
+import (
+	"context"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &PEMDataSource{}
+	_ datasource.DataSourceWithConfigure = &PEMDataSource{}
+)
+
+// NewPEMDataSource is a helper function to simplify the provider implementation.
+func NewPEMDataSource() datasource.DataSource {
+	return &PEMDataSource{}
+}
+
+// PEMDataSource reads the power entry modules of an NDU.
+type PEMDataSource struct {
+	client *ipm_pf.Client
+}
+
+type PEMDataSourceData struct {
+	NDUId types.String `tfsdk:"ndu_id"`
+	PEMs  types.List   `tfsdk:"pems"`
+}
+
+// Metadata returns the data source type name.
+func (r *PEMDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ndu_pem"
+}
+
+func (d *PEMDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches the power entry modules (PEMs) of an NDU",
+		Attributes: map[string]schema.Attribute{
+			"ndu_id": schema.StringAttribute{
+				Description: "ndu ID",
+				Required:    true,
+			},
+			"pems": schema.ListAttribute{
+				Computed:    true,
+				ElementType: PEMObjectType(),
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *PEMDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+func (d *PEMDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	query := PEMDataSourceData{}
+	diags := req.Config.Get(ctx, &query)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Debug(ctx, "PEMDataSource: get PEMs", map[string]interface{}{"ndu_id": query.NDUId.ValueString()})
+
+	data, err := common.GetResources(d.client, nduChildrenQuery(query.NDUId.ValueString(), "pem"))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"PEMDataSource: read ##: Error Get PEMs",
+			"Get:Could not get the NDU PEMs, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	query.PEMs = types.ListValueMust(PEMObjectType(), PEMObjectsValue(data))
+
+	diags = resp.State.Set(ctx, query)
+	resp.Diagnostics.Append(diags...)
+	tflog.Debug(ctx, "PEMDataSource: get ", map[string]interface{}{"PEMs": query})
+}
@@ -72,7 +72,7 @@ func (r *EDFAResource) Configure(_ context.Context, req resource.ConfigureReques
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r EDFAResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
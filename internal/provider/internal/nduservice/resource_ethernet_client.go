@@ -80,7 +80,7 @@ func (r *EClientResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r EClientResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
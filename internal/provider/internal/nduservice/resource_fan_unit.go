@@ -50,8 +50,9 @@ func (r *FanUnitResource) Metadata(_ context.Context, req resource.MetadataReque
 func (r *FanUnitResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	//type FanUnitResourceData struct
 	resp.Schema = schema.Schema{
-		Description: "Manages an NDU fan",
-		Attributes:  FanUnitResourceSchemaAttributes(),
+		Description:        "Manages an NDU fan",
+		DeprecationMessage: "The resource only reads state, use the ipm_ndu_fan_unit data source instead.",
+		Attributes:         FanUnitResourceSchemaAttributes(),
 	}
 }
 
@@ -61,7 +62,7 @@ func (r *FanUnitResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r FanUnitResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -50,8 +50,9 @@ func (r *LEDsResource) Metadata(_ context.Context, req resource.MetadataRequest,
 func (r *LEDsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	//type LEDsResourceData struct
 	resp.Schema = schema.Schema{
-		Description: "Manages an NDU leds",
-		Attributes:  LEDsResourceSchemaAttributes(),
+		Description:        "Manages an NDU leds",
+		DeprecationMessage: "The resource only reads state, use the ipm_ndu_leds data source instead.",
+		Attributes:         LEDsResourceSchemaAttributes(),
 	}
 }
 
@@ -61,7 +62,7 @@ func (r *LEDsResource) Configure(_ context.Context, req resource.ConfigureReques
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r LEDsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
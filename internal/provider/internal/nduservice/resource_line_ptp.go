@@ -69,7 +69,7 @@ func (r *LinePTPResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r LinePTPResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
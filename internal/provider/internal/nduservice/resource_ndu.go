@@ -86,7 +86,7 @@ func (r *NDUResource) Configure(_ context.Context, req resource.ConfigureRequest
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r NDUResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -0,0 +1,577 @@
+package nduservice
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &NDUChassisResource{}
+	_ resource.ResourceWithConfigure      = &NDUChassisResource{}
+	_ resource.ResourceWithValidateConfig = &NDUChassisResource{}
+)
+
+// NewNDUChassisResource is a helper function to simplify the provider implementation.
+func NewNDUChassisResource() resource.Resource {
+	return &NDUChassisResource{}
+}
+
+// NDUChassisResource provisions the ports of an NDU and the TOMs, XRs, EDFAs and VOAs plugged in them.
+type NDUChassisResource struct {
+	client *ipm_pf.Client
+}
+
+// the kinds of the port children, in the order they are created. They are deleted in the reverse order.
+const (
+	chassisTOM  = "tom"
+	chassisXR   = "xr"
+	chassisEDFA = "edfa"
+	chassisVOA  = "voa"
+)
+
+var chassisChildKinds = []string{chassisTOM, chassisXR, chassisEDFA, chassisVOA}
+
+// the col id of the XR of a port
+const chassisXRColId = "1"
+
+type NDUChassisPort struct {
+	Name        types.String `tfsdk:"name"`
+	ConnectedTo types.String `tfsdk:"connected_to"`
+	TOM         *TOMConfig   `tfsdk:"tom"`
+	XR          *XRConfig    `tfsdk:"xr"`
+	EDFA        *EDFAConfig  `tfsdk:"edfa"`
+	VOA         *VOAConfig   `tfsdk:"voa"`
+}
+
+type NDUChassisChild struct {
+	Href           types.String `tfsdk:"href"`
+	LifecycleState types.String `tfsdk:"lifecycle_state"`
+	ActualType     types.String `tfsdk:"actual_type"`
+	ActualSubType  types.String `tfsdk:"actual_sub_type"`
+}
+
+type NDUChassisResourceData struct {
+	Id       types.String               `tfsdk:"id"`
+	NDUId    types.String               `tfsdk:"ndu_id"`
+	Ports    map[string]*NDUChassisPort `tfsdk:"ports"`
+	Children map[string]NDUChassisChild `tfsdk:"children"`
+}
+
+// Metadata returns the data source type name.
+func (r *NDUChassisResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ndu_chassis"
+}
+
+// Schema defines the schema for the data source.
+func (r *NDUChassisResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Provisions the ports of an NDU, and the TOM, XR, EDFA and VOA expected in each port. In a port, the TOM is created first, then the XR, the EDFA " +
+			"and the VOA, and they are deleted in the reverse order. The XR of a port has col id " + chassisXRColId + ", the VOA has the col id of its port. " +
+			"The port settings are left as they are when a port is removed.",
+		Attributes: NDUChassisSchemaAttributes(),
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *NDUChassisResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+// ValidateConfig checks that the ports are keyed by their col id.
+func (r *NDUChassisResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	// only the port keys are checked, the port settings may be unknown until other resources are applied
+	var ports types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("ports"), &ports)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for key := range ports.Elements() {
+		if _, err := strconv.Atoi(key); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ports").AtMapKey(key), "NDUChassisResource: Invalid port", "The ports are keyed by their col id, "+key+" is not a number.")
+		}
+	}
+}
+
+func (r *NDUChassisResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data NDUChassisResourceData
+
+	diags := req.Config.Get(ctx, &data)
+
+	tflog.Debug(ctx, "NDUChassisResource: Create - ", map[string]interface{}{"NDUChassisResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = data.NDUId
+	r.apply(&data, &NDUChassisResourceData{}, ctx, &resp.Diagnostics)
+
+	// the children which are created before an error are kept in the state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *NDUChassisResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data NDUChassisResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "NDUChassisResource: Read - ", map[string]interface{}{"NDUChassisResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.read(&data, ctx, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *NDUChassisResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan NDUChassisResourceData
+	var state NDUChassisResourceData
+
+	// the planned children are unknown, the settings are read from the config
+	diags := req.Config.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Debug(ctx, "NDUChassisResource: Update", map[string]interface{}{"plan": plan, "state": state})
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = state.Id
+	r.apply(&plan, &state, ctx, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *NDUChassisResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data NDUChassisResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "NDUChassisResource: Delete", map[string]interface{}{"NDUChassisResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ports := sortedChassisPorts(data.Children)
+	for i := len(ports) - 1; i >= 0; i-- {
+		for k := len(chassisChildKinds) - 1; k >= 0; k-- {
+			key := ports[i] + "/" + chassisChildKinds[k]
+			if child, ok := data.Children[key]; ok {
+				r.child("delete", chassisChildKinds[k], common.ResourceIdentifier{}, child.Href, nil, ctx, &resp.Diagnostics)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+			}
+		}
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+// apply deletes the children of the state which are not in the plan anymore, then configures the ports of the plan and
+// creates or updates their children, in dependency order.
+func (r *NDUChassisResource) apply(plan *NDUChassisResourceData, state *NDUChassisResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	tflog.Debug(ctx, "NDUChassisResource: apply ## ", map[string]interface{}{"plan": plan})
+
+	nduId := plan.NDUId.ValueString()
+	plan.Children = make(map[string]NDUChassisChild)
+	for key, child := range state.Children {
+		plan.Children[key] = child
+	}
+
+	ports := sortedChassisPorts(state.Children)
+	for i := len(ports) - 1; i >= 0; i-- {
+		for k := len(chassisChildKinds) - 1; k >= 0; k-- {
+			kind := chassisChildKinds[k]
+			key := ports[i] + "/" + kind
+			child, ok := plan.Children[key]
+			if !ok || plan.Ports[ports[i]].config(kind) {
+				continue
+			}
+			tflog.Debug(ctx, "NDUChassisResource: apply ## delete", map[string]interface{}{"child": key})
+			r.child("delete", kind, common.ResourceIdentifier{}, child.Href, nil, ctx, diags)
+			if diags.HasError() {
+				return
+			}
+			delete(plan.Children, key)
+		}
+	}
+
+	ports = make([]string, 0, len(plan.Ports))
+	for key := range plan.Ports {
+		ports = append(ports, key)
+	}
+	sortChassisPorts(ports)
+	for _, portColId := range ports {
+		port := plan.Ports[portColId]
+		previous := state.Ports[portColId]
+		if previous == nil {
+			previous = &NDUChassisPort{}
+		}
+		if (!port.Name.IsNull() || !port.ConnectedTo.IsNull()) && (!port.Name.Equal(previous.Name) || !port.ConnectedTo.Equal(previous.ConnectedTo)) {
+			portData := PortResourceData{
+				Identifier: common.ResourceIdentifier{DeviceId: plan.NDUId, ColId: types.StringValue(portColId)},
+				Config:     &PortConfig{Name: port.Name, ConnectedTo: port.ConnectedTo},
+			}
+			portResource := PortResource{client: r.client}
+			portResource.update(&portData, ctx, diags)
+			if diags.HasError() {
+				return
+			}
+		}
+
+		for _, kind := range chassisChildKinds {
+			if !port.config(kind) {
+				continue
+			}
+			key := portColId + "/" + kind
+			child, ok := plan.Children[key]
+			switch {
+			case !ok:
+				tflog.Debug(ctx, "NDUChassisResource: apply ## create", map[string]interface{}{"child": key})
+				identifier := common.ResourceIdentifier{DeviceId: types.StringValue(nduId), ParentColId: types.StringValue(portColId)}
+				switch kind {
+				case chassisXR:
+					identifier.ColId = types.StringValue(chassisXRColId)
+				case chassisVOA:
+					identifier.ColId = types.StringValue(portColId)
+				}
+				child = r.child("create", kind, identifier, types.StringNull(), port, ctx, diags)
+			case !port.equal(previous, kind):
+				tflog.Debug(ctx, "NDUChassisResource: apply ## update", map[string]interface{}{"child": key})
+				child = r.child("update", kind, common.ResourceIdentifier{}, child.Href, port, ctx, diags)
+			}
+			if diags.HasError() {
+				return
+			}
+			plan.Children[key] = child
+		}
+	}
+
+	tflog.Debug(ctx, "NDUChassisResource: apply ##", map[string]interface{}{"plan": plan})
+}
+
+// read refreshes the children and the settings of the ports. A port child which does not exist anymore is removed from
+// the port, it is created again at the next apply.
+func (r *NDUChassisResource) read(state *NDUChassisResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	if state.NDUId.IsNull() {
+		diags.AddError(
+			"NDUChassisResource: Error read NDUChassisResource",
+			"Read: Could not read. ndu_id is not specified",
+		)
+		return
+	}
+	if state.Children == nil {
+		state.Children = make(map[string]NDUChassisChild)
+	}
+
+	for portColId, port := range state.Ports {
+		if port == nil {
+			continue
+		}
+		for _, kind := range chassisChildKinds {
+			if !port.config(kind) {
+				continue
+			}
+			key := portColId + "/" + kind
+			child, ok := state.Children[key]
+			if ok {
+				var childDiags diag.Diagnostics
+				child = r.child("read", kind, common.ResourceIdentifier{}, child.Href, port, ctx, &childDiags)
+				if childDiags.HasError() {
+					if !notFound(childDiags) {
+						diags.Append(childDiags...)
+						return
+					}
+					ok = false
+				}
+			}
+			if !ok {
+				tflog.Debug(ctx, "NDUChassisResource: read ## child not found", map[string]interface{}{"child": key})
+				delete(state.Children, key)
+				port.clear(kind)
+				continue
+			}
+			state.Children[key] = child
+		}
+	}
+
+	tflog.Debug(ctx, "NDUChassisResource: read SUCCESS ", map[string]interface{}{"state": state})
+}
+
+// child runs the create, update, read or delete operation of the resource of a port child, and returns the child.
+// The port settings of the child are refreshed by the read.
+func (r *NDUChassisResource) child(operation string, kind string, identifier common.ResourceIdentifier, href types.String, port *NDUChassisPort, ctx context.Context, diags *diag.Diagnostics) NDUChassisChild {
+	if port == nil {
+		port = &NDUChassisPort{TOM: &TOMConfig{}, XR: &XRConfig{}, EDFA: &EDFAConfig{}, VOA: &VOAConfig{}}
+	}
+	var state types.Object
+	switch kind {
+	case chassisTOM:
+		data := TOMResourceData{Identifier: identifier, Href: href, Config: port.TOM}
+		tomResource := TOMResource{client: r.client}
+		switch operation {
+		case "create":
+			tomResource.create(&data, ctx, diags)
+		case "update":
+			tomResource.update(&data, ctx, diags)
+		case "read":
+			tomResource.read(&data, ctx, diags)
+		case "delete":
+			tomResource.delete(&data, ctx, diags)
+		}
+		href, state = data.Href, data.State
+	case chassisXR:
+		data := XRResourceData{Identifier: identifier, Href: href, Config: port.XR}
+		xrResource := XRResource{client: r.client}
+		switch operation {
+		case "create":
+			xrResource.create(&data, ctx, diags)
+		case "update":
+			xrResource.update(&data, ctx, diags)
+		case "read":
+			xrResource.read(&data, ctx, diags)
+		case "delete":
+			xrResource.delete(&data, ctx, diags)
+		}
+		href, state = data.Href, data.State
+	case chassisEDFA:
+		data := EDFAResourceData{Identifier: identifier, Href: href, Config: port.EDFA}
+		edfaResource := EDFAResource{client: r.client}
+		switch operation {
+		case "create":
+			edfaResource.create(&data, ctx, diags)
+		case "update":
+			edfaResource.update(&data, ctx, diags)
+		case "read":
+			edfaResource.read(&data, ctx, diags)
+		case "delete":
+			edfaResource.delete(&data, ctx, diags)
+		}
+		href, state = data.Href, data.State
+	case chassisVOA:
+		data := VOAResourceData{Identifier: identifier, Href: href, Config: port.VOA}
+		voaResource := VOAResource{client: r.client}
+		switch operation {
+		case "create":
+			voaResource.create(&data, ctx, diags)
+		case "update":
+			voaResource.update(&data, ctx, diags)
+		case "read":
+			voaResource.read(&data, ctx, diags)
+		case "delete":
+			voaResource.delete(&data, ctx, diags)
+		}
+		href, state = data.Href, data.State
+	}
+
+	child := NDUChassisChild{
+		Href:           href,
+		LifecycleState: types.StringNull(),
+		ActualType:     types.StringNull(),
+		ActualSubType:  types.StringNull(),
+	}
+	if state.IsNull() || state.IsUnknown() {
+		return child
+	}
+	attributes := state.Attributes()
+	if v, ok := attributes["lifecycle_state"].(types.String); ok {
+		child.LifecycleState = v
+	}
+	if inventory, ok := attributes["inventory"].(types.Object); ok && !inventory.IsNull() {
+		if v, ok := inventory.Attributes()["actual_type"].(types.String); ok {
+			child.ActualType = v
+		}
+		if v, ok := inventory.Attributes()["actual_subtype"].(types.String); ok {
+			child.ActualSubType = v
+		}
+	}
+	return child
+}
+
+// config tells whether the port expects a child of the kind.
+func (port *NDUChassisPort) config(kind string) bool {
+	if port == nil {
+		return false
+	}
+	switch kind {
+	case chassisTOM:
+		return port.TOM != nil
+	case chassisXR:
+		return port.XR != nil
+	case chassisEDFA:
+		return port.EDFA != nil
+	case chassisVOA:
+		return port.VOA != nil
+	}
+	return false
+}
+
+// clear removes the child of the kind from the port.
+func (port *NDUChassisPort) clear(kind string) {
+	switch kind {
+	case chassisTOM:
+		port.TOM = nil
+	case chassisXR:
+		port.XR = nil
+	case chassisEDFA:
+		port.EDFA = nil
+	case chassisVOA:
+		port.VOA = nil
+	}
+}
+
+// equal tells whether the child of the kind has the same settings in both ports.
+func (port *NDUChassisPort) equal(other *NDUChassisPort, kind string) bool {
+	if !port.config(kind) || !other.config(kind) {
+		return port.config(kind) == other.config(kind)
+	}
+	switch kind {
+	case chassisTOM:
+		return *port.TOM == *other.TOM
+	case chassisXR:
+		return *port.XR == *other.XR
+	case chassisEDFA:
+		return *port.EDFA == *other.EDFA
+	case chassisVOA:
+		return *port.VOA == *other.VOA
+	}
+	return true
+}
+
+// notFound tells whether the diagnostics report a resource which does not exist.
+func notFound(diags diag.Diagnostics) bool {
+	for _, d := range diags.Errors() {
+		if strings.Contains(d.Detail(), "status: 404") {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedChassisPorts returns the col ids of the ports which have children, in ascending order.
+func sortedChassisPorts(children map[string]NDUChassisChild) []string {
+	found := make(map[string]bool)
+	ports := []string{}
+	for key := range children {
+		port := strings.SplitN(key, "/", 2)[0]
+		if !found[port] {
+			found[port] = true
+			ports = append(ports, port)
+		}
+	}
+	sortChassisPorts(ports)
+	return ports
+}
+
+func sortChassisPorts(ports []string) {
+	sort.Slice(ports, func(i, j int) bool {
+		a, errA := strconv.Atoi(ports[i])
+		b, errB := strconv.Atoi(ports[j])
+		if errA != nil || errB != nil {
+			return ports[i] < ports[j]
+		}
+		return a < b
+	})
+}
+
+func NDUChassisSchemaAttributes() map[string]schema.Attribute {
+	childConfig := func(attributes map[string]schema.Attribute, description string) schema.SingleNestedAttribute {
+		config := attributes["config"].(schema.SingleNestedAttribute)
+		config.Description = description
+		return config
+	}
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Description: "Identifier of the chassis, the NDU id",
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"ndu_id": schema.StringAttribute{
+			Description: "ID of the NDU",
+			Required:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"ports": schema.MapNestedAttribute{
+			Description: "The ports of the NDU by col id",
+			Required:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						Description: "name of the port",
+						Optional:    true,
+					},
+					"connected_to": schema.StringAttribute{
+						Description: "connected_to",
+						Optional:    true,
+					},
+					"tom":  childConfig(TOMResourceSchemaAttributes(), "The TOM expected in the port"),
+					"xr":   childConfig(XRResourceSchemaAttributes(), "The XR expected in the port"),
+					"edfa": childConfig(EDFAResourceSchemaAttributes(), "The EDFA expected in the port"),
+					"voa":  childConfig(VOAResourceSchemaAttributes(), "The VOA expected in the port"),
+				},
+			},
+		},
+		"children": schema.MapNestedAttribute{
+			Description: "The created TOMs, XRs, EDFAs and VOAs, keyed by port col id and kind such as 1/tom",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"href": schema.StringAttribute{
+						Description: "href",
+						Computed:    true,
+					},
+					"lifecycle_state": schema.StringAttribute{
+						Description: "lifecycle_state",
+						Computed:    true,
+					},
+					"actual_type": schema.StringAttribute{
+						Description: "type of the plugged hardware",
+						Computed:    true,
+					},
+					"actual_sub_type": schema.StringAttribute{
+						Description: "sub type of the plugged hardware",
+						Computed:    true,
+					},
+				},
+			},
+		},
+	}
+}
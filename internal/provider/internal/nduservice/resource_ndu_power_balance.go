@@ -0,0 +1,588 @@
+package nduservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &NDUPowerBalanceResource{}
+	_ resource.ResourceWithConfigure      = &NDUPowerBalanceResource{}
+	_ resource.ResourceWithValidateConfig = &NDUPowerBalanceResource{}
+)
+
+// NewNDUPowerBalanceResource is a helper function to simplify the provider implementation.
+func NewNDUPowerBalanceResource() resource.Resource {
+	return &NDUPowerBalanceResource{}
+}
+
+// NDUPowerBalanceResource sets the EDFA gains and VOA attenuations of NDU ports to reach target launch powers.
+type NDUPowerBalanceResource struct {
+	client *ipm_pf.Client
+}
+
+// defaults of the balancing
+const (
+	defaultPowerTolerance     = 0.5
+	defaultPowerIterations    = 5
+	defaultPowerSettleTime    = 10
+	powerBalanceMaxIterations = 20
+)
+
+type NDUPowerSpan struct {
+	PortColId         types.String  `tfsdk:"port_col_id"`
+	TargetLaunchPower types.Float64 `tfsdk:"target_launch_power"`
+}
+
+type NDUPowerSpanResult struct {
+	MeasuredPower       types.Float64 `tfsdk:"measured_power"`
+	AchievedPower       types.Float64 `tfsdk:"achieved_power"`
+	EDFAHref            types.String  `tfsdk:"edfa_href"`
+	VOAHref             types.String  `tfsdk:"voa_href"`
+	Gain                types.Int64   `tfsdk:"gain"`
+	Attenuation         types.Int64   `tfsdk:"attenuation"`
+	ProposedGain        types.Int64   `tfsdk:"proposed_gain"`
+	ProposedAttenuation types.Int64   `tfsdk:"proposed_attenuation"`
+	Converged           types.Bool    `tfsdk:"converged"`
+	Message             types.String  `tfsdk:"message"`
+}
+
+type NDUPowerBalanceResourceData struct {
+	Id               types.String                  `tfsdk:"id"`
+	NDUId            types.String                  `tfsdk:"ndu_id"`
+	PolPowerCtrlMode types.String                  `tfsdk:"pol_power_ctrl_mode"`
+	Spans            []NDUPowerSpan                `tfsdk:"spans"`
+	PowerAttribute   types.String                  `tfsdk:"power_attribute"`
+	Tolerance        types.Float64                 `tfsdk:"tolerance"`
+	MaxIterations    types.Int64                   `tfsdk:"max_iterations"`
+	SettleTime       types.Int64                   `tfsdk:"settle_time"`
+	DryRun           types.Bool                    `tfsdk:"dry_run"`
+	Triggers         types.Map                     `tfsdk:"triggers"`
+	Iterations       types.Int64                   `tfsdk:"iterations"`
+	Converged        types.Bool                    `tfsdk:"converged"`
+	Results          map[string]NDUPowerSpanResult `tfsdk:"results"`
+}
+
+// powerSpan is the measured launch power of a port, and the settings and limits of its EDFA and VOA.
+type powerSpan struct {
+	port           string
+	target         float64
+	power          float64
+	edfaHref       string
+	gain           float64
+	minGain        float64
+	maxGain        float64
+	voaHref        string
+	attenuation    float64
+	minAttenuation float64
+	maxAttenuation float64
+	// the supported limit of the EDFA or VOA which the port data does not give, the span is then not balanced
+	unknownLimit string
+}
+
+// Metadata returns the data source type name.
+func (r *NDUPowerBalanceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ndu_power_balance"
+}
+
+// Schema defines the schema for the data source.
+func (r *NDUPowerBalanceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Balances the launch powers of NDU ports. The measured launch power of each port is compared with its target, and the gain of the port EDFA and " +
+			"the attenuation of the port VOA are changed within their supported range: a missing power is added by lowering the attenuation first, an excess power " +
+			"is removed by raising the attenuation first. A port whose EDFA or VOA does not report its supported range is not balanced. The settings are applied until all the ports are within the tolerance or max_iterations is reached. " +
+			"In dry run, the proposed settings are only reported. The balancing runs again when the settings or the triggers change; destroying the resource leaves the settings as they are.",
+		Attributes: NDUPowerBalanceSchemaAttributes(),
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *NDUPowerBalanceResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+// ValidateConfig checks the ports and the limits of the balancing.
+func (r *NDUPowerBalanceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data NDUPowerBalanceResourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ports := make(map[string]bool)
+	for i, span := range data.Spans {
+		if span.PortColId.IsUnknown() {
+			continue
+		}
+		if ports[span.PortColId.ValueString()] {
+			resp.Diagnostics.AddAttributeError(path.Root("spans").AtListIndex(i).AtName("port_col_id"), "NDUPowerBalanceResource: Duplicate port",
+				"The port "+span.PortColId.ValueString()+" is balanced by several spans.")
+		}
+		ports[span.PortColId.ValueString()] = true
+	}
+	if !data.Tolerance.IsNull() && !data.Tolerance.IsUnknown() && data.Tolerance.ValueFloat64() <= 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("tolerance"), "NDUPowerBalanceResource: Invalid tolerance", "tolerance must be greater than 0.")
+	}
+	if !data.MaxIterations.IsNull() && !data.MaxIterations.IsUnknown() && (data.MaxIterations.ValueInt64() < 1 || data.MaxIterations.ValueInt64() > powerBalanceMaxIterations) {
+		resp.Diagnostics.AddAttributeError(path.Root("max_iterations"), "NDUPowerBalanceResource: Invalid max_iterations",
+			fmt.Sprintf("max_iterations must be between 1 and %d.", powerBalanceMaxIterations))
+	}
+}
+
+func (r *NDUPowerBalanceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data NDUPowerBalanceResourceData
+
+	diags := req.Config.Get(ctx, &data)
+
+	tflog.Debug(ctx, "NDUPowerBalanceResource: Create - ", map[string]interface{}{"NDUPowerBalanceResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = data.NDUId
+	r.balance(&data, ctx, &resp.Diagnostics)
+
+	// the results gathered before an error are kept in the state, the settings may already be applied
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *NDUPowerBalanceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data NDUPowerBalanceResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "NDUPowerBalanceResource: Read - ", map[string]interface{}{"NDUPowerBalanceResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.read(&data, ctx, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *NDUPowerBalanceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan NDUPowerBalanceResourceData
+	var state NDUPowerBalanceResourceData
+
+	// the planned results are unknown, the settings are read from the config
+	diags := req.Config.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Debug(ctx, "NDUPowerBalanceResource: Update", map[string]interface{}{"plan": plan})
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = state.Id
+	r.balance(&plan, ctx, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *NDUPowerBalanceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data NDUPowerBalanceResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "NDUPowerBalanceResource: Delete", map[string]interface{}{"NDUPowerBalanceResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+// balance measures the spans and applies the proposed settings, until the spans converge or the iterations are exhausted.
+func (r *NDUPowerBalanceResource) balance(plan *NDUPowerBalanceResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	tflog.Debug(ctx, "NDUPowerBalanceResource: balance ## ", map[string]interface{}{"plan": plan})
+
+	dryRun := plan.DryRun.ValueBool()
+	if !plan.PolPowerCtrlMode.IsNull() && !dryRun {
+		rb, _ := json.Marshal(map[string]interface{}{"polPowerCtrlMode": plan.PolPowerCtrlMode.ValueString()})
+		_, err := r.client.ExecuteIPMHttpCommand("PUT", "/ndus/"+plan.NDUId.ValueString(), rb)
+		if err != nil && !strings.Contains(err.Error(), "status: 202") {
+			diags.AddError(
+				"NDUPowerBalanceResource: balance ##: Error update NDU",
+				"Balance: Could not set the pol power control mode of the NDU, unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	tolerance := defaultPowerTolerance
+	if !plan.Tolerance.IsNull() {
+		tolerance = plan.Tolerance.ValueFloat64()
+	}
+	maxIterations := int64(defaultPowerIterations)
+	if !plan.MaxIterations.IsNull() {
+		maxIterations = plan.MaxIterations.ValueInt64()
+	}
+	settleTime := int64(defaultPowerSettleTime)
+	if !plan.SettleTime.IsNull() {
+		settleTime = plan.SettleTime.ValueInt64()
+	}
+
+	plan.Results = make(map[string]NDUPowerSpanResult)
+	plan.Converged = types.BoolValue(false)
+	plan.Iterations = types.Int64Value(0)
+	iterations := int64(0)
+	for {
+		spans, err := r.measure(plan)
+		if err != nil {
+			diags.AddError(
+				"NDUPowerBalanceResource: balance ##: Error measure spans",
+				"Balance: Could not measure the launch powers, unexpected error: "+err.Error(),
+			)
+			return
+		}
+
+		converged := true
+		pending := []powerSpan{}
+		for _, span := range spans {
+			gain, attenuation := span.propose()
+			result, ok := plan.Results[span.port]
+			if !ok {
+				// the measured power is the power before balancing
+				result.MeasuredPower = types.Float64Value(span.power)
+			}
+			result.AchievedPower = types.Float64Value(span.power)
+			result.EDFAHref = common.OptionalString(span.edfaHref)
+			result.VOAHref = common.OptionalString(span.voaHref)
+			result.Gain, result.ProposedGain = optionalSetting(span.edfaHref, span.gain), optionalSetting(span.edfaHref, gain)
+			result.Attenuation, result.ProposedAttenuation = optionalSetting(span.voaHref, span.attenuation), optionalSetting(span.voaHref, attenuation)
+			result.Converged = types.BoolValue(math.Abs(span.target-span.power) <= tolerance)
+			result.Message = types.StringNull()
+			if !result.Converged.ValueBool() {
+				converged = false
+				if span.unknownLimit != "" {
+					result.Message = types.StringValue("the port data has no " + span.unknownLimit + ", the span is not balanced")
+				} else if gain == span.gain && attenuation == span.attenuation {
+					result.Message = types.StringValue(fmt.Sprintf("the target is %.1f dB away and out of the range of the EDFA gain and VOA attenuation", span.target-span.power))
+				} else {
+					pending = append(pending, span)
+				}
+			}
+			plan.Results[span.port] = result
+		}
+		tflog.Debug(ctx, "NDUPowerBalanceResource: balance ## iteration", map[string]interface{}{"iteration": iterations, "converged": converged, "pending": len(pending)})
+
+		plan.Converged = types.BoolValue(converged)
+		if converged || dryRun || len(pending) == 0 || iterations >= maxIterations {
+			break
+		}
+		for _, span := range pending {
+			gain, attenuation := span.propose()
+			r.apply(span, gain, attenuation, ctx, diags)
+			if diags.HasError() {
+				return
+			}
+		}
+		iterations++
+		plan.Iterations = types.Int64Value(iterations)
+		select {
+		case <-ctx.Done():
+			diags.AddError("NDUPowerBalanceResource: balance ##: Balancing interrupted", "Balance: The balancing was interrupted before the powers converged.")
+			return
+		case <-time.After(time.Duration(settleTime) * time.Second):
+		}
+	}
+
+	if !plan.Converged.ValueBool() && !dryRun {
+		diags.AddWarning(
+			"NDUPowerBalanceResource: Powers not converged",
+			"Balance: Some launch powers are not within the tolerance of their target, see the results of the spans.",
+		)
+	}
+}
+
+// apply sets the gain of the EDFA and the attenuation of the VOA of the span.
+func (r *NDUPowerBalanceResource) apply(span powerSpan, gain float64, attenuation float64, ctx context.Context, diags *diag.Diagnostics) {
+	if span.edfaHref != "" && gain != span.gain {
+		edfa := EDFAResourceData{Href: types.StringValue(span.edfaHref), Config: &EDFAConfig{GainTarget: types.Int64Value(int64(gain))}}
+		edfaResource := EDFAResource{client: r.client}
+		edfaResource.update(&edfa, ctx, diags)
+		if diags.HasError() {
+			return
+		}
+	}
+	if span.voaHref != "" && attenuation != span.attenuation {
+		voa := VOAResourceData{Href: types.StringValue(span.voaHref), Config: &VOAConfig{VOAAttenuationTx: types.Int64Value(int64(attenuation))}}
+		voaResource := VOAResource{client: r.client}
+		voaResource.update(&voa, ctx, diags)
+		if diags.HasError() {
+			return
+		}
+	}
+	tflog.Debug(ctx, "NDUPowerBalanceResource: apply ## ", map[string]interface{}{"port": span.port, "gain": gain, "attenuation": attenuation})
+}
+
+// propose returns the EDFA gain and VOA attenuation which bring the launch power to the target, within their supported range.
+// A missing power is added by lowering the attenuation first, an excess power is removed by raising the attenuation first.
+// The current settings are returned when a supported limit is unknown.
+func (span powerSpan) propose() (float64, float64) {
+	gain, attenuation := span.gain, span.attenuation
+	if span.unknownLimit != "" {
+		return gain, attenuation
+	}
+	delta := math.Round(span.target - span.power)
+	clamp := func(value float64, min float64, max float64) float64 {
+		return math.Max(min, math.Min(max, value))
+	}
+	if span.voaHref != "" {
+		attenuation = clamp(span.attenuation-delta, span.minAttenuation, span.maxAttenuation)
+		delta -= span.attenuation - attenuation
+	}
+	if span.edfaHref != "" {
+		gain = clamp(span.gain+delta, span.minGain, span.maxGain)
+	}
+	return gain, attenuation
+}
+
+// measure reads the launch power of the ports of the spans, and the settings and limits of their EDFA and VOA.
+func (r *NDUPowerBalanceResource) measure(plan *NDUPowerBalanceResourceData) ([]powerSpan, error) {
+	powerAttribute := plan.PowerAttribute.ValueString()
+
+	spans := []powerSpan{}
+	for _, s := range plan.Spans {
+		port, err := common.GetResource(r.client, "/ndus/"+plan.NDUId.ValueString()+"/ports/"+s.PortColId.ValueString()+"?content=expanded")
+		if err != nil {
+			return nil, err
+		}
+		span := powerSpan{port: s.PortColId.ValueString(), target: s.TargetLaunchPower.ValueFloat64()}
+		var found bool
+		if span.power, found = lookupFloat(port, powerAttribute); !found {
+			return nil, fmt.Errorf("the port %s has no launch power at %s", span.port, powerAttribute)
+		}
+		if edfas, ok := port["edfas"].([]interface{}); ok && len(edfas) > 0 {
+			if edfa, ok := edfas[0].(map[string]interface{}); ok {
+				span.edfaHref, _ = common.LookupValue(edfa, "href")
+				span.gain, _ = lookupFloat(edfa, "config.gainTarget", "state.gainTarget", "state.currentGain")
+				if span.minGain, found = lookupFloat(edfa, "state.supportedMinGain"); !found {
+					span.unknownLimit = "supportedMinGain"
+				}
+				if span.maxGain, found = lookupFloat(edfa, "state.supportedMaxGain"); !found {
+					span.unknownLimit = "supportedMaxGain"
+				}
+			}
+		}
+		if voas, ok := port["voas"].([]interface{}); ok && len(voas) > 0 {
+			if voa, ok := voas[0].(map[string]interface{}); ok {
+				span.voaHref, _ = common.LookupValue(voa, "href")
+				span.attenuation, _ = lookupFloat(voa, "config.voaAttenuationTx", "state.voaAttenuationTx")
+				if span.minAttenuation, found = lookupFloat(voa, "state.supportedMinAttenuation"); !found {
+					span.unknownLimit = "supportedMinAttenuation"
+				}
+				if span.maxAttenuation, found = lookupFloat(voa, "state.supportedMaxAttenuation"); !found {
+					span.unknownLimit = "supportedMaxAttenuation"
+				}
+			}
+		}
+		if span.edfaHref == "" && span.voaHref == "" {
+			return nil, fmt.Errorf("the port %s has no EDFA and no VOA", span.port)
+		}
+		spans = append(spans, span)
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].port < spans[j].port })
+	return spans, nil
+}
+
+// read refreshes the achieved launch powers and the settings of the spans.
+func (r *NDUPowerBalanceResource) read(state *NDUPowerBalanceResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	spans, err := r.measure(state)
+	if err != nil {
+		diags.AddError(
+			"NDUPowerBalanceResource: read ##: Error measure spans",
+			"Read: Could not measure the launch powers, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	if state.Results == nil {
+		state.Results = make(map[string]NDUPowerSpanResult)
+	}
+	for _, span := range spans {
+		result, ok := state.Results[span.port]
+		if !ok {
+			continue
+		}
+		result.AchievedPower = types.Float64Value(span.power)
+		result.Gain = optionalSetting(span.edfaHref, span.gain)
+		result.Attenuation = optionalSetting(span.voaHref, span.attenuation)
+		state.Results[span.port] = result
+	}
+	tflog.Debug(ctx, "NDUPowerBalanceResource: read SUCCESS ", map[string]interface{}{"state": state})
+}
+
+// lookupFloat returns the first numeric value found at the dotted paths.
+func lookupFloat(data map[string]interface{}, keys ...string) (float64, bool) {
+	for _, key := range keys {
+		if v, ok := common.LookupValue(data, key); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// optionalSetting returns the setting of an EDFA or VOA, or null when the port does not have it.
+func optionalSetting(href string, value float64) types.Int64 {
+	if href == "" {
+		return types.Int64Null()
+	}
+	return types.Int64Value(int64(value))
+}
+
+func NDUPowerBalanceSchemaAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Description: "Identifier of the balancing, the NDU id",
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"ndu_id": schema.StringAttribute{
+			Description: "ID of the NDU",
+			Required:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"pol_power_ctrl_mode": schema.StringAttribute{
+			Description: "pol_power_ctrl_mode set on the NDU before the balancing",
+			Optional:    true,
+		},
+		"spans": schema.ListNestedAttribute{
+			Description: "The ports to balance",
+			Required:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"port_col_id": schema.StringAttribute{
+						Description: "col id of the port, whose first EDFA and first VOA are set",
+						Required:    true,
+					},
+					"target_launch_power": schema.Float64Attribute{
+						Description: "target launch power of the port in dBm",
+						Required:    true,
+					},
+				},
+			},
+		},
+		"power_attribute": schema.StringAttribute{
+			Description: "dotted path of the measured launch power in the expanded port data of the NDU, e.g. state.txPower",
+			Required:    true,
+		},
+		"tolerance": schema.Float64Attribute{
+			Description: "accepted difference in dB between the launch power and its target, 0.5 by default",
+			Optional:    true,
+		},
+		"max_iterations": schema.Int64Attribute{
+			Description: fmt.Sprintf("maximum number of times the settings are applied, %d by default", defaultPowerIterations),
+			Optional:    true,
+		},
+		"settle_time": schema.Int64Attribute{
+			Description: fmt.Sprintf("time in seconds to wait after applying the settings before measuring again, %d by default", defaultPowerSettleTime),
+			Optional:    true,
+		},
+		"dry_run": schema.BoolAttribute{
+			Description: "only report the proposed settings, false by default",
+			Optional:    true,
+		},
+		"triggers": schema.MapAttribute{
+			Description: "arbitrary values which run the balancing again when they change",
+			Optional:    true,
+			ElementType: types.StringType,
+		},
+		"iterations": schema.Int64Attribute{
+			Description: "number of times the settings were applied",
+			Computed:    true,
+		},
+		"converged": schema.BoolAttribute{
+			Description: "whether all the launch powers are within the tolerance of their target",
+			Computed:    true,
+		},
+		"results": schema.MapNestedAttribute{
+			Description: "The outcome of the balancing by port col id",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"measured_power": schema.Float64Attribute{
+						Description: "launch power before the balancing",
+						Computed:    true,
+					},
+					"achieved_power": schema.Float64Attribute{
+						Description: "launch power after the balancing",
+						Computed:    true,
+					},
+					"edfa_href": schema.StringAttribute{
+						Description: "href of the EDFA of the port",
+						Computed:    true,
+					},
+					"voa_href": schema.StringAttribute{
+						Description: "href of the VOA of the port",
+						Computed:    true,
+					},
+					"gain": schema.Int64Attribute{
+						Description: "gain target of the EDFA",
+						Computed:    true,
+					},
+					"attenuation": schema.Int64Attribute{
+						Description: "tx attenuation of the VOA",
+						Computed:    true,
+					},
+					"proposed_gain": schema.Int64Attribute{
+						Description: "gain target proposed at the last measure",
+						Computed:    true,
+					},
+					"proposed_attenuation": schema.Int64Attribute{
+						Description: "tx attenuation proposed at the last measure",
+						Computed:    true,
+					},
+					"converged": schema.BoolAttribute{
+						Description: "whether the launch power is within the tolerance of its target",
+						Computed:    true,
+					},
+					"message": schema.StringAttribute{
+						Description: "why the launch power could not reach its target",
+						Computed:    true,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package nduservice
+
+import (
+	"testing"
+)
+
+func TestPowerSpanPropose(t *testing.T) {
+	span := func(target float64, power float64) powerSpan {
+		return powerSpan{
+			port:           "1",
+			target:         target,
+			power:          power,
+			edfaHref:       "/ndus/n1/ports/1/edfas/1",
+			gain:           15,
+			minGain:        10,
+			maxGain:        25,
+			voaHref:        "/ndus/n1/ports/1/voas/1",
+			attenuation:    10,
+			minAttenuation: 0,
+			maxAttenuation: 20,
+		}
+	}
+	withoutVOA := span(3, 0)
+	withoutVOA.voaHref, withoutVOA.attenuation = "", 0
+	withoutEDFA := span(5, 0)
+	withoutEDFA.edfaHref = ""
+	unknownLimit := span(5, 0)
+	unknownLimit.unknownLimit = "supportedMaxGain"
+	tests := []struct {
+		name        string
+		span        powerSpan
+		gain        float64
+		attenuation float64
+	}{
+		{"balanced", span(1, 1), 15, 10},
+		{"missing power from the VOA", span(5, 0), 15, 5},
+		{"missing power from the VOA and the EDFA", span(15, 0), 20, 0},
+		{"excess power to the VOA", span(0, 4), 15, 14},
+		{"excess power to the VOA and the EDFA", span(0, 15), 10, 20},
+		{"out of range", span(0, 30), 10, 20},
+		{"rounded", span(-1.4, -3), 15, 8},
+		{"without VOA", withoutVOA, 18, 0},
+		{"without EDFA", withoutEDFA, 15, 5},
+		{"unknown limit", unknownLimit, 15, 10},
+	}
+	for _, test := range tests {
+		gain, attenuation := test.span.propose()
+		if gain != test.gain || attenuation != test.attenuation {
+			t.Errorf("%s: propose() = %v, %v, want %v, %v", test.name, gain, attenuation, test.gain, test.attenuation)
+		}
+	}
+}
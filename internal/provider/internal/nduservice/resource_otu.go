@@ -76,7 +76,7 @@ func (r *OTUResource) Configure(_ context.Context, req resource.ConfigureRequest
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r OTUResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -50,8 +50,9 @@ func (r *PEMResource) Metadata(_ context.Context, req resource.MetadataRequest,
 func (r *PEMResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	//type PEMResourceData struct
 	resp.Schema = schema.Schema{
-		Description: "Manages an NDU pem",
-		Attributes:  PEMResourceSchemaAttributes(),
+		Description:        "Manages an NDU pem",
+		DeprecationMessage: "The resource only reads state, use the ipm_ndu_pem data source instead.",
+		Attributes:         PEMResourceSchemaAttributes(),
 	}
 }
 
@@ -61,7 +62,7 @@ func (r *PEMResource) Configure(_ context.Context, req resource.ConfigureRequest
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r PEMResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -67,7 +67,7 @@ func (r *PolPTPResource) Configure(_ context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r PolPTPResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -76,7 +76,7 @@ func (r *PortResource) Configure(_ context.Context, req resource.ConfigureReques
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r PortResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
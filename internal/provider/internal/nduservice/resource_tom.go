@@ -71,7 +71,7 @@ func (r *TOMResource) Configure(_ context.Context, req resource.ConfigureRequest
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r TOMResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
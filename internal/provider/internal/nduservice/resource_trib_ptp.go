@@ -68,7 +68,7 @@ func (r *TribPTPResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r TribPTPResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
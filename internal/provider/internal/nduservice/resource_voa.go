@@ -73,7 +73,7 @@ func (r *VOAResource) Configure(_ context.Context, req resource.ConfigureRequest
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r VOAResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -176,6 +176,9 @@ func (r *VOAResource) create(plan *VOAResourceData, ctx context.Context, diags *
 	if !plan.Config.RequiredSubType.IsNull() {
 		configRequest["requiredSubType"] = plan.Config.RequiredSubType.ValueString()
 	}
+	if !plan.Config.RequiredType.IsNull() {
+		configRequest["requiredType"] = plan.Config.RequiredType.ValueString()
+	}
 	if !plan.Config.VOAAttenuationTx.IsNull() {
 		configRequest["voaAttenuationTx"] = plan.Config.VOAAttenuationTx.ValueInt64()
 	}
@@ -70,7 +70,7 @@ func (r *XRResource) Configure(_ context.Context, req resource.ConfigureRequest,
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r XRResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -208,7 +208,7 @@ func (d *FoundNetworkConnectionsDataSource) Configure(_ context.Context, req dat
 		return
 	}
 
-	d.client = req.ProviderData.(*ipm_pf.Client)
+	d.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (d *FoundNetworkConnectionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -24,6 +24,7 @@ var (
 	_ resource.Resource                = &NetworkConnectionResource{}
 	_ resource.ResourceWithConfigure   = &NetworkConnectionResource{}
 	_ resource.ResourceWithImportState = &NetworkConnectionResource{}
+	_ resource.ResourceWithModifyPlan  = &NetworkConnectionResource{}
 )
 
 // NewNetworkConnectionResource is a helper function to simplify the provider implementation.
@@ -32,7 +33,8 @@ func NewNetworkConnectionResource() resource.Resource {
 }
 
 type NetworkConnectionResource struct {
-	client *ipm_pf.Client
+	client   *ipm_pf.Client
+	profiles *common.Profiles
 }
 
 type NetworkConnectionConfig struct {
@@ -47,6 +49,7 @@ type NetworkConnectionConfig struct {
 type NetworkConnectionResourceData struct {
 	Id        types.String             `tfsdk:"id"`
 	Href      types.String             `tfsdk:"href"`
+	Profile   types.String             `tfsdk:"profile"`
 	Config    *NetworkConnectionConfig `tfsdk:"config"`
 	State     types.Object             `tfsdk:"state"`
 	Endpoints []NCEndpointResourceData `tfsdk:"end_points"`
@@ -73,7 +76,8 @@ func (r *NetworkConnectionResource) Configure(_ context.Context, req resource.Co
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
+	r.profiles = req.ProviderData.(*common.ProviderData).Profiles
 }
 
 func (r NetworkConnectionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -169,6 +173,39 @@ func (r NetworkConnectionResource) Delete(ctx context.Context, req resource.Dele
 	resp.State.RemoveResource(ctx)
 }
 
+// ModifyPlan validates the profile reference and the endpoint selectors at plan time.
+func (r *NetworkConnectionResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var profile types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("profile"), &profile)...)
+	if !profile.IsNull() && !profile.IsUnknown() {
+		if _, err := r.profiles.FindNCProfile(profile.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("profile"), "NetworkConnectionResource: Invalid profile", err.Error())
+		}
+	}
+	if r.client == nil || !req.State.Raw.IsNull() {
+		return
+	}
+
+	// the selectors of a new NC must resolve to module client interfaces, which may still appear during the apply
+	var endpoints []NCEndpointResourceData
+	if diags := req.Plan.GetAttribute(ctx, path.Root("end_points"), &endpoints); diags.HasError() {
+		return
+	}
+	resolver := common.NewSelectorResolver(r.client)
+	for i := range endpoints {
+		if !common.IfSelectorKnown(&endpoints[i].Config.Selector) {
+			continue
+		}
+		if _, err := resolver.ResolveIfSelector(&endpoints[i].Config.Selector); err != nil {
+			resp.Diagnostics.AddAttributeWarning(path.Root("end_points").AtListIndex(i).AtName("config").AtName("selector"),
+				"NetworkConnectionResource: Endpoint not found", "Endpoint "+strconv.Itoa(i)+": "+err.Error()+". It must be found before the NC is created.")
+		}
+	}
+}
+
 func (r *NetworkConnectionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// Retrieve import ID and save to id attribute
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
@@ -176,7 +213,20 @@ func (r *NetworkConnectionResource) ImportState(ctx context.Context, req resourc
 
 func (r *NetworkConnectionResource) create(plan *NetworkConnectionResourceData, ctx context.Context, diags *diag.Diagnostics) {
 
-	if plan.Config.Name.IsNull() || plan.Config.ServiceMode.IsNull() {
+	var profile common.NCProfile
+	if !plan.Profile.IsNull() {
+		var err error
+		profile, err = r.profiles.FindNCProfile(plan.Profile.ValueString())
+		if err != nil {
+			diags.AddError(
+				"Error Create NC",
+				"Create: Could not create NC, "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if plan.Config.Name.IsNull() || (plan.Config.ServiceMode.IsNull() && profile.ServiceMode.IsNull()) {
 		diags.AddError(
 			"Error Create NC",
 			"Create: Could not create NC, NC Name or Service mode is not specified.",
@@ -191,31 +241,39 @@ func (r *NetworkConnectionResource) create(plan *NetworkConnectionResourceData,
 		return
 	}
 
-	// Check to see if the related constellastion's lif cycle state is in' 'configured' state. Assume hub is the the first endpoint
-	queryString := "/transport-capacities?content=expanded"
-	if plan.Endpoints[0].Config.Selector.ModuleIfSelectorByModuleId != nil {
-		queryString = queryString + "&q={\"$and\":[{\"endpoints\":{\"$elemMatch\":{\"config.selector.moduleIfSelectorByModuleId.moduleId\":\"" + plan.Endpoints[0].Config.Selector.ModuleIfSelectorByModuleId.ModuleId.ValueString() + "\",\"config.selector.moduleIfSelectorByModuleId.moduleClientIfAid\":\"" + plan.Endpoints[0].Config.Selector.ModuleIfSelectorByModuleId.ModuleClientIfAid.ValueString() + "\"}}},{\"endpoints\":{\"$elemMatch\":{\"config.selector.moduleIfSelectorByModuleId.moduleId\":\"" + plan.Endpoints[1].Config.Selector.ModuleIfSelectorByModuleId.ModuleId.ValueString() + "\",\"config.selector.moduleIfSelectorByModuleId.moduleClientIfAid\":\"" + plan.Endpoints[1].Config.Selector.ModuleIfSelectorByModuleId.ModuleClientIfAid.ValueString() + "\"}}}]}"
-	} else if plan.Endpoints[0].Config.Selector.ModuleIfSelectorByModuleName != nil {
-			queryString = queryString + "&q={\"$and\":[{\"endpoints\":{\"$elemMatch\":{\"config.selector.moduleIfSelectorByModuleName.moduleName\":\"" + plan.Endpoints[0].Config.Selector.ModuleIfSelectorByModuleName.ModuleName.ValueString() + "\",\"config.selector.moduleIfSelectorByModuleName.moduleClientIfAid\":\"" + plan.Endpoints[0].Config.Selector.ModuleIfSelectorByModuleName.ModuleClientIfAid.ValueString() + "\"}}},{\"endpoints\":{\"$elemMatch\":{\"config.selector.moduleIfSelectorByModuleName.moduleName\":\"" + plan.Endpoints[1].Config.Selector.ModuleIfSelectorByModuleName.ModuleName.ValueString() + "\",\"config.selector.moduleIfSelectorByModuleName.moduleClientIfAid\":\"" + plan.Endpoints[1].Config.Selector.ModuleIfSelectorByModuleName.ModuleClientIfAid.ValueString() + "\"}}}]}"
-	} else if plan.Endpoints[0].Config.Selector.ModuleIfSelectorByModuleMAC != nil {
-			queryString = queryString + "&q={\"$and\":[{\"endpoints\":{\"$elemMatch\":{\"config.selector.moduleIfSelectorByModuleMAC.moduleMAC\":\"" + plan.Endpoints[0].Config.Selector.ModuleIfSelectorByModuleMAC.ModuleMAC.ValueString() + "\",\"config.selector.moduleIfSelectorByModuleMAC.moduleClientIfAid\":\"" + plan.Endpoints[0].Config.Selector.ModuleIfSelectorByModuleMAC.ModuleClientIfAid.ValueString() + "\"}}},{\"endpoints\":{\"$elemMatch\":{\"config.selector.moduleIfSelectorByModuleMAC.moduleMAC\":\"" + plan.Endpoints[1].Config.Selector.ModuleIfSelectorByModuleMAC.ModuleMAC.ValueString() + "\",\"config.selector.moduleIfSelectorByModuleMAC.moduleClientIfAid\":\"" + plan.Endpoints[1].Config.Selector.ModuleIfSelectorByModuleMAC.ModuleClientIfAid.ValueString() + "\"}}}]}"
-	} else if plan.Endpoints[0].Config.Selector.ModuleIfSelectorByModuleSerialNumber != nil {
-			queryString = queryString + "&q={\"$and\":[{\"endpoints\":{\"$elemMatch\":{\"config.selector.moduleIfSelectorByModuleSerialNumber.moduleSerialNumber\":\"" + plan.Endpoints[0].Config.Selector.ModuleIfSelectorByModuleSerialNumber.ModuleSerialNumber.ValueString() + "\",\"config.selector.moduleIfSelectorByModuleSerialNumber.moduleClientIfAid\":\"" + plan.Endpoints[0].Config.Selector.ModuleIfSelectorByModuleSerialNumber.ModuleClientIfAid.ValueString() + "\"}}},{\"endpoints\":{\"$elemMatch\":{\"config.selector.moduleIfSelectorByModuleSerialNumber.moduleSerialNumber\":\"" + plan.Endpoints[1].Config.Selector.ModuleIfSelectorByModuleSerialNumber.ModuleSerialNumber.ValueString() + "\",\"config.selector.moduleIfSelectorByModuleSerialNumber.moduleClientIfAid\":\"" + plan.Endpoints[1].Config.Selector.ModuleIfSelectorByModuleSerialNumber.ModuleClientIfAid.ValueString() + "\"}}}]}"
-	} 
-	tflog.Debug(ctx, "NetworkConnectionResource: TC QueryString ## ", map[string]interface{}{"QueryString": queryString})
-
-	_, err := common.CheckResourceState(ctx, r.client, queryString, 5) 
-	if err != nil {
-		diags.AddError(
-			"Error Creating NC",
-			"Create: Could not create NC: CheckResourceState: " + err.Error(),
-		)
-		return
+	// Check to see if a configured TC connects the hub, assumed to be the first endpoint, with each leaf endpoint
+	resolver := common.NewSelectorResolver(r.client)
+	resolvedEndpoints := make([]*common.ResolvedIf, len(plan.Endpoints))
+	for i := range plan.Endpoints {
+		resolved, err := resolver.ResolveIfSelector(&plan.Endpoints[i].Config.Selector)
+		if err != nil {
+			diags.AddError(
+				"Error Create NC",
+				"Create: Could not create NC, endpoint "+strconv.Itoa(i)+": "+err.Error(),
+			)
+			return
+		}
+		resolvedEndpoints[i] = resolved
+	}
+	for _, leaf := range resolvedEndpoints[1:] {
+		queryString := "/transport-capacities?content=expanded&q={\"$and\":[" + tcEndpointQuery(resolvedEndpoints[0]) + "," + tcEndpointQuery(leaf) + "]}"
+		tflog.Debug(ctx, "NetworkConnectionResource: TC QueryString ## ", map[string]interface{}{"QueryString": queryString})
+
+		_, err := common.CheckResourceState(ctx, r.client, queryString, 5)
+		if err != nil {
+			diags.AddError(
+				"Error Creating NC",
+				"Create: Could not create NC, no configured TC between the hub "+resolvedEndpoints[0].String()+" and the leaf "+leaf.String()+": "+err.Error(),
+			)
+			return
+		}
 	}
 
 	var createRequest = make(map[string]interface{})
 	createRequest["name"] = plan.Config.Name.ValueString()
-	createRequest["serviceMode"] = plan.Config.ServiceMode.ValueString()
+	if !plan.Config.ServiceMode.IsNull() {
+		createRequest["serviceMode"] = plan.Config.ServiceMode.ValueString()
+	}
 
 	if !plan.Config.MC.IsNull() {
 		createRequest["mc"] = plan.Config.MC.ValueString()
@@ -234,10 +292,14 @@ func (r *NetworkConnectionResource) create(plan *NetworkConnectionResourceData,
 			createRequest["labels"] = labels
 		}
 	}
+	common.MergeProfile(createRequest, profile.Request(ctx))
 	endpoints := []interface{}{}
 	for _, v := range plan.Endpoints {
 		endpoint := make(map[string]interface{})
 		endpoint["capacity"] = v.Config.Capacity.ValueInt64()
+		if v.Config.Capacity.IsNull() && !profile.EndpointCapacity.IsNull() {
+			endpoint["capacity"] = profile.EndpointCapacity.ValueInt64()
+		}
 		selector := make(map[string]interface{})
 		aSelector := make(map[string]interface{})
 		if v.Config.Selector.ModuleIfSelectorByModuleId != nil {
@@ -382,6 +444,17 @@ func (r *NetworkConnectionResource) update(plan *NetworkConnectionResourceData,
 			updateRequest["labels"] = labels
 		}
 	}
+	if !plan.Profile.IsNull() {
+		profile, err := r.profiles.FindNCProfile(plan.Profile.ValueString())
+		if err != nil {
+			diags.AddError(
+				"Error Update NC",
+				"Update: Could not Update NC, "+err.Error(),
+			)
+			return
+		}
+		common.MergeProfile(updateRequest, profile.Request(ctx))
+	}
 
 	tflog.Debug(ctx, "NetworkConnectionResource: update ## ", map[string]interface{}{"id": plan.Id.ValueString(), "Update Request": updateRequest})
 
@@ -552,6 +625,35 @@ func (r *NetworkConnectionResource) delete(plan *NetworkConnectionResourceData,
 	tflog.Debug(ctx, "NetworkConnectionResource: delete ## ", map[string]interface{}{"plan": plan})
 }
 
+// tcEndpointQuery returns the query of the TCs with an endpoint on the module client interface.
+func tcEndpointQuery(endpoint *common.ResolvedIf) string {
+	return "{\"endpoints\":{\"$elemMatch\":{\"state.moduleIf.moduleId\":\"" + endpoint.ModuleId + "\",\"state.moduleIf.clientIfAid\":\"" + endpoint.ClientIfAid + "\"}}}"
+}
+
+// CreateNetworkConnection creates the NC as ipm_network_connection does, for the resources composing NCs.
+func CreateNetworkConnection(client *ipm_pf.Client, plan *NetworkConnectionResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	r := NetworkConnectionResource{client: client}
+	r.create(plan, ctx, diags)
+}
+
+// UpdateNetworkConnection updates the NC as ipm_network_connection does.
+func UpdateNetworkConnection(client *ipm_pf.Client, plan *NetworkConnectionResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	r := NetworkConnectionResource{client: client}
+	r.update(plan, ctx, diags)
+}
+
+// ReadNetworkConnection reads the NC as ipm_network_connection does.
+func ReadNetworkConnection(client *ipm_pf.Client, state *NetworkConnectionResourceData, ctx context.Context, diags *diag.Diagnostics, retryCount ...int) {
+	r := NetworkConnectionResource{client: client}
+	r.read(state, ctx, diags, retryCount...)
+}
+
+// DeleteNetworkConnection deletes the NC as ipm_network_connection does.
+func DeleteNetworkConnection(client *ipm_pf.Client, state *NetworkConnectionResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	r := NetworkConnectionResource{client: client}
+	r.delete(state, ctx, diags)
+}
+
 func (ncData *NetworkConnectionResourceData) Populate(data map[string]interface{}, ctx context.Context, diags *diag.Diagnostics, computeOnly ...bool) {
 
 	computeFlag := false
@@ -710,6 +812,10 @@ func NetworkConnectionSchemaAttributes() map[string]schema.Attribute {
 			Description: "Href of the Network Connection",
 			Computed:    true,
 		},
+		"profile": schema.StringAttribute{
+			Description: "Name of a NC profile of the provider profiles. The config settings and endpoint capacities which are not specified are taken from the profile.",
+			Optional:    true,
+		},
 		//Config      NetworkConnectionConfig `tfsdk:"config"`
 		"config": schema.SingleNestedAttribute{
 			Description: " NetworkConnection Config",
@@ -69,7 +69,7 @@ func (r *NCEndpointResource) Configure(_ context.Context, req resource.Configure
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r NCEndpointResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -0,0 +1,375 @@
+package network
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &ConstellationCapacityDataSource{}
+	_ datasource.DataSourceWithConfigure = &ConstellationCapacityDataSource{}
+)
+
+// NewConstellationCapacityDataSource is a helper function to simplify the provider implementation.
+func NewConstellationCapacityDataSource() datasource.DataSource {
+	return &ConstellationCapacityDataSource{}
+}
+
+// ConstellationCapacityDataSource reports the allocated and free DSCs of a constellation network.
+type ConstellationCapacityDataSource struct {
+	client *ipm_pf.Client
+}
+
+type DSCAllocationData struct {
+	Total          types.Int64   `tfsdk:"total"`
+	Allocated      types.Int64   `tfsdk:"allocated"`
+	Free           types.Int64   `tfsdk:"free"`
+	AllocatedCDSCs []types.Int64 `tfsdk:"allocated_cdscs"`
+}
+
+type LeafCapacityData struct {
+	ModuleId             types.String       `tfsdk:"module_id"`
+	ModuleName           types.String       `tfsdk:"module_name"`
+	Tx                   *DSCAllocationData `tfsdk:"tx"`
+	Rx                   *DSCAllocationData `tfsdk:"rx"`
+	TransportCapacityIds []types.String     `tfsdk:"transport_capacity_ids"`
+}
+
+type ConstellationCapacityDataSourceData struct {
+	NetworkId       types.String       `tfsdk:"network_id"`
+	HubModuleId     types.String       `tfsdk:"hub_module_id"`
+	HubModuleName   types.String       `tfsdk:"hub_module_name"`
+	PlannedCapacity types.String       `tfsdk:"planned_capacity"`
+	Tx              *DSCAllocationData `tfsdk:"tx"`
+	Rx              *DSCAllocationData `tfsdk:"rx"`
+	Leaves          []LeafCapacityData `tfsdk:"leaves"`
+}
+
+// Metadata returns the data source type name.
+func (r *ConstellationCapacityDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_constellation_capacity"
+}
+
+func (d *ConstellationCapacityDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reports the allocated and free DSCs of the hub module of a constellation network, per direction and per leaf. tx is the hub to leaf direction, rx the leaf to hub direction.",
+		Attributes:  ConstellationCapacityDataSchemaAttributes(),
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *ConstellationCapacityDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+func (d *ConstellationCapacityDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	query := ConstellationCapacityDataSourceData{}
+	diags := req.Config.Get(ctx, &query)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "ConstellationCapacityDataSource: get capacity", map[string]interface{}{"network_id": query.NetworkId.ValueString()})
+
+	// the hub module: planned capacity and DSC limits
+	hub, err := common.GetResource(d.client, "/xr-networks/"+query.NetworkId.ValueString()+"/hubModule?content=expanded")
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"ConstellationCapacityDataSource: read ##: Error Get hub module",
+			"Could not get the hub module of network "+query.NetworkId.ValueString()+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+	hubModuleId, _ := common.LookupValue(hub, "state.module.moduleId")
+	if hubModuleId == "" {
+		resp.Diagnostics.AddError(
+			"ConstellationCapacityDataSource: read ##: Error Get hub module",
+			"The hub module of network "+query.NetworkId.ValueString()+" is not discovered",
+		)
+		return
+	}
+	query.HubModuleId = types.StringValue(hubModuleId)
+	query.HubModuleName = lookupString(hub, "state.module.moduleName")
+	query.PlannedCapacity = lookupString(hub, "config.module.plannedCapacity")
+
+	// the DSCs and DSC groups of the hub carriers
+	totalDSCs := int64(0)
+	hubTx := map[int64]bool{}
+	hubRx := map[int64]bool{}
+	linePtps, err := common.GetResources(d.client, "/modules/"+hubModuleId+"/linePtps?content=expanded")
+	if err != nil {
+		resp.Diagnostics.AddError("ConstellationCapacityDataSource: read ##: Error Get line PTPs", "Could not get, unexpected error: "+err.Error())
+		return
+	}
+	for _, linePtp := range linePtps {
+		linePtpUri := "/modules/" + hubModuleId + "/linePtps/" + colId(linePtp)
+		carriers, err := common.GetResources(d.client, linePtpUri+"/carriers?content=expanded")
+		if err != nil {
+			resp.Diagnostics.AddError("ConstellationCapacityDataSource: read ##: Error Get carriers", "Could not get, unexpected error: "+err.Error())
+			return
+		}
+		for _, carrier := range carriers {
+			carrierUri := linePtpUri + "/carriers/" + colId(carrier)
+			dscs, err := common.GetResources(d.client, carrierUri+"/dscs?content=expanded")
+			if err != nil {
+				resp.Diagnostics.AddError("ConstellationCapacityDataSource: read ##: Error Get DSCs", "Could not get, unexpected error: "+err.Error())
+				return
+			}
+			totalDSCs += int64(len(dscs))
+			dscgs, err := common.GetResources(d.client, carrierUri+"/dscgs?content=expanded")
+			if err != nil {
+				resp.Diagnostics.AddError("ConstellationCapacityDataSource: read ##: Error Get DSCGs", "Could not get, unexpected error: "+err.Error())
+				return
+			}
+			for _, dscg := range dscgs {
+				dscgData, ok := dscg.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				state, _ := dscgData["state"].(map[string]interface{})
+				addCDSCs(hubTx, state["txCDSCs"])
+				addCDSCs(hubRx, state["rxCDSCs"])
+			}
+		}
+	}
+	if totalDSCs == 0 {
+		totalDSCs = lookupInt64(hub, "config.module.maxDSCs")
+	}
+	totalTxDSCs := totalDSCs
+	if maxTxDSCs := lookupInt64(hub, "config.module.maxTxDSCs"); maxTxDSCs > 0 && maxTxDSCs < totalTxDSCs {
+		totalTxDSCs = maxTxDSCs
+	}
+
+	// the leaves and the TCs allocating the hub DSCs to them
+	leafModules, err := common.GetResources(d.client, "/xr-networks/"+query.NetworkId.ValueString()+"/leafModules?content=expanded")
+	if err != nil {
+		resp.Diagnostics.AddError("ConstellationCapacityDataSource: read ##: Error Get leaf modules", "Could not get, unexpected error: "+err.Error())
+		return
+	}
+	tcs, err := common.GetResources(d.client, "/transport-capacities?content=expanded")
+	if err != nil {
+		resp.Diagnostics.AddError("ConstellationCapacityDataSource: read ##: Error Get transport capacities", "Could not get, unexpected error: "+err.Error())
+		return
+	}
+	leafTx := map[string]map[int64]bool{}
+	leafRx := map[string]map[int64]bool{}
+	leafTCs := map[string][]types.String{}
+	for _, tc := range tcs {
+		tcData, ok := tc.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		links, _ := tcData["capacityLinks"].([]interface{})
+		for _, link := range links {
+			linkData, _ := link.(map[string]interface{})
+			linkState, _ := linkData["state"].(map[string]interface{})
+			hubModule, _ := linkState["hubModule"].(map[string]interface{})
+			leafModule, _ := linkState["leafModule"].(map[string]interface{})
+			if hubModule == nil || leafModule == nil || hubModule["moduleId"] != hubModuleId {
+				continue
+			}
+			leafModuleId, _ := leafModule["moduleId"].(string)
+			if leafTx[leafModuleId] == nil {
+				leafTx[leafModuleId] = map[int64]bool{}
+				leafRx[leafModuleId] = map[int64]bool{}
+			}
+			addCDSCs(leafTx[leafModuleId], hubModule["txCDSCs"])
+			addCDSCs(leafRx[leafModuleId], hubModule["rxCDSCs"])
+			addCDSCs(hubTx, hubModule["txCDSCs"])
+			addCDSCs(hubRx, hubModule["rxCDSCs"])
+			if id, ok := tcData["id"].(string); ok && !containsString(leafTCs[leafModuleId], id) {
+				leafTCs[leafModuleId] = append(leafTCs[leafModuleId], types.StringValue(id))
+			}
+		}
+	}
+
+	query.Tx = dscAllocation(totalTxDSCs, hubTx)
+	query.Rx = dscAllocation(totalDSCs, hubRx)
+	query.Leaves = []LeafCapacityData{}
+	for _, leaf := range leafModules {
+		leafData, ok := leaf.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		leafModuleId, _ := common.LookupValue(leafData, "state.module.moduleId")
+		leafDSCs := lookupInt64(leafData, "config.module.maxDSCs")
+		// the leaf transmits in the leaf to hub direction
+		leafTxDSCs := leafDSCs
+		if maxTxDSCs := lookupInt64(leafData, "config.module.maxTxDSCs"); maxTxDSCs > 0 && maxTxDSCs < leafTxDSCs {
+			leafTxDSCs = maxTxDSCs
+		}
+		transportCapacityIds := leafTCs[leafModuleId]
+		if transportCapacityIds == nil {
+			transportCapacityIds = []types.String{}
+		}
+		query.Leaves = append(query.Leaves, LeafCapacityData{
+			ModuleId:             lookupString(leafData, "state.module.moduleId"),
+			ModuleName:           lookupString(leafData, "state.module.moduleName"),
+			Tx:                   dscAllocation(leafDSCs, leafTx[leafModuleId]),
+			Rx:                   dscAllocation(leafTxDSCs, leafRx[leafModuleId]),
+			TransportCapacityIds: transportCapacityIds,
+		})
+	}
+
+	diags = resp.State.Set(ctx, query)
+	resp.Diagnostics.Append(diags...)
+	tflog.Debug(ctx, "ConstellationCapacityDataSource: get ", map[string]interface{}{"capacity": query})
+}
+
+// dscAllocation returns the allocation of the total DSCs, the total is unknown when it is 0.
+func dscAllocation(total int64, cdscs map[int64]bool) *DSCAllocationData {
+	allocated := []types.Int64{}
+	keys := make([]int64, 0, len(cdscs))
+	for cdsc := range cdscs {
+		keys = append(keys, cdsc)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	for _, cdsc := range keys {
+		allocated = append(allocated, types.Int64Value(cdsc))
+	}
+	allocation := DSCAllocationData{
+		Total:          types.Int64Null(),
+		Allocated:      types.Int64Value(int64(len(keys))),
+		Free:           types.Int64Null(),
+		AllocatedCDSCs: allocated,
+	}
+	if total > 0 {
+		allocation.Total = types.Int64Value(total)
+		free := total - int64(len(keys))
+		if free < 0 {
+			free = 0
+		}
+		allocation.Free = types.Int64Value(free)
+	}
+	return &allocation
+}
+
+func addCDSCs(cdscs map[int64]bool, values interface{}) {
+	list, _ := values.([]interface{})
+	for _, v := range list {
+		if cdsc, ok := v.(float64); ok {
+			cdscs[int64(cdsc)] = true
+		}
+	}
+}
+
+func containsString(values []types.String, value string) bool {
+	for _, v := range values {
+		if v.ValueString() == value {
+			return true
+		}
+	}
+	return false
+}
+
+func colId(data interface{}) string {
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	value, _ := common.LookupValue(dataMap, "colId")
+	return value
+}
+
+func lookupString(data map[string]interface{}, key string) types.String {
+	if value, found := common.LookupValue(data, key); found {
+		return types.StringValue(value)
+	}
+	return types.StringNull()
+}
+
+func lookupInt64(data map[string]interface{}, key string) int64 {
+	value, _ := common.LookupValue(data, key)
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return int64(number)
+}
+
+func DSCAllocationSchemaAttributes(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: description,
+		Computed:    true,
+		Attributes: map[string]schema.Attribute{
+			"total": schema.Int64Attribute{
+				Description: "number of DSCs, null if unknown",
+				Computed:    true,
+			},
+			"allocated": schema.Int64Attribute{
+				Description: "number of allocated DSCs",
+				Computed:    true,
+			},
+			"free": schema.Int64Attribute{
+				Description: "number of free DSCs, null if the total is unknown",
+				Computed:    true,
+			},
+			"allocated_cdscs": schema.ListAttribute{
+				Description: "allocated constellation DSC numbers",
+				Computed:    true,
+				ElementType: types.Int64Type,
+			},
+		},
+	}
+}
+
+func ConstellationCapacityDataSchemaAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"network_id": schema.StringAttribute{
+			Description: "Network ID",
+			Required:    true,
+		},
+		"hub_module_id": schema.StringAttribute{
+			Description: "module ID of the hub",
+			Computed:    true,
+		},
+		"hub_module_name": schema.StringAttribute{
+			Description: "module name of the hub",
+			Computed:    true,
+		},
+		"planned_capacity": schema.StringAttribute{
+			Description: "planned capacity of the hub",
+			Computed:    true,
+		},
+		"tx": DSCAllocationSchemaAttributes("hub DSCs in the hub to leaf direction"),
+		"rx": DSCAllocationSchemaAttributes("hub DSCs in the leaf to hub direction"),
+		"leaves": schema.ListNestedAttribute{
+			Description: "DSCs allocated to each leaf module",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"module_id": schema.StringAttribute{
+						Description: "module ID of the leaf",
+						Computed:    true,
+					},
+					"module_name": schema.StringAttribute{
+						Description: "module name of the leaf",
+						Computed:    true,
+					},
+					"tx": DSCAllocationSchemaAttributes("DSCs from the hub to the leaf, the total is the DSCs of the leaf"),
+					"rx": DSCAllocationSchemaAttributes("DSCs from the leaf to the hub, the total is the DSCs of the leaf"),
+					"transport_capacity_ids": schema.ListAttribute{
+						Description: "TCs between the hub and the leaf",
+						Computed:    true,
+						ElementType: types.StringType,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,56 @@
+package network
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDSCAllocation(t *testing.T) {
+	tests := []struct {
+		name       string
+		total      int64
+		cdscs      map[int64]bool
+		allocation DSCAllocationData
+	}{
+		{"unknown total", 0, map[int64]bool{3: true, 1: true}, DSCAllocationData{
+			Total:          types.Int64Null(),
+			Allocated:      types.Int64Value(2),
+			Free:           types.Int64Null(),
+			AllocatedCDSCs: []types.Int64{types.Int64Value(1), types.Int64Value(3)},
+		}},
+		{"none allocated", 16, map[int64]bool{}, DSCAllocationData{
+			Total:          types.Int64Value(16),
+			Allocated:      types.Int64Value(0),
+			Free:           types.Int64Value(16),
+			AllocatedCDSCs: []types.Int64{},
+		}},
+		{"sorted", 16, map[int64]bool{9: true, 2: true, 5: true}, DSCAllocationData{
+			Total:          types.Int64Value(16),
+			Allocated:      types.Int64Value(3),
+			Free:           types.Int64Value(13),
+			AllocatedCDSCs: []types.Int64{types.Int64Value(2), types.Int64Value(5), types.Int64Value(9)},
+		}},
+		{"over allocated", 2, map[int64]bool{1: true, 2: true, 3: true}, DSCAllocationData{
+			Total:          types.Int64Value(2),
+			Allocated:      types.Int64Value(3),
+			Free:           types.Int64Value(0),
+			AllocatedCDSCs: []types.Int64{types.Int64Value(1), types.Int64Value(2), types.Int64Value(3)},
+		}},
+	}
+	for _, test := range tests {
+		if allocation := dscAllocation(test.total, test.cdscs); !reflect.DeepEqual(*allocation, test.allocation) {
+			t.Errorf("%s: dscAllocation() = %+v, want %+v", test.name, *allocation, test.allocation)
+		}
+	}
+}
+
+func TestAddCDSCs(t *testing.T) {
+	cdscs := map[int64]bool{1: true}
+	addCDSCs(cdscs, []interface{}{float64(2), "3", float64(1)})
+	addCDSCs(cdscs, nil)
+	if want := map[int64]bool{1: true, 2: true}; !reflect.DeepEqual(cdscs, want) {
+		t.Errorf("addCDSCs() = %v, want %v", cdscs, want)
+	}
+}
@@ -0,0 +1,152 @@
+package network
+
+import (
+	"context"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &ResolveSelectorDataSource{}
+	_ datasource.DataSourceWithConfigure = &ResolveSelectorDataSource{}
+)
+
+// NewResolveSelectorDataSource is a helper function to simplify the provider implementation.
+func NewResolveSelectorDataSource() datasource.DataSource {
+	return &ResolveSelectorDataSource{}
+}
+
+// ResolveSelectorDataSource resolves a module or host port selector the way IPM does.
+type ResolveSelectorDataSource struct {
+	client *ipm_pf.Client
+}
+
+type ResolveSelectorDataSourceData struct {
+	Selector       *common.IfSelector `tfsdk:"selector"`
+	ModuleId       types.String       `tfsdk:"module_id"`
+	ModuleName     types.String       `tfsdk:"module_name"`
+	SerialNumber   types.String       `tfsdk:"serial_number"`
+	MACAddress     types.String       `tfsdk:"mac_address"`
+	ClientIfAid    types.String       `tfsdk:"client_if_aid"`
+	HostName       types.String       `tfsdk:"host_name"`
+	HostPortName   types.String       `tfsdk:"host_port_name"`
+	NetworkId      types.String       `tfsdk:"network_id"`
+	NetworkName    types.String       `tfsdk:"network_name"`
+	Role           types.String       `tfsdk:"role"`
+	LifecycleState types.String       `tfsdk:"lifecycle_state"`
+}
+
+// Metadata returns the data source type name.
+func (r *ResolveSelectorDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_resolve_selector"
+}
+
+func (d *ResolveSelectorDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	selector := common.IfSelectorSchema()
+	selector.Optional = false
+	selector.Required = true
+	selector.Description = "The module interface or host port selector to resolve. The module_client_if_aid of module interface selectors may be omitted to resolve the module only."
+	resp.Schema = schema.Schema{
+		Description: "Resolves a selector to its module, the host port bound to it, and the constellation the module is a member of.",
+		Attributes: map[string]schema.Attribute{
+			"selector": selector,
+			"module_id": schema.StringAttribute{
+				Description: "module_id",
+				Computed:    true,
+			},
+			"module_name": schema.StringAttribute{
+				Description: "module_name",
+				Computed:    true,
+			},
+			"serial_number": schema.StringAttribute{
+				Description: "serial_number",
+				Computed:    true,
+			},
+			"mac_address": schema.StringAttribute{
+				Description: "mac_address",
+				Computed:    true,
+			},
+			"client_if_aid": schema.StringAttribute{
+				Description: "client interface AID of the module, for interface and host port selectors",
+				Computed:    true,
+			},
+			"host_name": schema.StringAttribute{
+				Description: "host_name, for host port selectors",
+				Computed:    true,
+			},
+			"host_port_name": schema.StringAttribute{
+				Description: "host_port_name, for host port selectors",
+				Computed:    true,
+			},
+			"network_id": schema.StringAttribute{
+				Description: "ID of the constellation the module is a member of",
+				Computed:    true,
+			},
+			"network_name": schema.StringAttribute{
+				Description: "name of the constellation the module is a member of",
+				Computed:    true,
+			},
+			"role": schema.StringAttribute{
+				Description: "role of the module in its constellation: hub or leaf",
+				Computed:    true,
+			},
+			"lifecycle_state": schema.StringAttribute{
+				Description: "lifecycle state of the module in its constellation",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *ResolveSelectorDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+func (d *ResolveSelectorDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	query := ResolveSelectorDataSourceData{}
+	diags := req.Config.Get(ctx, &query)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	variant, values := common.IfSelectorValues(query.Selector)
+	tflog.Debug(ctx, "ResolveSelectorDataSource: resolve selector", map[string]interface{}{"selector": common.SelectorString(variant, values)})
+
+	resolved, err := common.NewSelectorResolver(d.client).Resolve(variant, values)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("selector"),
+			"ResolveSelectorDataSource: read ##: Error Resolve selector",
+			"Could not resolve the selector, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	query.ModuleId = common.OptionalString(resolved.ModuleId)
+	query.ModuleName = common.OptionalString(resolved.ModuleName)
+	query.SerialNumber = common.OptionalString(resolved.SerialNumber)
+	query.MACAddress = common.OptionalString(resolved.MACAddress)
+	query.ClientIfAid = common.OptionalString(resolved.ClientIfAid)
+	query.HostName = common.OptionalString(resolved.HostName)
+	query.HostPortName = common.OptionalString(resolved.HostPortName)
+	query.NetworkId = common.OptionalString(resolved.NetworkId)
+	query.NetworkName = common.OptionalString(resolved.NetworkName)
+	query.Role = common.OptionalString(resolved.Role)
+	query.LifecycleState = common.OptionalString(resolved.LifecycleState)
+
+	diags = resp.State.Set(ctx, &query)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,441 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+	host "terraform-provider-ipm/internal/provider/internal/hostservice"
+	transportcapacity "terraform-provider-ipm/internal/provider/internal/transportcapacityservice"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &TopologyDataSource{}
+	_ datasource.DataSourceWithConfigure = &TopologyDataSource{}
+)
+
+// NewTopologyDataSource is a helper function to simplify the provider implementation.
+func NewTopologyDataSource() datasource.DataSource {
+	return &TopologyDataSource{}
+}
+
+// TopologyDataSource returns the graph of a constellation network: its modules, the hosts attached to them,
+// and the capacity links, TCs and NCs between them.
+type TopologyDataSource struct {
+	client *ipm_pf.Client
+}
+
+type TopologyNodeData struct {
+	Id    types.String `tfsdk:"id"`
+	Kind  types.String `tfsdk:"kind"`
+	Name  types.String `tfsdk:"name"`
+	State types.String `tfsdk:"state"`
+}
+
+type TopologyEdgeData struct {
+	Id     types.String `tfsdk:"id"`
+	Kind   types.String `tfsdk:"kind"`
+	Source types.String `tfsdk:"source"`
+	Target types.String `tfsdk:"target"`
+	Name   types.String `tfsdk:"name"`
+	State  types.String `tfsdk:"state"`
+}
+
+type TopologyDataSourceData struct {
+	NetworkId types.String       `tfsdk:"network_id"`
+	Nodes     []TopologyNodeData `tfsdk:"nodes"`
+	Edges     []TopologyEdgeData `tfsdk:"edges"`
+	Dot       types.String       `tfsdk:"dot"`
+	Json      types.String       `tfsdk:"json"`
+}
+
+// Metadata returns the data source type name.
+func (r *TopologyDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_topology"
+}
+
+func (d *TopologyDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Returns the graph of a constellation network: the hub, leaf and reachable modules and the hosts attached to them as nodes, the capacity links, TCs, NCs and host attachments as edges, rendered as Graphviz DOT and JSON.",
+		Attributes:  TopologyDataSchemaAttributes(),
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *TopologyDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+func (d *TopologyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	query := TopologyDataSourceData{}
+	diags := req.Config.Get(ctx, &query)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	networkId := query.NetworkId.ValueString()
+	tflog.Debug(ctx, "TopologyDataSource: get topology", map[string]interface{}{"network_id": networkId})
+
+	graph := topologyGraph{nodes: map[string]*TopologyNodeData{}, edges: map[string]*TopologyEdgeData{}}
+
+	// the modules: hub, leaves, and the reachable modules which are not leaves yet, as the hub and leaf module data sources populate them
+	hub, err := common.GetResource(d.client, "/xr-networks/"+networkId+"/hubModule?content=expanded")
+	if err != nil {
+		resp.Diagnostics.AddError("TopologyDataSource: read ##: Error Get hub module", "Could not get the hub module of network "+networkId+", unexpected error: "+err.Error())
+		return
+	}
+	hubModuleId := graph.addModule(types.ObjectValueMust(NWModuleAttributeType(), NWModuleAttributeValue(hub)), "hub")
+	leafModules, err := common.GetResources(d.client, "/xr-networks/"+networkId+"/leafModules?content=expanded")
+	if err != nil {
+		resp.Diagnostics.AddError("TopologyDataSource: read ##: Error Get leaf modules", "Could not get, unexpected error: "+err.Error())
+		return
+	}
+	for _, leaf := range NWModulesValue(topologyObjects(leafModules)) {
+		graph.addModule(leaf, "leaf")
+	}
+	reachableModules, err := common.GetResources(d.client, "/xr-networks/"+networkId+"/reachableModules?content=expanded")
+	if err != nil {
+		resp.Diagnostics.AddError("TopologyDataSource: read ##: Error Get reachable modules", "Could not get, unexpected error: "+err.Error())
+		return
+	}
+	// the reachable modules have the shape of the network modules, ReachableModuleResourceData does not keep their module id
+	for _, reachable := range NWModulesValue(topologyObjects(reachableModules)) {
+		moduleId := graph.addModule(reachable, "reachable")
+		if hubModuleId != "" && moduleId != "" && graph.nodes[moduleId].Kind.ValueString() == "reachable" {
+			graph.addEdge("reachable:"+moduleId, "reachable", hubModuleId, moduleId, "", "")
+		}
+	}
+
+	// the hosts whose ports are attached to the modules, as the hosts data source populates them
+	hosts, err := common.GetResources(d.client, "/hosts?content=expanded")
+	if err != nil {
+		resp.Diagnostics.AddError("TopologyDataSource: read ##: Error Get hosts", "Could not get, unexpected error: "+err.Error())
+		return
+	}
+	for _, hostValue := range host.HostObjectsValue(topologyObjects(hosts)) {
+		graph.addHost(hostValue)
+	}
+
+	// the capacity links, TCs and NCs between the modules
+	capacityLinks, err := common.GetResources(d.client, "/capacity-links?content=expanded")
+	if err != nil {
+		resp.Diagnostics.AddError("TopologyDataSource: read ##: Error Get capacity links", "Could not get, unexpected error: "+err.Error())
+		return
+	}
+	for _, link := range transportcapacity.TCCapacityLinkObjectsValue(topologyObjects(capacityLinks)) {
+		source := attrString(link, "state", "hub_module", "module_id")
+		target := attrString(link, "state", "leaf_module", "module_id")
+		graph.addEdge("capacity-link:"+attrString(link, "id"), "capacity_link", source, target, "", attrString(link, "state", "life_cycle_state"))
+	}
+	for _, collection := range []struct{ uri, kind string }{
+		{"/transport-capacities", "transport_capacity"},
+		{"/network-connections", "network_connection"},
+	} {
+		services, err := common.GetResources(d.client, collection.uri+"?content=expanded")
+		if err != nil {
+			resp.Diagnostics.AddError("TopologyDataSource: read ##: Error Get "+collection.kind, "Could not get, unexpected error: "+err.Error())
+			return
+		}
+		for _, s := range services {
+			service, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			id, _ := service["id"].(string)
+			name, _ := common.LookupValue(service, "config.name")
+			lifecycleState, _ := common.LookupValue(service, "state.lifecycleState")
+			endpoints, _ := service["endpoints"].([]interface{})
+			moduleIds := []string{}
+			for _, e := range endpoints {
+				endpoint, _ := e.(map[string]interface{})
+				moduleId, _ := common.LookupValue(endpoint, "state.moduleIf.moduleId")
+				moduleIds = append(moduleIds, moduleId)
+			}
+			// the endpoint of the hub module is the source, the first endpoint when the hub is not an endpoint.
+			// A P2MP service has an edge to each leaf.
+			source := 0
+			for i, moduleId := range moduleIds {
+				if moduleId != "" && moduleId == hubModuleId {
+					source = i
+					break
+				}
+			}
+			for i := range moduleIds {
+				if i == source {
+					continue
+				}
+				edgeId := strings.ReplaceAll(collection.kind, "_", "-") + ":" + id
+				if len(moduleIds) > 2 {
+					edgeId = edgeId + ":" + strconv.Itoa(i)
+				}
+				graph.addEdge(edgeId, collection.kind, moduleIds[source], moduleIds[i], name, lifecycleState)
+			}
+		}
+	}
+
+	query.Nodes, query.Edges = graph.sorted()
+	query.Dot = types.StringValue(topologyDot(networkId, query.Nodes, query.Edges))
+	graphJson, err := topologyJson(networkId, query.Nodes, query.Edges)
+	if err != nil {
+		resp.Diagnostics.AddError("TopologyDataSource: read ##: Error Marshal topology", "Could not render the topology as JSON, unexpected error: "+err.Error())
+		return
+	}
+	query.Json = types.StringValue(graphJson)
+
+	diags = resp.State.Set(ctx, query)
+	resp.Diagnostics.Append(diags...)
+	tflog.Debug(ctx, "TopologyDataSource: get ", map[string]interface{}{"nodes": len(query.Nodes), "edges": len(query.Edges)})
+}
+
+type topologyGraph struct {
+	nodes map[string]*TopologyNodeData
+	edges map[string]*TopologyEdgeData
+}
+
+// addModule adds the module of a populated hub, leaf or reachable module as node, and returns its id.
+// A module is added once, the hub and leaves are read before the reachable modules.
+func (g *topologyGraph) addModule(module attr.Value, kind string) string {
+	moduleId := attrString(module, "state", "module", "module_id")
+	if moduleId == "" || g.nodes[moduleId] != nil {
+		return moduleId
+	}
+	g.nodes[moduleId] = &TopologyNodeData{
+		Id:    types.StringValue(moduleId),
+		Kind:  types.StringValue(kind),
+		Name:  common.OptionalString(attrString(module, "state", "module", "module_name")),
+		State: common.OptionalString(attrString(module, "state", "lifecycle_state")),
+	}
+	return moduleId
+}
+
+// addHost adds a populated host as node when one of its ports is attached to a module of the graph, with an edge per
+// attached port.
+func (g *topologyGraph) addHost(host attr.Value) {
+	hostId := attrString(host, "id")
+	hostObject, _ := host.(types.Object)
+	ports, _ := hostObject.Attributes()["ports"].(types.List)
+	for _, port := range ports.Elements() {
+		moduleId := attrString(port, "state", "module_if", "module_id")
+		if g.nodes[moduleId] == nil {
+			continue
+		}
+		hostNodeId := "host:" + hostId
+		if g.nodes[hostNodeId] == nil {
+			hostName := attrString(host, "state", "name")
+			if hostName == "" {
+				hostName = attrString(host, "config", "name")
+			}
+			g.nodes[hostNodeId] = &TopologyNodeData{
+				Id:    types.StringValue(hostNodeId),
+				Kind:  types.StringValue("host"),
+				Name:  common.OptionalString(hostName),
+				State: types.StringNull(),
+			}
+		}
+		portName := attrString(port, "state", "name")
+		clientIfAid := attrString(port, "state", "module_if", "client_if_aid")
+		g.addEdge("host-port:"+hostId+":"+attrString(port, "id"), "host_attachment", hostNodeId, moduleId, strings.Trim(portName+" - "+clientIfAid, " -"), "")
+	}
+}
+
+// addEdge adds the edge when both its nodes are in the graph.
+func (g *topologyGraph) addEdge(id string, kind string, source string, target string, name string, state string) {
+	if g.nodes[source] == nil || g.nodes[target] == nil {
+		return
+	}
+	g.edges[id] = &TopologyEdgeData{
+		Id:     types.StringValue(id),
+		Kind:   types.StringValue(kind),
+		Source: types.StringValue(source),
+		Target: types.StringValue(target),
+		Name:   common.OptionalString(name),
+		State:  common.OptionalString(state),
+	}
+}
+
+// topologyObjects returns the resources of a response which are objects, the populate helpers expect objects.
+func topologyObjects(data []interface{}) []interface{} {
+	objects := []interface{}{}
+	for _, v := range data {
+		if object, ok := v.(map[string]interface{}); ok {
+			objects = append(objects, object)
+		}
+	}
+	return objects
+}
+
+// attrString returns the string at the attribute path of a populated object, or an empty string.
+func attrString(value attr.Value, names ...string) string {
+	for _, name := range names {
+		object, ok := value.(types.Object)
+		if !ok || object.IsNull() {
+			return ""
+		}
+		value = object.Attributes()[name]
+	}
+	s, _ := value.(types.String)
+	return s.ValueString()
+}
+
+// sorted returns the nodes and edges in a stable order, so that the rendered graph only changes with the topology.
+func (g *topologyGraph) sorted() ([]TopologyNodeData, []TopologyEdgeData) {
+	kindOrder := map[string]int{"hub": 0, "leaf": 1, "reachable": 2, "host": 3}
+	nodes := []TopologyNodeData{}
+	for _, node := range g.nodes {
+		nodes = append(nodes, *node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Kind != nodes[j].Kind {
+			return kindOrder[nodes[i].Kind.ValueString()] < kindOrder[nodes[j].Kind.ValueString()]
+		}
+		return nodes[i].Id.ValueString() < nodes[j].Id.ValueString()
+	})
+	edges := []TopologyEdgeData{}
+	for _, edge := range g.edges {
+		edges = append(edges, *edge)
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].Id.ValueString() < edges[j].Id.ValueString() })
+	return nodes, edges
+}
+
+// topologyDot renders the graph in the Graphviz DOT language.
+func topologyDot(networkId string, nodes []TopologyNodeData, edges []TopologyEdgeData) string {
+	shapes := map[string]string{"hub": "shape=\"doublecircle\"", "leaf": "shape=\"circle\"", "reachable": "shape=\"circle\", style=\"dashed\"", "host": "shape=\"box\""}
+	styles := map[string]string{"capacity_link": "bold", "transport_capacity": "solid", "network_connection": "dashed", "host_attachment": "dotted", "reachable": "dotted"}
+	var b strings.Builder
+	b.WriteString("graph " + strconv.Quote("constellation "+networkId) + " {\n")
+	for _, node := range nodes {
+		label := node.Name.ValueString()
+		if label == "" {
+			label = node.Id.ValueString()
+		}
+		b.WriteString("  " + strconv.Quote(node.Id.ValueString()) + " [label=" + strconv.Quote(label) + ", " + shapes[node.Kind.ValueString()] + "];\n")
+	}
+	for _, edge := range edges {
+		label := edge.Kind.ValueString()
+		if edge.Name.ValueString() != "" {
+			label = label + ": " + edge.Name.ValueString()
+		}
+		b.WriteString("  " + strconv.Quote(edge.Source.ValueString()) + " -- " + strconv.Quote(edge.Target.ValueString()) +
+			" [label=" + strconv.Quote(label) + ", style=\"" + styles[edge.Kind.ValueString()] + "\"];\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// topologyJson renders the graph as a JSON document with nodes and edges lists.
+func topologyJson(networkId string, nodes []TopologyNodeData, edges []TopologyEdgeData) (string, error) {
+	values := func(items map[string]types.String) map[string]interface{} {
+		result := map[string]interface{}{}
+		for k, v := range items {
+			if !v.IsNull() {
+				result[k] = v.ValueString()
+			}
+		}
+		return result
+	}
+	graph := map[string]interface{}{"networkId": networkId}
+	jsonNodes := []interface{}{}
+	for _, node := range nodes {
+		jsonNodes = append(jsonNodes, values(map[string]types.String{"id": node.Id, "kind": node.Kind, "name": node.Name, "state": node.State}))
+	}
+	jsonEdges := []interface{}{}
+	for _, edge := range edges {
+		jsonEdges = append(jsonEdges, values(map[string]types.String{"id": edge.Id, "kind": edge.Kind, "source": edge.Source, "target": edge.Target, "name": edge.Name, "state": edge.State}))
+	}
+	graph["nodes"] = jsonNodes
+	graph["edges"] = jsonEdges
+	rb, err := json.MarshalIndent(graph, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(rb), nil
+}
+
+func TopologyDataSchemaAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"network_id": schema.StringAttribute{
+			Description: "Numeric identifier of the Constellation Network.",
+			Required:    true,
+		},
+		"nodes": schema.ListNestedAttribute{
+			Description: "The modules and hosts of the constellation",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Description: "module ID, or host:<host ID> for hosts",
+						Computed:    true,
+					},
+					"kind": schema.StringAttribute{
+						Description: "hub, leaf, reachable or host",
+						Computed:    true,
+					},
+					"name": schema.StringAttribute{
+						Description: "module or host name",
+						Computed:    true,
+					},
+					"state": schema.StringAttribute{
+						Description: "lifecycle state of the hub or leaf",
+						Computed:    true,
+					},
+				},
+			},
+		},
+		"edges": schema.ListNestedAttribute{
+			Description: "The capacity links, TCs, NCs and host attachments between the nodes",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Description: "<kind>:<IPM ID>, P2MP services have an edge per leaf",
+						Computed:    true,
+					},
+					"kind": schema.StringAttribute{
+						Description: "capacity_link, transport_capacity, network_connection, host_attachment or reachable",
+						Computed:    true,
+					},
+					"source": schema.StringAttribute{
+						Description: "node ID of the hub, or of the host for host attachments",
+						Computed:    true,
+					},
+					"target": schema.StringAttribute{
+						Description: "node ID of the leaf or attached module",
+						Computed:    true,
+					},
+					"name": schema.StringAttribute{
+						Description: "name of the TC or NC, or host port of host attachments",
+						Computed:    true,
+					},
+					"state": schema.StringAttribute{
+						Description: "lifecycle state",
+						Computed:    true,
+					},
+				},
+			},
+		},
+		"dot": schema.StringAttribute{
+			Description: "The topology in the Graphviz DOT language",
+			Computed:    true,
+		},
+		"json": schema.StringAttribute{
+			Description: "The topology as JSON document",
+			Computed:    true,
+		},
+	}
+}
@@ -0,0 +1,105 @@
+package network
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	host "terraform-provider-ipm/internal/provider/internal/hostservice"
+	transportcapacity "terraform-provider-ipm/internal/provider/internal/transportcapacityservice"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files of testdata")
+
+func testNetworkModule(moduleId string, moduleName string, lifecycleState string) interface{} {
+	return map[string]interface{}{
+		"id": "nm-" + moduleId,
+		"state": map[string]interface{}{
+			"lifecycleState": lifecycleState,
+			"module":         map[string]interface{}{"moduleId": moduleId, "moduleName": moduleName},
+		},
+	}
+}
+
+// testTopology builds the graph of a hub with a leaf, a reachable module, a host attached to the hub and the leaf, and a
+// capacity link, from IPM resources populated as the data sources do.
+func testTopology() ([]TopologyNodeData, []TopologyEdgeData) {
+	graph := topologyGraph{nodes: map[string]*TopologyNodeData{}, edges: map[string]*TopologyEdgeData{}}
+	hub := testNetworkModule("m1", "hub-1", "configured").(map[string]interface{})
+	hubModuleId := graph.addModule(types.ObjectValueMust(NWModuleAttributeType(), NWModuleAttributeValue(hub)), "hub")
+	for _, leaf := range NWModulesValue(topologyObjects([]interface{}{"not a module", testNetworkModule("m2", "leaf-2", "configured")})) {
+		graph.addModule(leaf, "leaf")
+	}
+	for _, reachable := range NWModulesValue(topologyObjects([]interface{}{testNetworkModule("m2", "leaf-2", ""), testNetworkModule("m3", "", "")})) {
+		moduleId := graph.addModule(reachable, "reachable")
+		if graph.nodes[moduleId].Kind.ValueString() == "reachable" {
+			graph.addEdge("reachable:"+moduleId, "reachable", hubModuleId, moduleId, "", "")
+		}
+	}
+	port := func(id string, name string, moduleId string, clientIfAid string) interface{} {
+		return map[string]interface{}{
+			"id":    id,
+			"state": map[string]interface{}{"name": name, "moduleIf": map[string]interface{}{"moduleId": moduleId, "clientIfAid": clientIfAid}},
+		}
+	}
+	hosts := []interface{}{
+		map[string]interface{}{
+			"id":    "h1",
+			"state": map[string]interface{}{"name": "router-1"},
+			"ports": []interface{}{port("p1", "Ethernet1/1", "m1", "XR-T1"), port("p2", "", "m2", "XR-T2")},
+		},
+		map[string]interface{}{
+			"id":    "h2",
+			"state": map[string]interface{}{"name": "router-2"},
+			"ports": []interface{}{port("p3", "Ethernet1/1", "m9", "XR-T1")},
+		},
+	}
+	for _, hostValue := range host.HostObjectsValue(topologyObjects(hosts)) {
+		graph.addHost(hostValue)
+	}
+	capacityLinks := []interface{}{
+		map[string]interface{}{
+			"id": "cl1",
+			"state": map[string]interface{}{
+				"lifecycleState": "configured",
+				"hubModule":      map[string]interface{}{"moduleId": "m1"},
+				"leafModule":     map[string]interface{}{"moduleId": "m2"},
+			},
+		},
+	}
+	for _, link := range transportcapacity.TCCapacityLinkObjectsValue(topologyObjects(capacityLinks)) {
+		graph.addEdge("capacity-link:"+attrString(link, "id"), "capacity_link", attrString(link, "state", "hub_module", "module_id"),
+			attrString(link, "state", "leaf_module", "module_id"), "", attrString(link, "state", "life_cycle_state"))
+	}
+	graph.addEdge("transport-capacity:tc1", "transport_capacity", "m1", "m2", "tc-1", "configured")
+	return graph.sorted()
+}
+
+func TestTopologyRendering(t *testing.T) {
+	nodes, edges := testTopology()
+	dot := topologyDot("n1", nodes, edges)
+	graphJson, err := topologyJson("n1", nodes, edges)
+	if err != nil {
+		t.Fatalf("topologyJson() error = %v", err)
+	}
+	for _, golden := range []struct{ file, rendered string }{
+		{"topology.dot", dot},
+		{"topology.json", graphJson + "\n"},
+	} {
+		if *updateGolden {
+			if err := os.WriteFile(filepath.Join("testdata", golden.file), []byte(golden.rendered), 0644); err != nil {
+				t.Fatalf("writing %s: %v", golden.file, err)
+			}
+		}
+		want, err := os.ReadFile(filepath.Join("testdata", golden.file))
+		if err != nil {
+			t.Fatalf("reading %s: %v", golden.file, err)
+		}
+		if golden.rendered != string(want) {
+			t.Errorf("%s differs, got:\n%s", golden.file, golden.rendered)
+		}
+	}
+}
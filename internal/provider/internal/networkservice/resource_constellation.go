@@ -0,0 +1,663 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	common "terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &ConstellationResource{}
+	_ resource.ResourceWithConfigure   = &ConstellationResource{}
+	_ resource.ResourceWithImportState = &ConstellationResource{}
+	_ resource.ResourceWithModifyPlan  = &ConstellationResource{}
+)
+
+// NewConstellationResource is a helper function to simplify the provider implementation.
+func NewConstellationResource() resource.Resource {
+	return &ConstellationResource{}
+}
+
+// ConstellationResource manages a constellation network with its hub and leaf modules as one intent.
+type ConstellationResource struct {
+	client   *ipm_pf.Client
+	profiles *common.Profiles
+}
+
+type ConstellationLeafData struct {
+	Id             types.String `tfsdk:"id"`
+	Href           types.String `tfsdk:"href"`
+	Profile        types.String `tfsdk:"profile"`
+	Config         *NodeConfig  `tfsdk:"config"`
+	LifecycleState types.String `tfsdk:"lifecycle_state"`
+	State          types.Object `tfsdk:"state"`
+}
+
+type ConstellationResourceData struct {
+	Id          types.String                     `tfsdk:"id"`
+	Href        types.String                     `tfsdk:"href"`
+	Profile     types.String                     `tfsdk:"profile"`
+	Config      *NetworkConfig                   `tfsdk:"config"`
+	State       types.Object                     `tfsdk:"state"`
+	HubModule   *ModuleResourceData              `tfsdk:"hub_module"`
+	LeafModules map[string]ConstellationLeafData `tfsdk:"leaf_modules"`
+}
+
+// Metadata returns the data source type name.
+func (r *ConstellationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_constellation"
+}
+
+// Schema defines the schema for the data source.
+func (r *ConstellationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a constellation network with its hub module and leaf modules",
+		Attributes:  ConstellationSchemaAttributes(),
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *ConstellationResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*common.ProviderData).Client
+	r.profiles = req.ProviderData.(*common.ProviderData).Profiles
+}
+
+func (r *ConstellationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ConstellationResourceData
+
+	diags := req.Config.Get(ctx, &data)
+
+	tflog.Debug(ctx, "ConstellationResource: Create - ", map[string]interface{}{"ConstellationResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.create(&data, ctx, &resp.Diagnostics)
+	if data.Id.IsNull() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ConstellationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ConstellationResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "ConstellationResource: Read - ", map[string]interface{}{"ConstellationResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.read(&data, ctx, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ConstellationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ConstellationResourceData
+	var state ConstellationResourceData
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Debug(ctx, "ConstellationResource: Update", map[string]interface{}{"plan": plan, "state": state})
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = state.Id
+	plan.Href = state.Href
+	r.update(&plan, &state, ctx, &resp.Diagnostics)
+
+	// the network, hub module and leaves which are changed before an error are kept in the state, the others keep their state
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ConstellationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ConstellationResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "ConstellationResource: Delete", map[string]interface{}{"ConstellationResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+
+	r.delete(&data, ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+// ModifyPlan validates the profile references and plans new leaf ids for the leaves whose selector changes.
+func (r *ConstellationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	// the references are checked once the plan values are known
+	var plan ConstellationResourceData
+	if diags := req.Plan.Get(ctx, &plan); diags.HasError() {
+		return
+	}
+
+	if !plan.Profile.IsNull() && !plan.Profile.IsUnknown() {
+		if _, _, _, err := r.profiles.FindNetworkProfile(plan.Profile.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("profile"), "ConstellationResource: Invalid profile", err.Error())
+		}
+	}
+	for key, leaf := range plan.LeafModules {
+		if leaf.Profile.IsNull() || leaf.Profile.IsUnknown() {
+			continue
+		}
+		if _, err := r.profiles.FindModuleConfigProfile(leaf.Profile.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("leaf_modules").AtMapKey(key).AtName("profile"), "ConstellationResource: Invalid profile", err.Error())
+		}
+	}
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+	var state ConstellationResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for key, leaf := range plan.LeafModules {
+		stateLeaf, ok := state.LeafModules[key]
+		if !ok || !leafSelectorChanged(&leaf, &stateLeaf) {
+			continue
+		}
+		// the leaf is removed and added again
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("leaf_modules").AtMapKey(key).AtName("id"), types.StringUnknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("leaf_modules").AtMapKey(key).AtName("href"), types.StringUnknown())...)
+	}
+}
+
+func (r *ConstellationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Retrieve import ID and save to id attribute
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *ConstellationResource) create(plan *ConstellationResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	tflog.Debug(ctx, "ConstellationResource: create ## ", map[string]interface{}{"plan": plan})
+
+	if plan.Config == nil || plan.HubModule == nil || plan.HubModule.Config == nil {
+		diags.AddError(
+			"Error Create ConstellationResource",
+			"Create: Could not create ConstellationResource, config and hub_module config must be specified",
+		)
+		return
+	}
+
+	// create the network with its hub module
+	network := plan.networkData()
+	networkResource := NetworkResource{client: r.client, profiles: r.profiles}
+	networkResource.create(&network, ctx, diags)
+	if diags.HasError() {
+		return
+	}
+	plan.setNetworkData(&network)
+
+	// add the leaf modules
+	for _, key := range sortedLeafKeys(plan.LeafModules) {
+		leaf := plan.LeafModules[key]
+		r.createLeaf(plan, key, &leaf, ctx, diags)
+		if diags.HasError() {
+			// keep the created leaves, the others are added again at the next apply
+			for _, other := range sortedLeafKeys(plan.LeafModules) {
+				if plan.LeafModules[other].Id.IsNull() {
+					delete(plan.LeafModules, other)
+				}
+			}
+			return
+		}
+		plan.LeafModules[key] = leaf
+	}
+
+	r.read(plan, ctx, diags)
+
+	tflog.Debug(ctx, "ConstellationResource: create ##", map[string]interface{}{"plan": plan})
+}
+
+func (r *ConstellationResource) update(plan *ConstellationResourceData, state *ConstellationResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	if plan.Id.IsNull() {
+		diags.AddError(
+			"Error Update ConstellationResource",
+			"Update: Could not Update. ConstellationResource ID is not specified",
+		)
+		return
+	}
+
+	// keep the state of the network, hub module and leaves until they are changed, so that a failed change is planned again
+	planned := *plan
+	planLeaves := plan.LeafModules
+	plan.Config = state.Config
+	plan.Profile = state.Profile
+	plan.HubModule = state.HubModule
+	plan.LeafModules = make(map[string]ConstellationLeafData)
+	for key, leaf := range state.LeafModules {
+		plan.LeafModules[key] = leaf
+	}
+	profileChanged := !planned.Profile.Equal(state.Profile)
+
+	// update the network config
+	if !reflect.DeepEqual(planned.Config, state.Config) || profileChanged {
+		network := planned.networkData()
+		networkResource := NetworkResource{client: r.client, profiles: r.profiles}
+		networkResource.update(&network, ctx, diags)
+		if diags.HasError() {
+			return
+		}
+	}
+	plan.Config = planned.Config
+
+	// update the hub module
+	if planned.HubModule != nil && state.HubModule != nil && planned.HubModule.Config != nil &&
+		(!reflect.DeepEqual(planned.HubModule.Config, state.HubModule.Config) || profileChanged) {
+		var hubProfile common.ModuleConfigProfile
+		if !planned.Profile.IsNull() {
+			var err error
+			_, _, hubProfile, err = r.profiles.FindNetworkProfile(planned.Profile.ValueString())
+			if err != nil {
+				diags.AddError(
+					"Error Update ConstellationResource",
+					"Update: Could not Update hub module, "+err.Error(),
+				)
+				return
+			}
+		}
+		hub := *planned.HubModule
+		hub.NetworkId = plan.Id
+		hubResource := HubModuleResource{client: r.client}
+		hubResource.update(&hub, ctx, diags, hubProfile)
+		if diags.HasError() {
+			return
+		}
+	}
+	plan.HubModule = planned.HubModule
+
+	// remove the leaves which are not planned anymore, or whose selector changes
+	for _, key := range sortedLeafKeys(state.LeafModules) {
+		stateLeaf := state.LeafModules[key]
+		planLeaf, ok := planLeaves[key]
+		if ok && !leafSelectorChanged(&planLeaf, &stateLeaf) {
+			continue
+		}
+		r.deleteLeaf(plan, key, &stateLeaf, ctx, diags)
+		if diags.HasError() {
+			return
+		}
+		delete(plan.LeafModules, key)
+	}
+
+	// add the new leaves and update the changed ones
+	for _, key := range sortedLeafKeys(planLeaves) {
+		leaf := planLeaves[key]
+		stateLeaf, ok := plan.LeafModules[key]
+		if !ok {
+			r.createLeaf(&planned, key, &leaf, ctx, diags)
+		} else if !reflect.DeepEqual(leaf.Config, stateLeaf.Config) || !leaf.Profile.Equal(stateLeaf.Profile) || profileChanged {
+			leaf.Id = stateLeaf.Id
+			leaf.Href = stateLeaf.Href
+			r.updateLeaf(&planned, key, &leaf, ctx, diags)
+		} else {
+			continue
+		}
+		if diags.HasError() {
+			return
+		}
+		plan.LeafModules[key] = leaf
+	}
+	if planLeaves == nil && len(plan.LeafModules) == 0 {
+		plan.LeafModules = nil
+	}
+	// the profile is kept until the network, the hub module and the leaves are updated with it
+	plan.Profile = planned.Profile
+
+	r.read(plan, ctx, diags, 2)
+
+	tflog.Debug(ctx, "ConstellationResource: update ## ", map[string]interface{}{"plan": plan})
+}
+
+func (r *ConstellationResource) read(state *ConstellationResourceData, ctx context.Context, diags *diag.Diagnostics, retryCount ...int) {
+
+	if state.Id.IsNull() {
+		diags.AddError(
+			"ConstellationResource: Error read ConstellationResource",
+			"Read: Could not read. ConstellationResource ID is not specified",
+		)
+		return
+	}
+
+	// an imported constellation has no hub module yet
+	imported := state.HubModule == nil
+
+	// read the network with its hub module
+	network := state.networkData()
+	networkResource := NetworkResource{client: r.client, profiles: r.profiles}
+	networkResource.read(&network, ctx, diags, retryCount...)
+	if diags.HasError() {
+		return
+	}
+	state.setNetworkData(&network)
+
+	// read the leaf modules
+	body, err := r.client.ExecuteIPMHttpCommand("GET", "/xr-networks/"+state.Id.ValueString()+"/leafModules?content=expanded", nil)
+	if err != nil {
+		diags.AddError(
+			"ConstellationResource: read ##: Error Get Leaf Modules",
+			"Read:Could not get leaf modules, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	var data []interface{}
+	err = json.Unmarshal(body, &data)
+	if err != nil {
+		diags.AddError(
+			"ConstellationResource: read ##: Error Unmarshal Leaf Modules",
+			"Read:Could not get leaf modules, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	leafModules := make(map[string]map[string]interface{})
+	for _, v := range data {
+		leafModule := v.(map[string]interface{})
+		if leafModule["id"] != nil {
+			leafModules[leafModule["id"].(string)] = leafModule
+		}
+	}
+
+	// import: the leaves are named by their module name
+	if imported {
+		state.LeafModules = make(map[string]ConstellationLeafData)
+		for id, leafModule := range leafModules {
+			key := leafModuleName(leafModule)
+			if key == "" {
+				key = id
+			}
+			leaf := ConstellationLeafData{Id: types.StringValue(id), Profile: types.StringNull()}
+			leaf.populate(leafModule, ctx, diags, true)
+			state.LeafModules[key] = leaf
+		}
+		return
+	}
+
+	for key, leaf := range state.LeafModules {
+		leafModule, ok := leafModules[leaf.Id.ValueString()]
+		if !ok {
+			// the leaf is removed outside of terraform, it is added again at the next apply
+			tflog.Debug(ctx, "ConstellationResource: read ## leaf module not found", map[string]interface{}{"leaf": key, "id": leaf.Id.ValueString()})
+			delete(state.LeafModules, key)
+			continue
+		}
+		leaf.populate(leafModule, ctx, diags)
+		state.LeafModules[key] = leaf
+	}
+
+	tflog.Debug(ctx, "ConstellationResource: read SUCCESS ", map[string]interface{}{"state": state})
+}
+
+func (r *ConstellationResource) delete(state *ConstellationResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	if state.Id.IsNull() {
+		diags.AddError(
+			"ConstellationResource: Error Delete ConstellationResource",
+			"Delete: Could not delete. ConstellationResource Id is not specified",
+		)
+		return
+	}
+
+	// remove the leaves before the network
+	for _, key := range sortedLeafKeys(state.LeafModules) {
+		leaf := state.LeafModules[key]
+		r.deleteLeaf(state, key, &leaf, ctx, diags)
+		if diags.HasError() {
+			return
+		}
+	}
+
+	network := state.networkData()
+	networkResource := NetworkResource{client: r.client, profiles: r.profiles}
+	networkResource.delete(&network, ctx, diags)
+
+	tflog.Debug(ctx, "ConstellationResource: delete ## ", map[string]interface{}{"state": state})
+}
+
+func (r *ConstellationResource) createLeaf(plan *ConstellationResourceData, key string, leaf *ConstellationLeafData, ctx context.Context, diags *diag.Diagnostics) {
+
+	tflog.Debug(ctx, "ConstellationResource: create leaf ## ", map[string]interface{}{"leaf": key})
+
+	if leaf.Config == nil {
+		diags.AddError(
+			"ConstellationResource: Error Create Leaf Module",
+			"Create: Could not create leaf module "+key+", config is not specified",
+		)
+		return
+	}
+	profile, err := r.leafProfile(plan, leaf)
+	if err != nil {
+		diags.AddError(
+			"ConstellationResource: Error Create Leaf Module",
+			"Create: Could not create leaf module "+key+", "+err.Error(),
+		)
+		return
+	}
+
+	module := ModuleResourceData{NetworkId: plan.Id, Config: leaf.Config}
+	leafResource := LeafModuleResource{client: r.client}
+	leafResource.create(&module, ctx, diags, profile)
+	if diags.HasError() {
+		return
+	}
+	leaf.setModuleData(&module)
+}
+
+func (r *ConstellationResource) updateLeaf(plan *ConstellationResourceData, key string, leaf *ConstellationLeafData, ctx context.Context, diags *diag.Diagnostics) {
+
+	tflog.Debug(ctx, "ConstellationResource: update leaf ## ", map[string]interface{}{"leaf": key, "id": leaf.Id.ValueString()})
+
+	profile, err := r.leafProfile(plan, leaf)
+	if err != nil {
+		diags.AddError(
+			"ConstellationResource: Error Update Leaf Module",
+			"Update: Could not update leaf module "+key+", "+err.Error(),
+		)
+		return
+	}
+
+	module := ModuleResourceData{NetworkId: plan.Id, Id: leaf.Id, Href: leaf.Href, Config: leaf.Config}
+	leafResource := LeafModuleResource{client: r.client}
+	leafResource.update(&module, ctx, diags, profile)
+	if diags.HasError() {
+		return
+	}
+	leaf.setModuleData(&module)
+}
+
+func (r *ConstellationResource) deleteLeaf(state *ConstellationResourceData, key string, leaf *ConstellationLeafData, ctx context.Context, diags *diag.Diagnostics) {
+
+	tflog.Debug(ctx, "ConstellationResource: delete leaf ## ", map[string]interface{}{"leaf": key, "id": leaf.Id.ValueString()})
+
+	if leaf.Id.IsNull() || leaf.Id.IsUnknown() {
+		return
+	}
+	_, err := r.client.ExecuteIPMHttpCommand("DELETE", "/xr-networks/"+state.Id.ValueString()+"/leafModules/"+leaf.Id.ValueString(), nil)
+	if err != nil && !strings.Contains(err.Error(), "status: 404") {
+		diags.AddError(
+			"ConstellationResource: delete ##: Error Delete Leaf Module",
+			"Delete:Could not delete leaf module "+key+", unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// networkData returns the constellation network as managed by ipm_constellation_network.
+func (cData *ConstellationResourceData) networkData() NetworkResourceData {
+	return NetworkResourceData{
+		Id:               cData.Id,
+		Href:             cData.Href,
+		Profile:          cData.Profile,
+		Config:           cData.Config,
+		State:            cData.State,
+		HubModule:        cData.HubModule,
+		LeafModules:      types.ListNull(NWModuleObjectType()),
+		ReachableModules: types.ListNull(NWReachableModuleObjectType()),
+	}
+}
+
+func (cData *ConstellationResourceData) setNetworkData(network *NetworkResourceData) {
+	cData.Id = network.Id
+	cData.Href = network.Href
+	cData.Config = network.Config
+	cData.State = network.State
+	cData.HubModule = network.HubModule
+}
+
+func (leaf *ConstellationLeafData) setModuleData(module *ModuleResourceData) {
+	leaf.Id = module.Id
+	leaf.Href = module.Href
+	leaf.Config = module.Config
+	leaf.State = module.State
+	leaf.LifecycleState = leafLifecycleState(module.State)
+}
+
+func (leaf *ConstellationLeafData) populate(data map[string]interface{}, ctx context.Context, diags *diag.Diagnostics, computeOnly ...bool) {
+	module := ModuleResourceData{Id: leaf.Id, Href: leaf.Href, Config: leaf.Config}
+	module.Populate(data, ctx, diags, computeOnly...)
+	leaf.setModuleData(&module)
+}
+
+// leafProfile returns the module config profile of the leaf, or the leaf config profile of the network profile.
+func (r *ConstellationResource) leafProfile(plan *ConstellationResourceData, leaf *ConstellationLeafData) (common.ModuleConfigProfile, error) {
+	if !leaf.Profile.IsNull() {
+		return r.profiles.FindModuleConfigProfile(leaf.Profile.ValueString())
+	}
+	if plan.Profile.IsNull() {
+		return common.ModuleConfigProfile{}, nil
+	}
+	networkProfile, _, _, err := r.profiles.FindNetworkProfile(plan.Profile.ValueString())
+	if err != nil || networkProfile.LeafConfigProfile.IsNull() {
+		return common.ModuleConfigProfile{}, err
+	}
+	return r.profiles.FindModuleConfigProfile(networkProfile.LeafConfigProfile.ValueString())
+}
+
+func leafSelectorChanged(plan *ConstellationLeafData, state *ConstellationLeafData) bool {
+	if plan.Config == nil || state.Config == nil {
+		return plan.Config != state.Config
+	}
+	return !reflect.DeepEqual(plan.Config.Selector, state.Config.Selector)
+}
+
+func leafLifecycleState(state types.Object) types.String {
+	if state.IsNull() || state.IsUnknown() {
+		return types.StringNull()
+	}
+	lifecycleState, ok := state.Attributes()["lifecycle_state"].(types.String)
+	if !ok {
+		return types.StringNull()
+	}
+	return lifecycleState
+}
+
+func leafModuleName(data map[string]interface{}) string {
+	state, ok := data["state"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	module, ok := state["module"].(map[string]interface{})
+	if !ok || module["moduleName"] == nil {
+		return ""
+	}
+	return module["moduleName"].(string)
+}
+
+func sortedLeafKeys(leaves map[string]ConstellationLeafData) []string {
+	keys := make([]string, 0, len(leaves))
+	for k := range leaves {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ConstellationLeafSchemaAttributes returns the attributes of a leaf of a leaf module map, profileDescription describes the profile default.
+func ConstellationLeafSchemaAttributes(profileDescription string) map[string]schema.Attribute {
+	leafAttributes := LeafModuleSchemaAttributes()
+	delete(leafAttributes, "network_id")
+	leafAttributes["profile"] = schema.StringAttribute{
+		Description: "Name of a module config profile of the provider profiles. " + profileDescription,
+		Optional:    true,
+	}
+	leafAttributes["lifecycle_state"] = schema.StringAttribute{
+		Description: "lifecycle state of the leaf module",
+		Computed:    true,
+	}
+	return leafAttributes
+}
+
+func ConstellationSchemaAttributes() map[string]schema.Attribute {
+	networkAttributes := NetworkSchemaAttributes()
+	leafAttributes := ConstellationLeafSchemaAttributes("Defaults to the leaf config profile of the network profile.")
+
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Description: "Numeric identifier of the Network.",
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"href": schema.StringAttribute{
+			Description: "href",
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"profile":    networkAttributes["profile"],
+		"config":     networkAttributes["config"],
+		"state":      networkAttributes["state"],
+		"hub_module": networkAttributes["hub_module"],
+		"leaf_modules": schema.MapNestedAttribute{
+			Description: "leaf modules by name, with their selector and module settings",
+			Optional:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: leafAttributes,
+			},
+		},
+	}
+}
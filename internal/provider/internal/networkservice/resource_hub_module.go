@@ -48,6 +48,12 @@ type ModuleSelector struct {
 	HostPortSelectorByPortSourceMAC    *common.HostPortSelectorByPortSourceMAC    `tfsdk:"host_port_selector_by_port_source_mac"`
 }
 
+// Values returns the IPM name of the selector variant, and its values by IPM attribute name.
+func (s *ModuleSelector) Values() (string, map[string]types.String) {
+	return common.ModuleSelectorValues(s.ModuleSelectorByModuleId, s.ModuleSelectorByModuleName, s.ModuleSelectorByModuleMAC, s.ModuleSelectorByModuleSerialNumber,
+		s.HostPortSelectorByName, s.HostPortSelectorByPortId, s.HostPortSelectorBySysName, s.HostPortSelectorByPortSourceMAC)
+}
+
 type ConfigModule struct {
 	PlannedCapacity           types.String `tfsdk:"planned_capacity"`
 	TrafficMode               types.String `tfsdk:"traffic_mode"`
@@ -95,7 +101,7 @@ func (r *HubModuleResource) Configure(_ context.Context, req resource.ConfigureR
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r HubModuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -169,7 +175,8 @@ func (r *HubModuleResource) ImportState(ctx context.Context, req resource.Import
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
-func (r *HubModuleResource) update(plan *ModuleResourceData, ctx context.Context, diags *diag.Diagnostics) {
+// update changes the hub module settings. The settings not specified in the plan are taken from the optional module config profile.
+func (r *HubModuleResource) update(plan *ModuleResourceData, ctx context.Context, diags *diag.Diagnostics, profile ...common.ModuleConfigProfile) {
 
 	tflog.Debug(ctx, "HubModuleResource: update - plan", map[string]interface{}{"plan": plan})
 	if plan.NetworkId.IsNull() {
@@ -204,6 +211,9 @@ func (r *HubModuleResource) update(plan *ModuleResourceData, ctx context.Context
 	if !plan.Config.ManagedBy.IsNull() {
 		module["managedBy"] = plan.Config.ManagedBy.ValueString()
 	}
+	if len(profile) > 0 {
+		mergeModuleProfile(module, profile[0])
+	}
 	updateRequest["module"] = module
 
 	// send Update request to server
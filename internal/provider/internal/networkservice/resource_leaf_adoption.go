@@ -0,0 +1,600 @@
+package network
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	common "terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &LeafAdoptionResource{}
+	_ resource.ResourceWithConfigure   = &LeafAdoptionResource{}
+	_ resource.ResourceWithImportState = &LeafAdoptionResource{}
+	_ resource.ResourceWithModifyPlan  = &LeafAdoptionResource{}
+)
+
+// NewLeafAdoptionResource is a helper function to simplify the provider implementation.
+func NewLeafAdoptionResource() resource.Resource {
+	return &LeafAdoptionResource{}
+}
+
+// LeafAdoptionResource adds the reachable modules of a constellation which match a policy as leaf modules.
+type LeafAdoptionResource struct {
+	client   *ipm_pf.Client
+	profiles *common.Profiles
+}
+
+type LeafAdoptionMatchData struct {
+	SerialNumbers []types.String `tfsdk:"serial_numbers"`
+	Labels        types.Map      `tfsdk:"labels"`
+	NameRegex     types.String   `tfsdk:"name_regex"`
+}
+
+type AdoptedLeafData struct {
+	Id             types.String `tfsdk:"id"`
+	ModuleId       types.String `tfsdk:"module_id"`
+	SerialNumber   types.String `tfsdk:"serial_number"`
+	LifecycleState types.String `tfsdk:"lifecycle_state"`
+}
+
+type LeafAdoptionResourceData struct {
+	Id              types.String               `tfsdk:"id"`
+	NetworkId       types.String               `tfsdk:"network_id"`
+	Match           *LeafAdoptionMatchData     `tfsdk:"match"`
+	Profile         types.String               `tfsdk:"profile"`
+	RemoveUnmatched types.Bool                 `tfsdk:"remove_unmatched"`
+	Leaves          map[string]AdoptedLeafData `tfsdk:"leaves"`
+}
+
+// adoptionModule is a reachable module or a leaf module, as matched by the policy.
+type adoptionModule struct {
+	key          string
+	moduleId     string
+	moduleName   string
+	serialNumber string
+	leafId       string
+	leafState    string
+}
+
+// Metadata returns the data source type name.
+func (r *LeafAdoptionResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_leaf_adoption"
+}
+
+// Schema defines the schema for the data source.
+func (r *LeafAdoptionResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Adds the reachable modules of a constellation network which match a policy as leaf modules. The policy is evaluated at each plan, " +
+			"the modules which became reachable since the last apply are adopted at the next apply. The adopted leaves are removed when the resource is destroyed.",
+		Attributes: LeafAdoptionSchemaAttributes(),
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *LeafAdoptionResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*common.ProviderData).Client
+	r.profiles = req.ProviderData.(*common.ProviderData).Profiles
+}
+
+func (r *LeafAdoptionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data LeafAdoptionResourceData
+
+	diags := req.Config.Get(ctx, &data)
+
+	tflog.Debug(ctx, "LeafAdoptionResource: Create - ", map[string]interface{}{"LeafAdoptionResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = data.NetworkId
+	data.Leaves = make(map[string]AdoptedLeafData)
+	r.apply(&data, ctx, &resp.Diagnostics)
+
+	// the leaves which are adopted before an error are kept in the state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *LeafAdoptionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data LeafAdoptionResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "LeafAdoptionResource: Read - ", map[string]interface{}{"LeafAdoptionResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.read(&data, ctx, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *LeafAdoptionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan LeafAdoptionResourceData
+	var state LeafAdoptionResourceData
+
+	// the planned leaves are unknown, the settings are read from the config
+	diags := req.Config.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Debug(ctx, "LeafAdoptionResource: Update", map[string]interface{}{"plan": plan, "state": state})
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = state.Id
+	plan.Leaves = state.Leaves
+	if plan.Leaves == nil {
+		plan.Leaves = make(map[string]AdoptedLeafData)
+	}
+	r.apply(&plan, ctx, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *LeafAdoptionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data LeafAdoptionResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "LeafAdoptionResource: Delete", map[string]interface{}{"LeafAdoptionResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+
+	for _, key := range sortedAdoptedLeafKeys(data.Leaves) {
+		r.deleteLeaf(&data, key, ctx, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+// ModifyPlan validates the policy and the profile, and plans an update when modules are to be adopted or removed.
+func (r *LeafAdoptionResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	// the planned leaves may be unknown, the settings are read from the config
+	var plan LeafAdoptionResourceData
+	if diags := req.Config.Get(ctx, &plan); diags.HasError() {
+		return
+	}
+
+	if plan.Match != nil {
+		if len(plan.Match.SerialNumbers) == 0 && plan.Match.Labels.IsNull() && plan.Match.NameRegex.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("match"), "LeafAdoptionResource: Invalid match policy",
+				"At least one of serial_numbers, labels and name_regex must be specified, a policy matching all the reachable modules is not allowed.")
+		}
+		if !plan.Match.NameRegex.IsNull() && !plan.Match.NameRegex.IsUnknown() {
+			if _, err := regexp.Compile(plan.Match.NameRegex.ValueString()); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("match").AtName("name_regex"), "LeafAdoptionResource: Invalid name_regex", err.Error())
+			}
+		}
+	}
+	if !plan.Profile.IsNull() && !plan.Profile.IsUnknown() {
+		if _, err := r.profiles.FindModuleConfigProfile(plan.Profile.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("profile"), "LeafAdoptionResource: Invalid profile", err.Error())
+		}
+	}
+	if resp.Diagnostics.HasError() || req.State.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	// the policy is evaluated against the reachable modules of now
+	var state LeafAdoptionResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || plan.NetworkId.IsUnknown() || !plan.NetworkId.Equal(state.NetworkId) {
+		return
+	}
+	plan.Leaves = state.Leaves
+	adopt, remove, err := r.evaluate(&plan)
+	if err != nil {
+		resp.Diagnostics.AddWarning("LeafAdoptionResource: Policy not evaluated", "Could not evaluate the match policy: "+err.Error())
+		return
+	}
+	tflog.Debug(ctx, "LeafAdoptionResource: ModifyPlan ## ", map[string]interface{}{"adopt": len(adopt), "remove": remove})
+	if len(adopt) > 0 || len(remove) > 0 {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("leaves"), types.MapUnknown(types.ObjectType{AttrTypes: adoptedLeafAttributeType()}))...)
+	}
+}
+
+func (r *LeafAdoptionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Retrieve import ID and save to id attribute
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// apply adopts the reachable modules which match the policy, and removes the adopted leaves which do not match anymore.
+func (r *LeafAdoptionResource) apply(plan *LeafAdoptionResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	if plan.NetworkId.IsNull() || plan.Match == nil {
+		diags.AddError(
+			"Error Apply LeafAdoptionResource",
+			"Apply: Could not adopt leaf modules, network_id and match must be specified",
+		)
+		return
+	}
+	var profile common.ModuleConfigProfile
+	if !plan.Profile.IsNull() {
+		var err error
+		profile, err = r.profiles.FindModuleConfigProfile(plan.Profile.ValueString())
+		if err != nil {
+			diags.AddError(
+				"Error Apply LeafAdoptionResource",
+				"Apply: Could not adopt leaf modules, "+err.Error(),
+			)
+			return
+		}
+	}
+
+	adopt, remove, err := r.evaluate(plan)
+	if err != nil {
+		diags.AddError(
+			"LeafAdoptionResource: apply ##: Error Evaluate policy",
+			"Apply: Could not evaluate the match policy, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	tflog.Debug(ctx, "LeafAdoptionResource: apply ## ", map[string]interface{}{"adopt": len(adopt), "remove": remove})
+
+	for _, key := range remove {
+		r.deleteLeaf(plan, key, ctx, diags)
+		if diags.HasError() {
+			return
+		}
+		delete(plan.Leaves, key)
+	}
+
+	for _, module := range adopt {
+		selector := ModuleSelector{ModuleSelectorByModuleId: &common.ModuleSelectorByModuleId{ModuleId: types.StringValue(module.moduleId)}}
+		if module.serialNumber != "" {
+			// the serial number selects the module again when it is replaced by IPM after a reset
+			selector = ModuleSelector{ModuleSelectorByModuleSerialNumber: &common.ModuleSelectorByModuleSerialNumber{ModuleSerialNumber: types.StringValue(module.serialNumber)}}
+		}
+		leaf := ModuleResourceData{NetworkId: plan.NetworkId, Config: &NodeConfig{Selector: selector, Module: nullConfigModule(), ManagedBy: types.StringNull()}}
+		leafResource := LeafModuleResource{client: r.client}
+		leafResource.create(&leaf, ctx, diags, profile)
+		if diags.HasError() {
+			return
+		}
+		plan.Leaves[module.key] = AdoptedLeafData{
+			Id:             leaf.Id,
+			ModuleId:       types.StringValue(module.moduleId),
+			SerialNumber:   common.OptionalString(module.serialNumber),
+			LifecycleState: leafLifecycleState(leaf.State),
+		}
+	}
+
+	r.read(plan, ctx, diags)
+}
+
+// evaluate returns the reachable modules to adopt, and the keys of the adopted leaves to remove.
+func (r *LeafAdoptionResource) evaluate(plan *LeafAdoptionResourceData) ([]adoptionModule, []string, error) {
+	networkId := plan.NetworkId.ValueString()
+	leafModules, err := common.GetResources(r.client, "/xr-networks/"+networkId+"/leafModules?content=expanded")
+	if err != nil {
+		return nil, nil, err
+	}
+	reachableModules, err := common.GetResources(r.client, "/xr-networks/"+networkId+"/reachableModules?content=expanded")
+	if err != nil {
+		return nil, nil, err
+	}
+	getModule := func(moduleId string) (map[string]interface{}, error) {
+		return common.GetResource(r.client, "/modules/"+moduleId+"?content=expanded")
+	}
+	return selectAdoptions(plan, leafModules, reachableModules, getModule)
+}
+
+// selectAdoptions returns the reachable modules matching the policy which are not leaves yet, and the keys of the adopted
+// leaves which no longer match it when remove_unmatched is set. getModule returns the module whose labels are matched.
+func selectAdoptions(plan *LeafAdoptionResourceData, leafModules []interface{}, reachableModules []interface{}, getModule func(string) (map[string]interface{}, error)) ([]adoptionModule, []string, error) {
+	var nameRegex *regexp.Regexp
+	if !plan.Match.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(plan.Match.NameRegex.ValueString())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	leaves := make(map[string]adoptionModule)
+	leafModuleIds := make(map[string]bool)
+	for _, l := range leafModules {
+		data, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		leaf := adoptionModuleOf(data)
+		leaves[leaf.leafId] = leaf
+		leafModuleIds[leaf.moduleId] = true
+	}
+
+	adopt := []adoptionModule{}
+	for _, m := range reachableModules {
+		data, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		module := adoptionModuleOf(data)
+		if module.moduleId == "" || leafModuleIds[module.moduleId] {
+			continue
+		}
+		matches, err := matchesPolicy(plan.Match, nameRegex, module, getModule)
+		if err != nil {
+			return nil, nil, err
+		}
+		if matches {
+			if _, ok := plan.Leaves[module.key]; ok {
+				module.key = module.key + "-" + module.moduleId
+			}
+			adopt = append(adopt, module)
+		}
+	}
+	sort.Slice(adopt, func(i, j int) bool { return adopt[i].key < adopt[j].key })
+
+	remove := []string{}
+	if plan.RemoveUnmatched.ValueBool() {
+		for _, key := range sortedAdoptedLeafKeys(plan.Leaves) {
+			leaf, ok := leaves[plan.Leaves[key].Id.ValueString()]
+			if !ok {
+				continue
+			}
+			matches, err := matchesPolicy(plan.Match, nameRegex, leaf, getModule)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !matches {
+				remove = append(remove, key)
+			}
+		}
+	}
+	return adopt, remove, nil
+}
+
+// matchesPolicy tells whether the module matches all the criteria of the policy.
+func matchesPolicy(match *LeafAdoptionMatchData, nameRegex *regexp.Regexp, module adoptionModule, getModule func(string) (map[string]interface{}, error)) (bool, error) {
+	if len(match.SerialNumbers) > 0 {
+		found := false
+		for _, serialNumber := range match.SerialNumbers {
+			if strings.EqualFold(serialNumber.ValueString(), module.serialNumber) {
+				found = true
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+	if nameRegex != nil && !nameRegex.MatchString(module.moduleName) {
+		return false, nil
+	}
+	if !match.Labels.IsNull() && len(match.Labels.Elements()) > 0 {
+		data, err := getModule(module.moduleId)
+		if err != nil {
+			return false, err
+		}
+		labels := make(map[string]string)
+		for key, value := range match.Labels.Elements() {
+			labels[key] = value.(types.String).ValueString()
+		}
+		if !common.MatchLabels(data, labels) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (r *LeafAdoptionResource) read(state *LeafAdoptionResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	if state.Id.IsNull() {
+		diags.AddError(
+			"LeafAdoptionResource: Error read LeafAdoptionResource",
+			"Read: Could not read. LeafAdoptionResource ID is not specified",
+		)
+		return
+	}
+	// an imported adoption has the network id as id and no adopted leaves, the existing leaves are left alone
+	if state.NetworkId.IsNull() {
+		state.NetworkId = state.Id
+	}
+
+	leafModules, err := common.GetResources(r.client, "/xr-networks/"+state.NetworkId.ValueString()+"/leafModules?content=expanded")
+	if err != nil {
+		diags.AddError(
+			"LeafAdoptionResource: read ##: Error Get Leaf Modules",
+			"Read:Could not get leaf modules, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	leaves := make(map[string]adoptionModule)
+	for _, l := range leafModules {
+		data, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		leaf := adoptionModuleOf(data)
+		leaves[leaf.leafId] = leaf
+	}
+	if state.Leaves == nil {
+		state.Leaves = make(map[string]AdoptedLeafData)
+	}
+	for key, adopted := range state.Leaves {
+		leaf, ok := leaves[adopted.Id.ValueString()]
+		if !ok {
+			// the leaf is removed outside of terraform, it is adopted again at the next apply when it still matches
+			tflog.Debug(ctx, "LeafAdoptionResource: read ## leaf module not found", map[string]interface{}{"leaf": key, "id": adopted.Id.ValueString()})
+			delete(state.Leaves, key)
+			continue
+		}
+		adopted.ModuleId = common.OptionalString(leaf.moduleId)
+		adopted.SerialNumber = common.OptionalString(leaf.serialNumber)
+		adopted.LifecycleState = common.OptionalString(leaf.leafState)
+		state.Leaves[key] = adopted
+	}
+
+	tflog.Debug(ctx, "LeafAdoptionResource: read SUCCESS ", map[string]interface{}{"state": state})
+}
+
+func (r *LeafAdoptionResource) deleteLeaf(state *LeafAdoptionResourceData, key string, ctx context.Context, diags *diag.Diagnostics) {
+
+	leaf := state.Leaves[key]
+	tflog.Debug(ctx, "LeafAdoptionResource: delete leaf ## ", map[string]interface{}{"leaf": key, "id": leaf.Id.ValueString()})
+
+	if leaf.Id.IsNull() || leaf.Id.IsUnknown() {
+		return
+	}
+	_, err := r.client.ExecuteIPMHttpCommand("DELETE", "/xr-networks/"+state.NetworkId.ValueString()+"/leafModules/"+leaf.Id.ValueString(), nil)
+	if err != nil && !strings.Contains(err.Error(), "status: 404") {
+		diags.AddError(
+			"LeafAdoptionResource: delete ##: Error Delete Leaf Module",
+			"Delete:Could not delete leaf module "+key+", unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// adoptionModuleOf returns the module of a reachable module or leaf module, keyed by its module name.
+func adoptionModuleOf(data map[string]interface{}) adoptionModule {
+	module := adoptionModule{}
+	module.leafId, _ = data["id"].(string)
+	module.moduleId, _ = common.LookupValue(data, "state.module.moduleId")
+	module.moduleName, _ = common.LookupValue(data, "state.module.moduleName")
+	module.serialNumber, _ = common.LookupValue(data, "state.module.serialNumber")
+	module.leafState, _ = common.LookupValue(data, "state.lifecycleState")
+	module.key = module.moduleName
+	if module.key == "" {
+		module.key = module.moduleId
+	}
+	return module
+}
+
+// nullConfigModule returns module settings which are all taken from the profile.
+func nullConfigModule() ConfigModule {
+	return ConfigModule{
+		PlannedCapacity:           types.StringNull(),
+		TrafficMode:               types.StringNull(),
+		FiberConnectionMode:       types.StringNull(),
+		FecIterations:             types.StringNull(),
+		RequestedNominalPsdOffset: types.StringNull(),
+		MaxDSCs:                   types.Int64Null(),
+		MaxTxDSCs:                 types.Int64Null(),
+		TxCLPtarget:               types.Int64Null(),
+	}
+}
+
+func sortedAdoptedLeafKeys(leaves map[string]AdoptedLeafData) []string {
+	keys := make([]string, 0, len(leaves))
+	for k := range leaves {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func adoptedLeafAttributeType() map[string]attr.Type {
+	return map[string]attr.Type{
+		"id":              types.StringType,
+		"module_id":       types.StringType,
+		"serial_number":   types.StringType,
+		"lifecycle_state": types.StringType,
+	}
+}
+
+func LeafAdoptionSchemaAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Description: "Numeric identifier of the Network.",
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"network_id": schema.StringAttribute{
+			Description: "Numeric identifier of the Constellation Network.",
+			Required:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"match": schema.SingleNestedAttribute{
+			Description: "The policy which the reachable modules must match to be adopted. A module matches when it meets all the specified criteria.",
+			Required:    true,
+			Attributes: map[string]schema.Attribute{
+				"serial_numbers": schema.ListAttribute{
+					Description: "allowed module serial numbers",
+					Optional:    true,
+					ElementType: types.StringType,
+				},
+				"labels": schema.MapAttribute{
+					Description: "labels which the module must have",
+					Optional:    true,
+					ElementType: types.StringType,
+				},
+				"name_regex": schema.StringAttribute{
+					Description: "regular expression which the module name must match",
+					Optional:    true,
+				},
+			},
+		},
+		"profile": schema.StringAttribute{
+			Description: "Name of the module config profile of the provider profiles with the settings of the adopted leaves.",
+			Optional:    true,
+		},
+		"remove_unmatched": schema.BoolAttribute{
+			Description: "Remove the adopted leaves which do not match the policy anymore. Leaves which are not adopted by this resource are never removed.",
+			Optional:    true,
+		},
+		"leaves": schema.MapNestedAttribute{
+			Description: "The adopted leaf modules by module name",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Description: "Numeric identifier of the leaf module",
+						Computed:    true,
+					},
+					"module_id": schema.StringAttribute{
+						Description: "module_id",
+						Computed:    true,
+					},
+					"serial_number": schema.StringAttribute{
+						Description: "serial_number",
+						Computed:    true,
+					},
+					"lifecycle_state": schema.StringAttribute{
+						Description: "lifecycle state of the leaf module",
+						Computed:    true,
+					},
+				},
+			},
+		},
+	}
+}
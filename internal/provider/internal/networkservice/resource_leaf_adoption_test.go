@@ -0,0 +1,108 @@
+package network
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testModule(leafId string, moduleId string, moduleName string, serialNumber string) interface{} {
+	return map[string]interface{}{
+		"id": leafId,
+		"state": map[string]interface{}{
+			"module": map[string]interface{}{"moduleId": moduleId, "moduleName": moduleName, "serialNumber": serialNumber},
+		},
+	}
+}
+
+func TestSelectAdoptions(t *testing.T) {
+	leafModules := []interface{}{
+		"not a leaf",
+		testModule("l1", "m1", "leaf-1", "SN1"),
+		testModule("l2", "m2", "spare-2", "SN2"),
+	}
+	reachableModules := []interface{}{
+		"not a module",
+		testModule("r1", "m1", "leaf-1", "SN1"),
+		testModule("r3", "m3", "leaf-3", "SN3"),
+		testModule("r4", "m4", "spare-4", "SN4"),
+		testModule("r5", "m5", "leaf-5", "sn5"),
+		testModule("r6", "", "leaf-6", "SN6"),
+	}
+	moduleLabels := map[string]interface{}{
+		"m1": map[string]interface{}{"config": map[string]interface{}{"labels": map[string]interface{}{"role": "leaf"}}},
+		"m3": map[string]interface{}{"config": map[string]interface{}{"labels": map[string]interface{}{"role": "leaf"}}},
+		"m4": map[string]interface{}{"config": map[string]interface{}{"labels": map[string]interface{}{"role": "leaf"}}},
+	}
+	getModule := func(moduleId string) (map[string]interface{}, error) {
+		data, _ := moduleLabels[moduleId].(map[string]interface{})
+		return data, nil
+	}
+	adopted := map[string]AdoptedLeafData{
+		"leaf-1": {Id: types.StringValue("l1")},
+		"leaf-2": {Id: types.StringValue("l2")},
+	}
+	noMatch := func() *LeafAdoptionMatchData {
+		return &LeafAdoptionMatchData{Labels: types.MapNull(types.StringType), NameRegex: types.StringNull()}
+	}
+	serialNumbers := noMatch()
+	serialNumbers.SerialNumbers = []types.String{types.StringValue("SN3"), types.StringValue("SN5")}
+	nameRegex := noMatch()
+	nameRegex.NameRegex = types.StringValue("^leaf-")
+	labels := noMatch()
+	labels.Labels = types.MapValueMust(types.StringType, map[string]attr.Value{"role": types.StringValue("leaf")})
+	both := noMatch()
+	both.NameRegex = types.StringValue("^leaf-")
+	both.Labels = labels.Labels
+
+	tests := []struct {
+		name            string
+		match           *LeafAdoptionMatchData
+		leaves          map[string]AdoptedLeafData
+		removeUnmatched bool
+		adopt           []string
+		remove          []string
+	}{
+		{"all", noMatch(), nil, false, []string{"leaf-3", "leaf-5", "spare-4"}, []string{}},
+		{"serial numbers", serialNumbers, nil, false, []string{"leaf-3", "leaf-5"}, []string{}},
+		{"name regex", nameRegex, nil, false, []string{"leaf-3", "leaf-5"}, []string{}},
+		{"labels", labels, nil, false, []string{"leaf-3", "spare-4"}, []string{}},
+		{"all criteria", both, nil, false, []string{"leaf-3"}, []string{}},
+		{"unmatched kept", nameRegex, adopted, false, []string{"leaf-3", "leaf-5"}, []string{}},
+		{"unmatched removed", nameRegex, adopted, true, []string{"leaf-3", "leaf-5"}, []string{"leaf-2"}},
+		{"unmatched removed by labels", labels, adopted, true, []string{"leaf-3", "spare-4"}, []string{"leaf-2"}},
+		{"key taken", nameRegex, map[string]AdoptedLeafData{"leaf-3": {Id: types.StringValue("l9")}}, false, []string{"leaf-3-m3", "leaf-5"}, []string{}},
+	}
+	for _, test := range tests {
+		plan := LeafAdoptionResourceData{Match: test.match, RemoveUnmatched: types.BoolValue(test.removeUnmatched), Leaves: test.leaves}
+		adopt, remove, err := selectAdoptions(&plan, leafModules, reachableModules, getModule)
+		if err != nil {
+			t.Errorf("%s: selectAdoptions() error = %v", test.name, err)
+			continue
+		}
+		keys := []string{}
+		for _, module := range adopt {
+			keys = append(keys, module.key)
+		}
+		if !reflect.DeepEqual(keys, test.adopt) {
+			t.Errorf("%s: selectAdoptions() adopt = %v, want %v", test.name, keys, test.adopt)
+		}
+		if !reflect.DeepEqual(remove, test.remove) {
+			t.Errorf("%s: selectAdoptions() remove = %v, want %v", test.name, remove, test.remove)
+		}
+	}
+}
+
+func TestMatchesPolicyError(t *testing.T) {
+	match := &LeafAdoptionMatchData{
+		Labels:    types.MapValueMust(types.StringType, map[string]attr.Value{"role": types.StringValue("leaf")}),
+		NameRegex: types.StringNull(),
+	}
+	getModule := func(string) (map[string]interface{}, error) { return nil, errors.New("status: 500") }
+	if _, err := matchesPolicy(match, nil, adoptionModule{moduleId: "m1"}, getModule); err == nil {
+		t.Errorf("matchesPolicy() error = nil, want the error of the module")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"terraform-provider-ipm/internal/ipm_pf"
+	common "terraform-provider-ipm/internal/provider/internal/common"
 
 	//	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -56,7 +57,7 @@ func (r *LeafModuleResource) Configure(_ context.Context, req resource.Configure
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r LeafModuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -140,7 +141,8 @@ func (r *LeafModuleResource) ImportState(ctx context.Context, req resource.Impor
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
-func (r *LeafModuleResource) create(plan *ModuleResourceData, ctx context.Context, diags *diag.Diagnostics) {
+// create adds the leaf module to the network. The settings not specified in the plan are taken from the optional module config profile.
+func (r *LeafModuleResource) create(plan *ModuleResourceData, ctx context.Context, diags *diag.Diagnostics, profile ...common.ModuleConfigProfile) {
 
 	if plan.NetworkId.IsNull() {
 		diags.AddError(
@@ -150,83 +152,10 @@ func (r *LeafModuleResource) create(plan *ModuleResourceData, ctx context.Contex
 		return
 	}
 
-	if plan.Config.Selector.ModuleSelectorByModuleId == nil && plan.Config.Selector.ModuleSelectorByModuleName == nil &&
-		plan.Config.Selector.ModuleSelectorByModuleMAC == nil && plan.Config.Selector.ModuleSelectorByModuleSerialNumber == nil &&
-		plan.Config.Selector.HostPortSelectorByName == nil && plan.Config.Selector.HostPortSelectorByPortId == nil &&
-		plan.Config.Selector.HostPortSelectorBySysName == nil && plan.Config.Selector.HostPortSelectorByPortSourceMAC == nil {
-		diags.AddError(
-			"LeafModuleResource: Error Create LeafModuleResource",
-			"Create: Could not create LeafModuleResource, No hub module selector specified",
-		)
+	createRequest := leafModuleCreateRequest(plan.Config, diags, profile...)
+	if createRequest == nil {
 		return
 	}
-
-	var createRequest = make(map[string]interface{})
-	// get Module setting
-	var selector = make(map[string]interface{})
-	var module = make(map[string]interface{})
-	aSelector := make(map[string]interface{})
-	if plan.Config.Selector.ModuleSelectorByModuleId != nil {
-		aSelector["moduleId"] = plan.Config.Selector.ModuleSelectorByModuleId.ModuleId.ValueString()
-		selector["moduleSelectorByModuleId"] = aSelector
-	} else if plan.Config.Selector.ModuleSelectorByModuleName != nil {
-		aSelector["moduleName"] = plan.Config.Selector.ModuleSelectorByModuleName.ModuleName.ValueString()
-		selector["moduleSelectorByModuleName"] = aSelector
-	} else if plan.Config.Selector.ModuleSelectorByModuleMAC != nil {
-		aSelector["moduleMAC"] = plan.Config.Selector.ModuleSelectorByModuleMAC.ModuleMAC.ValueString()
-		selector["moduleSelectorByModuleMAC"] = aSelector
-	} else if plan.Config.Selector.ModuleSelectorByModuleSerialNumber != nil {
-		aSelector["moduleSerialNumber"] = plan.Config.Selector.ModuleSelectorByModuleSerialNumber.ModuleSerialNumber.ValueString()
-		selector["moduleSelectorByModuleSerialNumber"] = aSelector
-	} else if plan.Config.Selector.HostPortSelectorByName != nil {
-		aSelector["hostName"] = plan.Config.Selector.HostPortSelectorByName.HostName.ValueString()
-		aSelector["hostPortName"] = plan.Config.Selector.HostPortSelectorByName.HostPortName.ValueString()
-		selector["hostPortSelectorByName"] = aSelector
-	} else if plan.Config.Selector.HostPortSelectorByPortId != nil {
-		aSelector["chassisId"] = plan.Config.Selector.HostPortSelectorByPortId.ChassisId.ValueString()
-		aSelector["chassisIdSubtype"] = plan.Config.Selector.HostPortSelectorByPortId.ChassisIdSubtype.ValueString()
-		aSelector["portId"] = plan.Config.Selector.HostPortSelectorByPortId.PortId.ValueString()
-		aSelector["portIdSubtype"] = plan.Config.Selector.HostPortSelectorByPortId.PortIdSubtype.ValueString()
-		selector["hostPortSelectorByPortId"] = aSelector
-	} else if plan.Config.Selector.HostPortSelectorBySysName != nil {
-		aSelector["sysName"] = plan.Config.Selector.HostPortSelectorBySysName.SysName.ValueString()
-		aSelector["portId"] = plan.Config.Selector.HostPortSelectorByPortId.PortId.ValueString()
-		aSelector["portIdSubtype"] = plan.Config.Selector.HostPortSelectorByPortId.PortIdSubtype.ValueString()
-		selector["hostPortSelectorBySysName"] = aSelector
-	} else if plan.Config.Selector.HostPortSelectorByPortSourceMAC != nil {
-		aSelector["portSourceMAC"] = plan.Config.Selector.HostPortSelectorByPortSourceMAC.PortSourceMAC.ValueString()
-		selector["hostPortSelectorByPortSourceMAC"] = aSelector
-	}
-	createRequest["selector"] = selector
-
-	if !plan.Config.Module.TrafficMode.IsNull() {
-		module["trafficMode"] = plan.Config.Module.TrafficMode.ValueString()
-	}
-	if !plan.Config.Module.FecIterations.IsNull() {
-		module["fecIterations"] = plan.Config.Module.FecIterations.ValueString()
-	}
-	if !plan.Config.Module.FiberConnectionMode.IsNull() {
-		module["fiberConnectionMode"] = plan.Config.Module.FiberConnectionMode.ValueString()
-	}
-	if !plan.Config.ManagedBy.IsNull() {
-		module["managedBy"] = plan.Config.ManagedBy.ValueString()
-	}
-	if !plan.Config.Module.RequestedNominalPsdOffset.IsNull() {
-		module["requestedNominalPsdOffset"] = plan.Config.Module.RequestedNominalPsdOffset.ValueString()
-	}
-	if !plan.Config.Module.MaxDSCs.IsNull() {
-		module["maxDSCs"] = plan.Config.Module.MaxDSCs.ValueInt64()
-	}
-	if !plan.Config.Module.MaxTxDSCs.IsNull() {
-		module["maxTxDSCs"] = plan.Config.Module.MaxTxDSCs.ValueInt64()
-	}
-	if !plan.Config.Module.TxCLPtarget.IsNull() {
-		module["txCLPtarget"] = plan.Config.Module.TxCLPtarget.ValueInt64()
-	}
-	if !plan.Config.Module.PlannedCapacity.IsNull() {
-		module["plannedCapacity"] = plan.Config.Module.PlannedCapacity.ValueString()
-	}
-	createRequest["module"] = module
 	tflog.Debug(ctx, "LeafModuleResource: create ## ", map[string]interface{}{"Create Reauest": createRequest})
 
 	var request []map[string]interface{}
@@ -281,7 +210,8 @@ func (r *LeafModuleResource) create(plan *ModuleResourceData, ctx context.Contex
 	tflog.Debug(ctx, "LeafModuleResource: create ##", map[string]interface{}{"plan": plan})
 }
 
-func (r *LeafModuleResource) update(plan *ModuleResourceData, ctx context.Context, diags *diag.Diagnostics) {
+// update changes the leaf module settings. The settings not specified in the plan are taken from the optional module config profile.
+func (r *LeafModuleResource) update(plan *ModuleResourceData, ctx context.Context, diags *diag.Diagnostics, profile ...common.ModuleConfigProfile) {
 
 	if plan.NetworkId.IsNull() || plan.Id.IsNull() {
 		diags.AddError(
@@ -315,6 +245,9 @@ func (r *LeafModuleResource) update(plan *ModuleResourceData, ctx context.Contex
 	if !plan.Config.ManagedBy.IsNull() {
 		module["managedBy"] = plan.Config.ManagedBy.ValueString()
 	}
+	if len(profile) > 0 {
+		mergeModuleProfile(module, profile[0])
+	}
 	updateRequest["module"] = module
 
 	// send Update request to server
@@ -424,3 +357,68 @@ func (r *LeafModuleResource) delete(plan *ModuleResourceData, ctx context.Contex
 
 	tflog.Debug(ctx, "LeafModuleResource: delete ## ", map[string]interface{}{"plan": plan})
 }
+
+// leafModuleCreateRequest returns the create request of a leaf module, or nil when the config has no selector.
+// The settings not specified in the config are taken from the optional module config profile.
+func leafModuleCreateRequest(config *NodeConfig, diags *diag.Diagnostics, profile ...common.ModuleConfigProfile) map[string]interface{} {
+	if config == nil {
+		diags.AddError(
+			"LeafModuleResource: Error Create LeafModuleResource",
+			"Create: Could not create LeafModuleResource, config is not specified",
+		)
+		return nil
+	}
+	variant, values := config.Selector.Values()
+	if variant == "" {
+		diags.AddError(
+			"LeafModuleResource: Error Create LeafModuleResource",
+			"Create: Could not create LeafModuleResource, No hub module selector specified",
+		)
+		return nil
+	}
+
+	var createRequest = make(map[string]interface{})
+	// get Module setting
+	var module = make(map[string]interface{})
+	createRequest["selector"] = common.SelectorRequest(variant, values)
+
+	if !config.Module.TrafficMode.IsNull() {
+		module["trafficMode"] = config.Module.TrafficMode.ValueString()
+	}
+	if !config.Module.FecIterations.IsNull() {
+		module["fecIterations"] = config.Module.FecIterations.ValueString()
+	}
+	if !config.Module.FiberConnectionMode.IsNull() {
+		module["fiberConnectionMode"] = config.Module.FiberConnectionMode.ValueString()
+	}
+	if !config.ManagedBy.IsNull() {
+		module["managedBy"] = config.ManagedBy.ValueString()
+	}
+	if !config.Module.RequestedNominalPsdOffset.IsNull() {
+		module["requestedNominalPsdOffset"] = config.Module.RequestedNominalPsdOffset.ValueString()
+	}
+	if !config.Module.MaxDSCs.IsNull() {
+		module["maxDSCs"] = config.Module.MaxDSCs.ValueInt64()
+	}
+	if !config.Module.MaxTxDSCs.IsNull() {
+		module["maxTxDSCs"] = config.Module.MaxTxDSCs.ValueInt64()
+	}
+	if !config.Module.TxCLPtarget.IsNull() {
+		module["txCLPtarget"] = config.Module.TxCLPtarget.ValueInt64()
+	}
+	if !config.Module.PlannedCapacity.IsNull() {
+		module["plannedCapacity"] = config.Module.PlannedCapacity.ValueString()
+	}
+	if len(profile) > 0 {
+		mergeModuleProfile(module, profile[0])
+	}
+	createRequest["module"] = module
+	return createRequest
+}
+
+func mergeModuleProfile(module map[string]interface{}, profile common.ModuleConfigProfile) {
+	common.MergeProfile(module, profile.Request())
+	if _, ok := module["managedBy"]; !ok && !profile.ManagedBy.IsNull() {
+		module["managedBy"] = profile.ManagedBy.ValueString()
+	}
+}
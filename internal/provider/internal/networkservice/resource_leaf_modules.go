@@ -0,0 +1,563 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	common "terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &LeafModulesResource{}
+	_ resource.ResourceWithConfigure   = &LeafModulesResource{}
+	_ resource.ResourceWithImportState = &LeafModulesResource{}
+	_ resource.ResourceWithModifyPlan  = &LeafModulesResource{}
+)
+
+// NewLeafModulesResource is a helper function to simplify the provider implementation.
+func NewLeafModulesResource() resource.Resource {
+	return &LeafModulesResource{}
+}
+
+// LeafModulesResource manages the leaf modules of a constellation network as one set. The new leaves are added by one request.
+type LeafModulesResource struct {
+	client   *ipm_pf.Client
+	profiles *common.Profiles
+}
+
+type LeafModulesResourceData struct {
+	Id          types.String                     `tfsdk:"id"`
+	NetworkId   types.String                     `tfsdk:"network_id"`
+	Profile     types.String                     `tfsdk:"profile"`
+	LeafModules map[string]ConstellationLeafData `tfsdk:"leaf_modules"`
+}
+
+// Metadata returns the data source type name.
+func (r *LeafModulesResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_leaf_modules"
+}
+
+// Schema defines the schema for the data source.
+func (r *LeafModulesResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the leaf modules of a constellation network as one set: the new leaves are added by one request, the removed leaves are deleted and the changed leaves updated.",
+		Attributes:  LeafModulesSchemaAttributes(),
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *LeafModulesResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*common.ProviderData).Client
+	r.profiles = req.ProviderData.(*common.ProviderData).Profiles
+}
+
+func (r *LeafModulesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data LeafModulesResourceData
+
+	diags := req.Config.Get(ctx, &data)
+
+	tflog.Debug(ctx, "LeafModulesResource: Create - ", map[string]interface{}{"LeafModulesResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.create(&data, ctx, &resp.Diagnostics)
+	if data.Id.IsNull() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *LeafModulesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data LeafModulesResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "LeafModulesResource: Read - ", map[string]interface{}{"LeafModulesResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.read(&data, ctx, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *LeafModulesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan LeafModulesResourceData
+	var state LeafModulesResourceData
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Debug(ctx, "LeafModulesResource: Update", map[string]interface{}{"plan": plan, "state": state})
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = state.Id
+	r.update(&plan, &state, ctx, &resp.Diagnostics)
+
+	// the leaves which are changed before an error are kept in the state
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *LeafModulesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data LeafModulesResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "LeafModulesResource: Delete", map[string]interface{}{"LeafModulesResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+
+	for _, key := range sortedLeafKeys(data.LeafModules) {
+		leaf := data.LeafModules[key]
+		r.deleteLeaf(&data, key, &leaf, ctx, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+// ModifyPlan validates the profile references and plans new leaf ids for the leaves whose selector changes.
+func (r *LeafModulesResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan LeafModulesResourceData
+	if diags := req.Plan.Get(ctx, &plan); diags.HasError() {
+		return
+	}
+
+	if !plan.Profile.IsNull() && !plan.Profile.IsUnknown() {
+		if _, err := r.profiles.FindModuleConfigProfile(plan.Profile.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("profile"), "LeafModulesResource: Invalid profile", err.Error())
+		}
+	}
+	for key, leaf := range plan.LeafModules {
+		if leaf.Profile.IsNull() || leaf.Profile.IsUnknown() {
+			continue
+		}
+		if _, err := r.profiles.FindModuleConfigProfile(leaf.Profile.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("leaf_modules").AtMapKey(key).AtName("profile"), "LeafModulesResource: Invalid profile", err.Error())
+		}
+	}
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+	var state LeafModulesResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for key, leaf := range plan.LeafModules {
+		stateLeaf, ok := state.LeafModules[key]
+		if !ok || !leafSelectorChanged(&leaf, &stateLeaf) {
+			continue
+		}
+		// the leaf is removed and added again
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("leaf_modules").AtMapKey(key).AtName("id"), types.StringUnknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("leaf_modules").AtMapKey(key).AtName("href"), types.StringUnknown())...)
+	}
+}
+
+func (r *LeafModulesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Retrieve import ID and save to id attribute
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *LeafModulesResource) create(plan *LeafModulesResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	tflog.Debug(ctx, "LeafModulesResource: create ## ", map[string]interface{}{"plan": plan})
+
+	if plan.NetworkId.IsNull() {
+		diags.AddError(
+			"Error Create LeafModulesResource",
+			"Create: Could not create leaf modules, network_id is not specified",
+		)
+		return
+	}
+
+	planLeaves := plan.LeafModules
+	plan.Id = plan.NetworkId
+	plan.LeafModules = make(map[string]ConstellationLeafData)
+	r.createLeaves(plan, planLeaves, sortedLeafKeys(planLeaves), ctx, diags)
+	if diags.HasError() {
+		return
+	}
+	if planLeaves == nil && len(plan.LeafModules) == 0 {
+		plan.LeafModules = nil
+	}
+
+	r.read(plan, ctx, diags)
+
+	tflog.Debug(ctx, "LeafModulesResource: create ##", map[string]interface{}{"plan": plan})
+}
+
+// update removes the leaves which are not planned anymore or whose selector changes, adds the new leaves by one request
+// and updates the leaves whose settings change. The leaves which are not changed are not sent to IPM.
+func (r *LeafModulesResource) update(plan *LeafModulesResourceData, state *LeafModulesResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	if plan.Id.IsNull() {
+		diags.AddError(
+			"Error Update LeafModulesResource",
+			"Update: Could not Update. LeafModulesResource ID is not specified",
+		)
+		return
+	}
+
+	// keep the state of the leaves until they are changed
+	planLeaves := plan.LeafModules
+	plan.LeafModules = make(map[string]ConstellationLeafData)
+	for key, leaf := range state.LeafModules {
+		plan.LeafModules[key] = leaf
+	}
+
+	for _, key := range sortedLeafKeys(state.LeafModules) {
+		stateLeaf := state.LeafModules[key]
+		planLeaf, ok := planLeaves[key]
+		if ok && !leafSelectorChanged(&planLeaf, &stateLeaf) {
+			continue
+		}
+		r.deleteLeaf(plan, key, &stateLeaf, ctx, diags)
+		if diags.HasError() {
+			return
+		}
+		delete(plan.LeafModules, key)
+	}
+
+	added := []string{}
+	for _, key := range sortedLeafKeys(planLeaves) {
+		leaf := planLeaves[key]
+		stateLeaf, ok := plan.LeafModules[key]
+		if !ok {
+			added = append(added, key)
+			continue
+		}
+		if reflect.DeepEqual(leaf.Config, stateLeaf.Config) && leaf.Profile.Equal(stateLeaf.Profile) && plan.Profile.Equal(state.Profile) {
+			continue
+		}
+		profile, err := r.leafModulesProfile(plan, &leaf)
+		if err != nil {
+			diags.AddError(
+				"LeafModulesResource: Error Update Leaf Module",
+				"Update: Could not update leaf module "+key+", "+err.Error(),
+			)
+			return
+		}
+		module := ModuleResourceData{NetworkId: plan.NetworkId, Id: stateLeaf.Id, Href: stateLeaf.Href, Config: leaf.Config}
+		leafResource := LeafModuleResource{client: r.client}
+		leafResource.update(&module, ctx, diags, profile)
+		if diags.HasError() {
+			return
+		}
+		leaf.setModuleData(&module)
+		plan.LeafModules[key] = leaf
+	}
+
+	r.createLeaves(plan, planLeaves, added, ctx, diags)
+	if diags.HasError() {
+		return
+	}
+	if planLeaves == nil && len(plan.LeafModules) == 0 {
+		plan.LeafModules = nil
+	}
+
+	r.read(plan, ctx, diags)
+
+	tflog.Debug(ctx, "LeafModulesResource: update ## ", map[string]interface{}{"plan": plan})
+}
+
+// createLeaves adds the planned leaves of the keys by one request. The created leaves are added to the leaves of the plan.
+func (r *LeafModulesResource) createLeaves(plan *LeafModulesResourceData, planLeaves map[string]ConstellationLeafData, keys []string, ctx context.Context, diags *diag.Diagnostics) {
+	if len(keys) == 0 {
+		return
+	}
+
+	var request []map[string]interface{}
+	for _, key := range keys {
+		leaf := planLeaves[key]
+		profile, err := r.leafModulesProfile(plan, &leaf)
+		if err != nil {
+			diags.AddError(
+				"LeafModulesResource: Error Create Leaf Module",
+				"Create: Could not create leaf module "+key+", "+err.Error(),
+			)
+			return
+		}
+		createRequest := leafModuleCreateRequest(leaf.Config, diags, profile)
+		if createRequest == nil {
+			return
+		}
+		request = append(request, createRequest)
+	}
+	tflog.Debug(ctx, "LeafModulesResource: create leaves ## ", map[string]interface{}{"leaves": keys, "Create Request": request})
+
+	rb, err := json.Marshal(request)
+	if err != nil {
+		diags.AddError(
+			"LeafModulesResource: create ##: Error Create Leaf Modules",
+			"Create: Could not Marshal the leaf modules, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	body, err := r.client.ExecuteIPMHttpCommand("POST", "/xr-networks/"+plan.NetworkId.ValueString()+"/leafModules", rb)
+	if err != nil {
+		diags.AddError(
+			"LeafModulesResource: create ##: Error Create Leaf Modules",
+			"Create:Could not create leaf modules "+strings.Join(keys, ", ")+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+	tflog.Debug(ctx, "LeafModulesResource: create ## ExecuteIPMHttpCommand ..", map[string]interface{}{"response": string(body)})
+	var data []interface{}
+	err = json.Unmarshal(body, &data)
+	if err != nil {
+		diags.AddError(
+			"LeafModulesResource: create ##: Error Unmarshal response",
+			"Create:Could not create leaf modules, unexpected response for "+strconv.Itoa(len(keys))+" leaf modules: "+string(body),
+		)
+		return
+	}
+
+	// IPM returns the created leaves in the order of the request, the leaves it returned are kept before a mismatch is reported
+	hrefs, missing := createdLeafHrefs(keys, data)
+	ids := []string{}
+	for _, key := range keys {
+		href, ok := hrefs[key]
+		if !ok {
+			continue
+		}
+		leaf := planLeaves[key]
+		splits := strings.Split(href, "/")
+		leaf.Href = types.StringValue(href)
+		leaf.Id = types.StringValue(splits[len(splits)-1])
+		leaf.LifecycleState = types.StringNull()
+		leaf.State = types.ObjectNull(NWModuleStateAttributeType())
+		plan.LeafModules[key] = leaf
+		ids = append(ids, leaf.Id.ValueString())
+	}
+	if len(missing) > 0 || len(data) != len(keys) {
+		diags.AddError(
+			"LeafModulesResource: create ##: Error Unmarshal response",
+			"Create:Could not create leaf modules "+strings.Join(missing, ", ")+", unexpected response for "+strconv.Itoa(len(keys))+" leaf modules: "+string(body),
+		)
+		return
+	}
+
+	r.waitForLeaves(plan, ids, ctx, diags)
+}
+
+// createdLeafHrefs maps the leaves of the batch response to the keys of the request by their index. The keys without
+// href in the response are returned as missing.
+func createdLeafHrefs(keys []string, data []interface{}) (map[string]string, []string) {
+	hrefs := make(map[string]string)
+	missing := []string{}
+	for i, key := range keys {
+		var href string
+		if i < len(data) {
+			result, _ := data[i].(map[string]interface{})
+			href, _ = result["href"].(string)
+		}
+		if href == "" {
+			missing = append(missing, key)
+			continue
+		}
+		hrefs[key] = href
+	}
+	return hrefs, missing
+}
+
+// waitForLeaves repeats the request of the leaf modules of the network until the leaves just created are listed.
+func (r *LeafModulesResource) waitForLeaves(plan *LeafModulesResourceData, ids []string, ctx context.Context, diags *diag.Diagnostics) {
+	for i := 1; i <= 10; i++ {
+		data, err := common.GetResources(r.client, "/xr-networks/"+plan.NetworkId.ValueString()+"/leafModules")
+		if err != nil {
+			diags.AddError(
+				"LeafModulesResource: create ##: Error Get Leaf Modules",
+				"Create:Could not get leaf modules, unexpected error: "+err.Error(),
+			)
+			return
+		}
+		listed := make(map[string]bool)
+		for _, v := range data {
+			if leafModule, ok := v.(map[string]interface{}); ok {
+				if id, ok := leafModule["id"].(string); ok {
+					listed[id] = true
+				}
+			}
+		}
+		pending := []string{}
+		for _, id := range ids {
+			if !listed[id] {
+				pending = append(pending, id)
+			}
+		}
+		if len(pending) == 0 {
+			return
+		}
+		tflog.Debug(ctx, "LeafModulesResource: create ## leaf modules pending", map[string]interface{}{"ids": pending})
+		ids = pending
+		time.Sleep(2 * time.Second)
+	}
+	diags.AddError(
+		"LeafModulesResource: create ##: Error Get Leaf Modules",
+		"Create:The leaf modules "+strings.Join(ids, ", ")+" are created but not listed by the network",
+	)
+}
+
+// read populates the leaves from one request of the leaf modules of the network. The leaves which are not found are
+// removed from the state, and added again at the next apply.
+func (r *LeafModulesResource) read(state *LeafModulesResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	if state.Id.IsNull() {
+		diags.AddError(
+			"LeafModulesResource: Error read LeafModulesResource",
+			"Read: Could not read. LeafModulesResource ID is not specified",
+		)
+		return
+	}
+	// an imported resource has the network id as id, and the leaves are named by their module name
+	imported := state.NetworkId.IsNull()
+	if imported {
+		state.NetworkId = state.Id
+	}
+
+	data, err := common.GetResources(r.client, "/xr-networks/"+state.NetworkId.ValueString()+"/leafModules?content=expanded")
+	if err != nil {
+		diags.AddError(
+			"LeafModulesResource: read ##: Error Get Leaf Modules",
+			"Read:Could not get leaf modules, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	leafModules := make(map[string]map[string]interface{})
+	for _, v := range data {
+		leafModule, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if id, ok := leafModule["id"].(string); ok {
+			leafModules[id] = leafModule
+		}
+	}
+
+	if imported {
+		state.LeafModules = make(map[string]ConstellationLeafData)
+		for id, leafModule := range leafModules {
+			key := leafModuleName(leafModule)
+			if key == "" {
+				key = id
+			}
+			leaf := ConstellationLeafData{Id: types.StringValue(id), Profile: types.StringNull()}
+			leaf.populate(leafModule, ctx, diags, true)
+			state.LeafModules[key] = leaf
+		}
+		return
+	}
+
+	for key, leaf := range state.LeafModules {
+		leafModule, ok := leafModules[leaf.Id.ValueString()]
+		if !ok {
+			// the leaf is removed outside of terraform, it is added again at the next apply
+			tflog.Debug(ctx, "LeafModulesResource: read ## leaf module not found", map[string]interface{}{"leaf": key, "id": leaf.Id.ValueString()})
+			delete(state.LeafModules, key)
+			continue
+		}
+		leaf.populate(leafModule, ctx, diags)
+		state.LeafModules[key] = leaf
+	}
+
+	tflog.Debug(ctx, "LeafModulesResource: read SUCCESS ", map[string]interface{}{"state": state})
+}
+
+func (r *LeafModulesResource) deleteLeaf(state *LeafModulesResourceData, key string, leaf *ConstellationLeafData, ctx context.Context, diags *diag.Diagnostics) {
+
+	tflog.Debug(ctx, "LeafModulesResource: delete leaf ## ", map[string]interface{}{"leaf": key, "id": leaf.Id.ValueString()})
+
+	if leaf.Id.IsNull() || leaf.Id.IsUnknown() {
+		return
+	}
+	_, err := r.client.ExecuteIPMHttpCommand("DELETE", "/xr-networks/"+state.NetworkId.ValueString()+"/leafModules/"+leaf.Id.ValueString(), nil)
+	if err != nil && !strings.Contains(err.Error(), "status: 404") {
+		diags.AddError(
+			"LeafModulesResource: delete ##: Error Delete Leaf Module",
+			"Delete:Could not delete leaf module "+key+", unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// leafModulesProfile returns the module config profile of the leaf, or the profile of the leaf modules.
+func (r *LeafModulesResource) leafModulesProfile(plan *LeafModulesResourceData, leaf *ConstellationLeafData) (common.ModuleConfigProfile, error) {
+	if !leaf.Profile.IsNull() {
+		return r.profiles.FindModuleConfigProfile(leaf.Profile.ValueString())
+	}
+	if plan.Profile.IsNull() {
+		return common.ModuleConfigProfile{}, nil
+	}
+	return r.profiles.FindModuleConfigProfile(plan.Profile.ValueString())
+}
+
+func LeafModulesSchemaAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Description: "Numeric identifier of the Network.",
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"network_id": schema.StringAttribute{
+			Description: "Numeric identifier of the Constellation Network.",
+			Required:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"profile": schema.StringAttribute{
+			Description: "Name of the module config profile of the provider profiles for the leaves without profile.",
+			Optional:    true,
+		},
+		"leaf_modules": schema.MapNestedAttribute{
+			Description: "leaf modules by name, with their selector and module settings",
+			Optional:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: ConstellationLeafSchemaAttributes("Defaults to the profile of the leaf modules."),
+			},
+		},
+	}
+}
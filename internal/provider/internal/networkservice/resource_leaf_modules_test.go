@@ -0,0 +1,36 @@
+package network
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCreatedLeafHrefs(t *testing.T) {
+	leaf := func(href string) interface{} { return map[string]interface{}{"href": href} }
+	tests := []struct {
+		name    string
+		keys    []string
+		data    []interface{}
+		hrefs   map[string]string
+		missing []string
+	}{
+		{"by index", []string{"a", "b"}, []interface{}{leaf("/xr-networks/n1/leafModules/1"), leaf("/xr-networks/n1/leafModules/2")},
+			map[string]string{"a": "/xr-networks/n1/leafModules/1", "b": "/xr-networks/n1/leafModules/2"}, []string{}},
+		{"short response", []string{"a", "b"}, []interface{}{leaf("/xr-networks/n1/leafModules/1")},
+			map[string]string{"a": "/xr-networks/n1/leafModules/1"}, []string{"b"}},
+		{"no href", []string{"a", "b"}, []interface{}{map[string]interface{}{}, leaf("/xr-networks/n1/leafModules/2")},
+			map[string]string{"b": "/xr-networks/n1/leafModules/2"}, []string{"a"}},
+		{"not an object", []string{"a"}, []interface{}{"/xr-networks/n1/leafModules/1"},
+			map[string]string{}, []string{"a"}},
+		{"empty response", []string{"a"}, nil, map[string]string{}, []string{"a"}},
+	}
+	for _, test := range tests {
+		hrefs, missing := createdLeafHrefs(test.keys, test.data)
+		if !reflect.DeepEqual(hrefs, test.hrefs) {
+			t.Errorf("%s: createdLeafHrefs() hrefs = %v, want %v", test.name, hrefs, test.hrefs)
+		}
+		if !reflect.DeepEqual(missing, test.missing) {
+			t.Errorf("%s: createdLeafHrefs() missing = %v, want %v", test.name, missing, test.missing)
+		}
+	}
+}
@@ -26,6 +26,7 @@ var (
 	_ resource.Resource                = &NetworkResource{}
 	_ resource.ResourceWithConfigure   = &NetworkResource{}
 	_ resource.ResourceWithImportState = &NetworkResource{}
+	_ resource.ResourceWithModifyPlan  = &NetworkResource{}
 )
 
 // NewNetworkResource is a helper function to simplify the provider implementation.
@@ -34,7 +35,8 @@ func NewNetworkResource() resource.Resource {
 }
 
 type NetworkResource struct {
-	client *ipm_pf.Client
+	client   *ipm_pf.Client
+	profiles *common.Profiles
 }
 
 type NetworkConfig struct {
@@ -49,6 +51,7 @@ type NetworkConfig struct {
 type NetworkResourceData struct {
 	Id               types.String                  `tfsdk:"id"`
 	Href             types.String                  `tfsdk:"href"`
+	Profile          types.String                  `tfsdk:"profile"`
 	Config           *NetworkConfig                 `tfsdk:"config"`
 	State            types.Object                  `tfsdk:"state"`
 	HubModule        *ModuleResourceData            `tfsdk:"hub_module"`
@@ -76,7 +79,8 @@ func (r *NetworkResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
+	r.profiles = req.ProviderData.(*common.ProviderData).Profiles
 }
 
 func (r *NetworkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -153,6 +157,21 @@ func (r NetworkResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	resp.State.RemoveResource(ctx)
 }
 
+// ModifyPlan validates the profile reference at plan time.
+func (r *NetworkResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var profile types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("profile"), &profile)...)
+	if profile.IsNull() || profile.IsUnknown() {
+		return
+	}
+	if _, _, _, err := r.profiles.FindNetworkProfile(profile.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("profile"), "NetworkResource: Invalid profile", err.Error())
+	}
+}
+
 func (r *NetworkResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// Retrieve import ID and save to id attribute
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
@@ -162,7 +181,21 @@ func (r *NetworkResource) create(plan *NetworkResourceData, ctx context.Context,
 
 	tflog.Debug(ctx, "NetworkResource: create ## ", map[string]interface{}{"plan": plan})
 
-	if plan.Config.ConstellationFrequency.IsNull() {
+	var networkProfile common.NetworkConfigProfile
+	var hubProfile common.ModuleConfigProfile
+	if !plan.Profile.IsNull() {
+		var err error
+		_, networkProfile, hubProfile, err = r.profiles.FindNetworkProfile(plan.Profile.ValueString())
+		if err != nil {
+			diags.AddError(
+				"Error Create NetworkResource",
+				"Create: Could not create NetworkResource, "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if plan.Config.ConstellationFrequency.IsNull() && networkProfile.ConstellationFrequency.IsNull() {
 		diags.AddError(
 			"Error Create NetworkResource",
 			"Create: Could not create NetworkResource, ConstellationFrequency is not specified",
@@ -189,51 +222,21 @@ func (r *NetworkResource) create(plan *NetworkResourceData, ctx context.Context,
 	if !plan.Config.TcMode.IsNull() {
 		configRequest["tcMode"] = plan.Config.TcMode.ValueBool()
 	}
+	common.MergeProfile(configRequest, networkProfile.Request())
 	createRequest["config"] = configRequest
 	tflog.Debug(ctx, "NetworkResource: create ## ", map[string]interface{}{"configRequest": configRequest})
 
 	// get hubModule setting
 	var hubModuleRequest = make(map[string]interface{})
-	var selector = make(map[string]interface{})
-	aSelector := make(map[string]interface{})
-	if plan.HubModule.Config.Selector.ModuleSelectorByModuleId != nil {
-		aSelector["moduleId"] = plan.HubModule.Config.Selector.ModuleSelectorByModuleId.ModuleId.ValueString()
-		selector["moduleSelectorByModuleId"] = aSelector
-	} else if plan.HubModule.Config.Selector.ModuleSelectorByModuleName != nil {
-		aSelector["moduleName"] = plan.HubModule.Config.Selector.ModuleSelectorByModuleName.ModuleName.ValueString()
-		selector["moduleSelectorByModuleName"] = aSelector
-	} else if plan.HubModule.Config.Selector.ModuleSelectorByModuleMAC != nil {
-		aSelector["moduleMAC"] = plan.HubModule.Config.Selector.ModuleSelectorByModuleMAC.ModuleMAC.ValueString()
-		selector["moduleSelectorByModuleMAC"] = aSelector
-	} else if plan.HubModule.Config.Selector.ModuleSelectorByModuleSerialNumber != nil {
-		aSelector["moduleSerialNumber"] = plan.HubModule.Config.Selector.ModuleSelectorByModuleSerialNumber.ModuleSerialNumber.ValueString()
-		selector["moduleSelectorByModuleSerialNumber"] = aSelector
-	} else if plan.HubModule.Config.Selector.HostPortSelectorByName != nil {
-		aSelector["hostName"] = plan.HubModule.Config.Selector.HostPortSelectorByName.HostName.ValueString()
-		aSelector["hostPortName"] = plan.HubModule.Config.Selector.HostPortSelectorByName.HostPortName.ValueString()
-		selector["hostPortSelectorByName"] = aSelector
-	} else if plan.HubModule.Config.Selector.HostPortSelectorByPortId != nil {
-		aSelector["chassisId"] = plan.HubModule.Config.Selector.HostPortSelectorByPortId.ChassisId.ValueString()
-		aSelector["chassisIdSubtype"] = plan.HubModule.Config.Selector.HostPortSelectorByPortId.ChassisIdSubtype.ValueString()
-		aSelector["portId"] = plan.HubModule.Config.Selector.HostPortSelectorByPortId.PortId.ValueString()
-		aSelector["portIdSubtype"] = plan.HubModule.Config.Selector.HostPortSelectorByPortId.PortIdSubtype.ValueString()
-		selector["hostPortSelectorByPortId"] = aSelector
-	} else if plan.HubModule.Config.Selector.HostPortSelectorBySysName != nil {
-		aSelector["sysName"] = plan.HubModule.Config.Selector.HostPortSelectorBySysName.SysName.ValueString()
-		aSelector["portId"] = plan.HubModule.Config.Selector.HostPortSelectorByPortId.PortId.ValueString()
-		aSelector["portIdSubtype"] = plan.HubModule.Config.Selector.HostPortSelectorByPortId.PortIdSubtype.ValueString()
-		selector["hostPortSelectorBySysName"] = aSelector
-	} else if plan.HubModule.Config.Selector.HostPortSelectorByPortSourceMAC != nil {
-		aSelector["portSourceMAC"] = plan.HubModule.Config.Selector.HostPortSelectorByPortSourceMAC.PortSourceMAC.ValueString()
-		selector["hostPortSelectorByPortSourceMAC"] = aSelector
-	} else {
+	variant, values := plan.HubModule.Config.Selector.Values()
+	if variant == "" {
 		diags.AddError(
 			"Error Create NetworkResource",
 			"Create: Could not create NetworkResource, No hub module selector specified",
 		)
 		return
 	}
-	hubModuleRequest["selector"] = selector
+	hubModuleRequest["selector"] = common.SelectorRequest(variant, values)
 	var module = make(map[string]interface{})
 	if !plan.HubModule.Config.Module.TrafficMode.IsNull() {
 		module["trafficMode"] = plan.HubModule.Config.Module.TrafficMode.ValueString()
@@ -253,6 +256,7 @@ func (r *NetworkResource) create(plan *NetworkResourceData, ctx context.Context,
 	if !plan.HubModule.Config.Module.PlannedCapacity.IsNull() {
 		module["plannedCapacity"] = plan.HubModule.Config.Module.PlannedCapacity.ValueString()
 	}
+	mergeModuleProfile(module, hubProfile)
 	hubModuleRequest["module"] = module
 	createRequest["hubModule"] = hubModuleRequest
 
@@ -336,6 +340,20 @@ func (r *NetworkResource) update(plan *NetworkResourceData, ctx context.Context,
 	if !plan.Config.ManagedBy.IsNull() {
 		updateRequest["managedBy"] = plan.Config.ManagedBy.ValueString()
 	}
+	if !plan.Profile.IsNull() {
+		_, networkProfile, _, err := r.profiles.FindNetworkProfile(plan.Profile.ValueString())
+		if err != nil {
+			diags.AddError(
+				"Error Update NetworkResource",
+				"Update: Could not Update NetworkResource, "+err.Error(),
+			)
+			return
+		}
+		profileRequest := networkProfile.Request()
+		// the topology is set at creation only
+		delete(profileRequest, "topology")
+		common.MergeProfile(updateRequest, profileRequest)
+	}
 
 	tflog.Debug(ctx, "NetworkResource: update ## ", map[string]interface{}{"id": plan.Id.ValueString(),"Update Request": updateRequest})
 
@@ -588,6 +606,10 @@ func NetworkSchemaAttributes() map[string]schema.Attribute {
 				stringplanmodifier.UseStateForUnknown(),
 			},
 		},
+		"profile": schema.StringAttribute{
+			Description: "Name of a network profile of the provider profiles. The config and hub module settings which are not specified are taken from the profile.",
+			Optional:    true,
+		},
 		//Config           NetworkConfig `tfsdk:"config"`
 		"config": schema.SingleNestedAttribute{
 			Description: "config",
@@ -63,7 +63,7 @@ func (r *ReachableModuleResource) Configure(_ context.Context, req resource.Conf
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r ReachableModuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
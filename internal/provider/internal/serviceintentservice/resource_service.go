@@ -0,0 +1,694 @@
+package serviceintent
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	common "terraform-provider-ipm/internal/provider/internal/common"
+	networkconnection "terraform-provider-ipm/internal/provider/internal/networkconnectionservice"
+	transportcapacity "terraform-provider-ipm/internal/provider/internal/transportcapacityservice"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &ServiceResource{}
+	_ resource.ResourceWithConfigure      = &ServiceResource{}
+	_ resource.ResourceWithValidateConfig = &ServiceResource{}
+	_ resource.ResourceWithModifyPlan     = &ServiceResource{}
+)
+
+const (
+	defaultServiceTimeout = 600
+	servicePollInterval   = 15 * time.Second
+)
+
+// NewServiceResource is a helper function to simplify the provider implementation.
+func NewServiceResource() resource.Resource {
+	return &ServiceResource{}
+}
+
+// ServiceResource manages a point to multipoint service: a TC between the hub and each leaf client interface,
+// and the NC between the hub and all the leaf client interfaces.
+type ServiceResource struct {
+	client *ipm_pf.Client
+}
+
+type ServiceLeafData struct {
+	Selector            common.IfSelector `tfsdk:"selector"`
+	Capacity            types.Int64       `tfsdk:"capacity"`
+	TransportCapacityId types.String      `tfsdk:"transport_capacity_id"`
+	LifecycleState      types.String      `tfsdk:"lifecycle_state"`
+}
+
+type ServiceResourceData struct {
+	Id             types.String               `tfsdk:"id"`
+	Name           types.String               `tfsdk:"name"`
+	ServiceMode    types.String               `tfsdk:"service_mode"`
+	CapacityMode   types.String               `tfsdk:"capacity_mode"`
+	Labels         types.Map                  `tfsdk:"labels"`
+	Capacity       types.Int64                `tfsdk:"capacity"`
+	Timeout        types.Int64                `tfsdk:"timeout"`
+	Hub            common.IfSelector          `tfsdk:"hub"`
+	Leaves         map[string]ServiceLeafData `tfsdk:"leaves"`
+	LifecycleState types.String               `tfsdk:"lifecycle_state"`
+}
+
+// Metadata returns the data source type name.
+func (r *ServiceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service"
+}
+
+// Schema defines the schema for the data source.
+func (r *ServiceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a point to multipoint service: the TC between the hub and each leaf, then the NC between the hub and the leaves. " +
+			"Adding or removing a leaf, changing the selector, capacity or capacity mode of a leaf, or changing the hub deletes the NC and creates it again, " +
+			"which interrupts the traffic of all the leaves.",
+		Attributes: ServiceSchemaAttributes(),
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *ServiceResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*common.ProviderData).Client
+}
+
+// ValidateConfig checks that the service has leaves.
+func (r *ServiceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var leaves types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("leaves"), &leaves)...)
+	if resp.Diagnostics.HasError() || leaves.IsUnknown() {
+		return
+	}
+	if len(leaves.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("leaves"), "ServiceResource: Invalid leaves", "At least one leaf must be specified.")
+	}
+}
+
+// ModifyPlan warns when the update deletes the NC and creates it again, its id and lifecycle state are then unknown, as
+// are the TC ids of the changed leaves and the lifecycle states of the TCs which are created or updated.
+func (r *ServiceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+	var plan ServiceResourceData
+	var state ServiceResourceData
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	changedLeaves, addedLeaves := plan.endpointChanges(&state)
+	if len(changedLeaves) == 0 && len(addedLeaves) == 0 && !state.Id.IsNull() {
+		if plan.networkConnectionChanged(&state) {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("lifecycle_state"), types.StringUnknown())...)
+		}
+		if plan.transportCapacitiesChanged(&state) {
+			setLeavesUnknown(ctx, sortedLeafKeys(plan.Leaves), "lifecycle_state", resp)
+		}
+		return
+	}
+	setLeavesUnknown(ctx, sortedLeafKeys(plan.Leaves), "lifecycle_state", resp)
+	for _, key := range changedLeaves {
+		if _, ok := plan.Leaves[key]; ok {
+			setLeavesUnknown(ctx, []string{key}, "transport_capacity_id", resp)
+		}
+	}
+	resp.Diagnostics.AddWarning(
+		"ServiceResource: Network Connection replaced",
+		"The NC "+state.Id.ValueString()+" of the service is deleted and created again, the traffic of all the leaves is interrupted. "+
+			"Changed leaves: "+strings.Join(changedLeaves, ", ")+". Added leaves: "+strings.Join(addedLeaves, ", ")+".",
+	)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("lifecycle_state"), types.StringUnknown())...)
+}
+
+func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ServiceResourceData
+
+	diags := req.Config.Get(ctx, &data)
+
+	tflog.Debug(ctx, "ServiceResource: Create - ", map[string]interface{}{"ServiceResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.create(&data, ctx, &resp.Diagnostics)
+
+	// keep the created TCs in the state, so that they are deleted with the service
+	if data.Id.IsNull() && !data.hasTransportCapacity() {
+		return
+	}
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ServiceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ServiceResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "ServiceResource: Read - ", map[string]interface{}{"ServiceResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.read(&data, ctx, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ServiceResourceData
+	var state ServiceResourceData
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Debug(ctx, "ServiceResource: Update", map[string]interface{}{"plan": plan, "state": state})
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.update(&plan, &state, ctx, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ServiceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ServiceResourceData
+
+	diags := req.State.Get(ctx, &data)
+
+	tflog.Debug(ctx, "ServiceResource: Delete", map[string]interface{}{"ServiceResourceData": data})
+
+	resp.Diagnostics.Append(diags...)
+
+	r.delete(&data, ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *ServiceResource) create(plan *ServiceResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	tflog.Debug(ctx, "ServiceResource: create ## ", map[string]interface{}{"plan": plan})
+
+	plan.Id = types.StringNull()
+	plan.LifecycleState = types.StringNull()
+	for key, leaf := range plan.Leaves {
+		leaf.TransportCapacityId = types.StringNull()
+		leaf.LifecycleState = types.StringNull()
+		plan.Leaves[key] = leaf
+	}
+
+	// the TCs first, the NC needs them
+	for _, key := range sortedLeafKeys(plan.Leaves) {
+		r.createTransportCapacity(plan, key, ctx, diags)
+		if diags.HasError() {
+			return
+		}
+	}
+	r.waitTransportCapacities(plan, ctx, diags)
+	if diags.HasError() {
+		return
+	}
+
+	r.createNetworkConnection(plan, ctx, diags)
+
+	tflog.Debug(ctx, "ServiceResource: create ##", map[string]interface{}{"plan": plan})
+}
+
+func (r *ServiceResource) update(plan *ServiceResourceData, state *ServiceResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	tflog.Debug(ctx, "ServiceResource: update ## ", map[string]interface{}{"plan": plan})
+
+	changedLeaves, addedLeaves := plan.endpointChanges(state)
+
+	// the planned leaves keep the TCs of the state until they are changed, the removed leaves are kept until their TC is deleted
+	planLeaves := plan.Leaves
+	plan.Id = state.Id
+	plan.LifecycleState = state.LifecycleState
+	plan.Leaves = make(map[string]ServiceLeafData)
+	for key, leaf := range planLeaves {
+		leaf.TransportCapacityId = types.StringNull()
+		leaf.LifecycleState = types.StringNull()
+		if stateLeaf, ok := state.Leaves[key]; ok {
+			leaf.TransportCapacityId = stateLeaf.TransportCapacityId
+			leaf.LifecycleState = stateLeaf.LifecycleState
+		}
+		plan.Leaves[key] = leaf
+	}
+	for key, stateLeaf := range state.Leaves {
+		if _, ok := planLeaves[key]; !ok {
+			plan.Leaves[key] = stateLeaf
+		}
+	}
+
+	// the endpoints change: the NC is deleted before its TCs, and created again after them
+	if len(changedLeaves) > 0 || len(addedLeaves) > 0 || plan.Id.IsNull() {
+		if !plan.Id.IsNull() {
+			nc := networkconnection.NetworkConnectionResourceData{Id: plan.Id}
+			networkconnection.DeleteNetworkConnection(r.client, &nc, ctx, diags)
+			if diags.HasError() {
+				return
+			}
+			plan.Id = types.StringNull()
+			plan.LifecycleState = types.StringNull()
+		}
+		for _, key := range changedLeaves {
+			r.deleteTransportCapacity(plan, key, ctx, diags)
+			if diags.HasError() {
+				return
+			}
+			if _, ok := planLeaves[key]; !ok {
+				delete(plan.Leaves, key)
+				continue
+			}
+			leaf := plan.Leaves[key]
+			leaf.TransportCapacityId = types.StringNull()
+			leaf.LifecycleState = types.StringNull()
+			plan.Leaves[key] = leaf
+		}
+		for _, key := range sortedLeafKeys(plan.Leaves) {
+			if !plan.Leaves[key].TransportCapacityId.IsNull() {
+				continue
+			}
+			r.createTransportCapacity(plan, key, ctx, diags)
+			if diags.HasError() {
+				return
+			}
+		}
+		r.updateTransportCapacities(plan, state, ctx, diags)
+		if diags.HasError() {
+			return
+		}
+		r.waitTransportCapacities(plan, ctx, diags)
+		if diags.HasError() {
+			return
+		}
+		r.createNetworkConnection(plan, ctx, diags)
+		return
+	}
+
+	// only the names, labels or service mode change
+	r.updateTransportCapacities(plan, state, ctx, diags)
+	if diags.HasError() {
+		return
+	}
+	ncChanged := plan.networkConnectionChanged(state)
+	if ncChanged {
+		nc := plan.networkConnectionData()
+		nc.Id = plan.Id
+		networkconnection.UpdateNetworkConnection(r.client, &nc, ctx, diags)
+		if diags.HasError() {
+			return
+		}
+	}
+	r.read(plan, ctx, diags)
+	if !ncChanged {
+		// the plan keeps the lifecycle state of an unchanged NC, the next read refreshes it
+		plan.LifecycleState = state.LifecycleState
+	}
+	if !plan.transportCapacitiesChanged(state) {
+		for key, leaf := range plan.Leaves {
+			leaf.LifecycleState = state.Leaves[key].LifecycleState
+			plan.Leaves[key] = leaf
+		}
+	}
+
+	tflog.Debug(ctx, "ServiceResource: update ## ", map[string]interface{}{"plan": plan})
+}
+
+func (r *ServiceResource) read(state *ServiceResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	for _, key := range sortedLeafKeys(state.Leaves) {
+		leaf := state.Leaves[key]
+		if leaf.TransportCapacityId.IsNull() {
+			continue
+		}
+		tc := transportcapacity.TransportCapacityResourceData{Id: leaf.TransportCapacityId}
+		transportcapacity.ReadTransportCapacity(r.client, &tc, ctx, diags)
+		if diags.HasError() {
+			return
+		}
+		leaf.LifecycleState = stateValue(tc.State, "life_cycle_state")
+		state.Leaves[key] = leaf
+	}
+
+	if state.Id.IsNull() {
+		return
+	}
+	nc := networkconnection.NetworkConnectionResourceData{Id: state.Id}
+	networkconnection.ReadNetworkConnection(r.client, &nc, ctx, diags)
+	if diags.HasError() {
+		return
+	}
+	state.LifecycleState = stateValue(nc.State, "lifecycle_state")
+
+	tflog.Debug(ctx, "ServiceResource: read SUCCESS ", map[string]interface{}{"state": state})
+}
+
+func (r *ServiceResource) delete(state *ServiceResourceData, ctx context.Context, diags *diag.Diagnostics) {
+
+	// the NC before its TCs
+	if !state.Id.IsNull() {
+		nc := networkconnection.NetworkConnectionResourceData{Id: state.Id}
+		networkconnection.DeleteNetworkConnection(r.client, &nc, ctx, diags)
+		if diags.HasError() {
+			return
+		}
+	}
+	for _, key := range sortedLeafKeys(state.Leaves) {
+		r.deleteTransportCapacity(state, key, ctx, diags)
+		if diags.HasError() {
+			return
+		}
+	}
+
+	tflog.Debug(ctx, "ServiceResource: delete ## ", map[string]interface{}{"state": state})
+}
+
+func (r *ServiceResource) createTransportCapacity(plan *ServiceResourceData, key string, ctx context.Context, diags *diag.Diagnostics) {
+	leaf := plan.Leaves[key]
+	capacity := plan.leafCapacity(&leaf)
+
+	tc := transportcapacity.TransportCapacityResourceData{
+		Config: &transportcapacity.TCConfig{
+			Name:         types.StringValue(plan.Name.ValueString() + "-" + key),
+			CapacityMode: plan.CapacityMode,
+			Labels:       plan.labels(),
+		},
+		Endpoints: []transportcapacity.TCEndpointResourceData{
+			{Config: transportcapacity.TCEndpointConfig{Capacity: capacity, Selector: plan.Hub}},
+			{Config: transportcapacity.TCEndpointConfig{Capacity: capacity, Selector: leaf.Selector}},
+		},
+	}
+	tflog.Debug(ctx, "ServiceResource: create TC ## ", map[string]interface{}{"leaf": key})
+	transportcapacity.CreateTransportCapacity(r.client, &tc, ctx, diags)
+	if tc.Id.IsNull() || diags.HasError() {
+		return
+	}
+	leaf.TransportCapacityId = tc.Id
+	leaf.LifecycleState = stateValue(tc.State, "life_cycle_state")
+	plan.Leaves[key] = leaf
+}
+
+// updateTransportCapacities renames the TCs and updates their labels.
+func (r *ServiceResource) updateTransportCapacities(plan *ServiceResourceData, state *ServiceResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	if !plan.transportCapacitiesChanged(state) {
+		return
+	}
+	for _, key := range sortedLeafKeys(plan.Leaves) {
+		leaf := plan.Leaves[key]
+		if _, ok := state.Leaves[key]; !ok || leaf.TransportCapacityId.IsNull() {
+			continue
+		}
+		tc := transportcapacity.TransportCapacityResourceData{Id: leaf.TransportCapacityId}
+		transportcapacity.ReadTransportCapacity(r.client, &tc, ctx, diags, 1)
+		if diags.HasError() {
+			return
+		}
+		tc.Config = &transportcapacity.TCConfig{
+			Name:         types.StringValue(plan.Name.ValueString() + "-" + key),
+			CapacityMode: plan.CapacityMode,
+			Labels:       plan.labels(),
+		}
+		transportcapacity.UpdateTransportCapacity(r.client, &tc, ctx, diags)
+		if diags.HasError() {
+			return
+		}
+	}
+}
+
+func (r *ServiceResource) deleteTransportCapacity(state *ServiceResourceData, key string, ctx context.Context, diags *diag.Diagnostics) {
+	leaf := state.Leaves[key]
+	if leaf.TransportCapacityId.IsNull() {
+		return
+	}
+	tflog.Debug(ctx, "ServiceResource: delete TC ## ", map[string]interface{}{"leaf": key, "id": leaf.TransportCapacityId.ValueString()})
+	tc := transportcapacity.TransportCapacityResourceData{Id: leaf.TransportCapacityId}
+	transportcapacity.DeleteTransportCapacity(r.client, &tc, ctx, diags)
+}
+
+// waitTransportCapacities waits until all the TCs of the service are configured.
+func (r *ServiceResource) waitTransportCapacities(plan *ServiceResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	timeout := int64(defaultServiceTimeout)
+	if !plan.Timeout.IsNull() {
+		timeout = plan.Timeout.ValueInt64()
+	}
+	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+	for _, key := range sortedLeafKeys(plan.Leaves) {
+		leaf := plan.Leaves[key]
+		if leaf.TransportCapacityId.IsNull() {
+			continue
+		}
+		_, err := common.WaitForResourceState(ctx, r.client, "/transport-capacities/"+leaf.TransportCapacityId.ValueString()+"?content=expanded",
+			map[string]string{"state.lifecycleState": "configured"}, time.Until(deadline), servicePollInterval)
+		if err != nil {
+			diags.AddError(
+				"ServiceResource: Error wait TC",
+				"Could not create NC, TC of leaf "+key+" is not configured within "+strconv.FormatInt(timeout, 10)+" seconds: "+err.Error(),
+			)
+			return
+		}
+		leaf.LifecycleState = types.StringValue("configured")
+		plan.Leaves[key] = leaf
+	}
+}
+
+func (r *ServiceResource) createNetworkConnection(plan *ServiceResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	nc := plan.networkConnectionData()
+	networkconnection.CreateNetworkConnection(r.client, &nc, ctx, diags)
+	if nc.Id.IsNull() || diags.HasError() {
+		return
+	}
+	plan.Id = nc.Id
+	plan.LifecycleState = stateValue(nc.State, "lifecycle_state")
+}
+
+// networkConnectionData returns the NC between the hub and all the leaves. The hub endpoint carries the capacity of all the leaves.
+func (sData *ServiceResourceData) networkConnectionData() networkconnection.NetworkConnectionResourceData {
+	hubCapacity := int64(0)
+	leafEndpoints := []networkconnection.NCEndpointResourceData{}
+	for _, key := range sortedLeafKeys(sData.Leaves) {
+		leaf := sData.Leaves[key]
+		capacity := sData.leafCapacity(&leaf)
+		hubCapacity += capacity.ValueInt64()
+		leafEndpoints = append(leafEndpoints, networkconnection.NCEndpointResourceData{
+			Config: networkconnection.NCEndpointConfig{Selector: leaf.Selector, Capacity: capacity},
+		})
+	}
+	endpoints := []networkconnection.NCEndpointResourceData{
+		{Config: networkconnection.NCEndpointConfig{Selector: sData.Hub, Capacity: types.Int64Value(hubCapacity)}},
+	}
+	return networkconnection.NetworkConnectionResourceData{
+		Profile: types.StringNull(),
+		Config: &networkconnection.NetworkConnectionConfig{
+			Name:        sData.Name,
+			ServiceMode: sData.ServiceMode,
+			Labels:      sData.labels(),
+		},
+		Endpoints: append(endpoints, leafEndpoints...),
+	}
+}
+
+// endpointChanges returns the leaves whose TC is deleted or created again, and the added leaves. The NC is replaced when
+// any leaf is returned, a change of the hub changes all the leaves.
+func (sData *ServiceResourceData) endpointChanges(state *ServiceResourceData) ([]string, []string) {
+	changedLeaves := []string{}
+	for _, key := range sortedLeafKeys(state.Leaves) {
+		stateLeaf := state.Leaves[key]
+		leaf, ok := sData.Leaves[key]
+		if !ok || sData.leafChanged(&leaf, state, &stateLeaf) {
+			changedLeaves = append(changedLeaves, key)
+		}
+	}
+	addedLeaves := []string{}
+	for _, key := range sortedLeafKeys(sData.Leaves) {
+		if _, ok := state.Leaves[key]; !ok {
+			addedLeaves = append(addedLeaves, key)
+		}
+	}
+	return changedLeaves, addedLeaves
+}
+
+// networkConnectionChanged tells whether the NC is updated in place.
+func (sData *ServiceResourceData) networkConnectionChanged(state *ServiceResourceData) bool {
+	return !sData.Name.Equal(state.Name) || !sData.ServiceMode.Equal(state.ServiceMode) || !sData.Labels.Equal(state.Labels)
+}
+
+// transportCapacitiesChanged tells whether the TCs of the leaves are updated in place.
+func (sData *ServiceResourceData) transportCapacitiesChanged(state *ServiceResourceData) bool {
+	return !sData.Name.Equal(state.Name) || !sData.Labels.Equal(state.Labels)
+}
+
+// leafChanged tells whether the TC of the leaf must be created again.
+func (sData *ServiceResourceData) leafChanged(leaf *ServiceLeafData, state *ServiceResourceData, stateLeaf *ServiceLeafData) bool {
+	return !reflect.DeepEqual(leaf.Selector, stateLeaf.Selector) ||
+		!sData.leafCapacity(leaf).Equal(state.leafCapacity(stateLeaf)) ||
+		!sData.CapacityMode.Equal(state.CapacityMode) ||
+		!reflect.DeepEqual(sData.Hub, state.Hub)
+}
+
+func (sData *ServiceResourceData) leafCapacity(leaf *ServiceLeafData) types.Int64 {
+	if !leaf.Capacity.IsNull() {
+		return leaf.Capacity
+	}
+	return sData.Capacity
+}
+
+func (sData *ServiceResourceData) labels() types.Map {
+	if sData.Labels.IsNull() || sData.Labels.IsUnknown() {
+		return types.MapNull(types.StringType)
+	}
+	return sData.Labels
+}
+
+func (sData *ServiceResourceData) hasTransportCapacity() bool {
+	for _, leaf := range sData.Leaves {
+		if !leaf.TransportCapacityId.IsNull() {
+			return true
+		}
+	}
+	return false
+}
+
+// setLeavesUnknown plans an unknown value for the computed attribute of the leaves.
+func setLeavesUnknown(ctx context.Context, keys []string, attribute string, resp *resource.ModifyPlanResponse) {
+	for _, key := range keys {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("leaves").AtMapKey(key).AtName(attribute), types.StringUnknown())...)
+	}
+}
+
+func stateValue(state types.Object, name string) types.String {
+	if state.IsNull() || state.IsUnknown() {
+		return types.StringNull()
+	}
+	value, ok := state.Attributes()[name].(types.String)
+	if !ok {
+		return types.StringNull()
+	}
+	return value
+}
+
+func sortedLeafKeys(leaves map[string]ServiceLeafData) []string {
+	keys := make([]string, 0, len(leaves))
+	for k := range leaves {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func ServiceSchemaAttributes() map[string]schema.Attribute {
+	hub := common.IfSelectorSchema()
+	hub.Description = "client interface of the hub module"
+	hub.Optional = false
+	hub.Required = true
+	leafSelector := common.IfSelectorSchema()
+	leafSelector.Description = "client interface of the leaf module"
+	leafSelector.Optional = false
+	leafSelector.Required = true
+
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Description: "Identifier of the Network Connection of the service, unknown in the plan when the NC is replaced",
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"name": schema.StringAttribute{
+			Description: "name of the NC. The TCs are named <name>-<leaf>",
+			Required:    true,
+		},
+		"service_mode": schema.StringAttribute{
+			Description: "service_mode of the NC",
+			Required:    true,
+		},
+		"capacity_mode": schema.StringAttribute{
+			Description: "capacity_mode of the TCs",
+			Optional:    true,
+		},
+		"labels": schema.MapAttribute{
+			Description: "labels of the NC and TCs",
+			Optional:    true,
+			ElementType: types.StringType,
+		},
+		"capacity": schema.Int64Attribute{
+			Description: "capacity of the leaves which do not specify one",
+			Required:    true,
+		},
+		"timeout": schema.Int64Attribute{
+			Description: "seconds to wait for the TCs to be configured before the NC is created, default 600",
+			Optional:    true,
+		},
+		"hub": hub,
+		"leaves": schema.MapNestedAttribute{
+			Description: "leaf client interfaces by name",
+			Required:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"selector": leafSelector,
+					"capacity": schema.Int64Attribute{
+						Description: "capacity of the leaf",
+						Optional:    true,
+					},
+					"transport_capacity_id": schema.StringAttribute{
+						Description: "Identifier of the TC between the hub and the leaf",
+						Computed:    true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+					"lifecycle_state": schema.StringAttribute{
+						Description: "lifecycle state of the TC",
+						Computed:    true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+				},
+			},
+		},
+		"lifecycle_state": schema.StringAttribute{
+			Description: "lifecycle state of the NC",
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+	}
+}
@@ -0,0 +1,105 @@
+package serviceintent
+
+import (
+	"reflect"
+	"testing"
+
+	common "terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testSelector(moduleName string, clientIfAid string) common.IfSelector {
+	return common.IfSelector{
+		ModuleIfSelectorByModuleName: &common.ModuleIfSelectorByModuleName{
+			ModuleName:        types.StringValue(moduleName),
+			ModuleClientIfAid: types.StringValue(clientIfAid),
+		},
+	}
+}
+
+func testLeaf(moduleName string, capacity types.Int64) ServiceLeafData {
+	return ServiceLeafData{
+		Selector:            testSelector(moduleName, "XR-T1"),
+		Capacity:            capacity,
+		TransportCapacityId: types.StringNull(),
+		LifecycleState:      types.StringNull(),
+	}
+}
+
+func testService(capacity int64, leaves map[string]ServiceLeafData) ServiceResourceData {
+	return ServiceResourceData{
+		Name:         types.StringValue("service"),
+		CapacityMode: types.StringValue("dedicatedDownlinkSymmetric"),
+		Capacity:     types.Int64Value(capacity),
+		Hub:          testSelector("hub", "XR-T1"),
+		Leaves:       leaves,
+	}
+}
+
+func TestEndpointChanges(t *testing.T) {
+	state := testService(100, map[string]ServiceLeafData{
+		"a": testLeaf("leaf-a", types.Int64Null()),
+		"b": testLeaf("leaf-b", types.Int64Value(200)),
+	})
+	otherHub := testService(100, state.Leaves)
+	otherHub.Hub = testSelector("hub", "XR-T2")
+	otherMode := testService(100, state.Leaves)
+	otherMode.CapacityMode = types.StringValue("sharedDownlinkSymmetric")
+	tests := []struct {
+		name    string
+		plan    ServiceResourceData
+		changed []string
+		added   []string
+	}{
+		{"unchanged", testService(100, state.Leaves), []string{}, []string{}},
+		{"capacity of the leaves without capacity", testService(400, state.Leaves), []string{"a"}, []string{}},
+		{"capacity of a leaf", testService(100, map[string]ServiceLeafData{
+			"a": testLeaf("leaf-a", types.Int64Null()),
+			"b": testLeaf("leaf-b", types.Int64Value(400)),
+		}), []string{"b"}, []string{}},
+		{"same capacity set on the leaf", testService(100, map[string]ServiceLeafData{
+			"a": testLeaf("leaf-a", types.Int64Value(100)),
+			"b": testLeaf("leaf-b", types.Int64Value(200)),
+		}), []string{}, []string{}},
+		{"selector", testService(100, map[string]ServiceLeafData{
+			"a": testLeaf("leaf-c", types.Int64Null()),
+			"b": testLeaf("leaf-b", types.Int64Value(200)),
+		}), []string{"a"}, []string{}},
+		{"leaf removed and added", testService(100, map[string]ServiceLeafData{
+			"b": testLeaf("leaf-b", types.Int64Value(200)),
+			"c": testLeaf("leaf-c", types.Int64Null()),
+		}), []string{"a"}, []string{"c"}},
+		{"hub", otherHub, []string{"a", "b"}, []string{}},
+		{"capacity mode", otherMode, []string{"a", "b"}, []string{}},
+	}
+	for _, test := range tests {
+		changed, added := test.plan.endpointChanges(&state)
+		if !reflect.DeepEqual(changed, test.changed) || !reflect.DeepEqual(added, test.added) {
+			t.Errorf("%s: endpointChanges() = %v, %v, want %v, %v", test.name, changed, added, test.changed, test.added)
+		}
+	}
+}
+
+func TestLeafChanged(t *testing.T) {
+	state := testService(100, nil)
+	stateLeaf := testLeaf("leaf-a", types.Int64Null())
+	stateLeaf.TransportCapacityId = types.StringValue("tc1")
+	stateLeaf.LifecycleState = types.StringValue("configured")
+	tests := []struct {
+		name    string
+		plan    ServiceResourceData
+		leaf    ServiceLeafData
+		changed bool
+	}{
+		{"computed attributes", testService(100, nil), testLeaf("leaf-a", types.Int64Null()), false},
+		{"inherited capacity", testService(200, nil), testLeaf("leaf-a", types.Int64Null()), true},
+		{"own capacity", testService(100, nil), testLeaf("leaf-a", types.Int64Value(200)), true},
+		{"client interface", testService(100, nil), ServiceLeafData{Selector: testSelector("leaf-a", "XR-T2"), Capacity: types.Int64Null()}, true},
+	}
+	for _, test := range tests {
+		if changed := test.plan.leafChanged(&test.leaf, &state, &stateLeaf); changed != test.changed {
+			t.Errorf("%s: leafChanged() = %v, want %v", test.name, changed, test.changed)
+		}
+	}
+}
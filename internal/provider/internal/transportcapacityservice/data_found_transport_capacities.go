@@ -84,7 +84,7 @@ func (d *FoundTransportCapacitiesDataSource) Configure(_ context.Context, req da
 		return
 	}
 
-	d.client = req.ProviderData.(*ipm_pf.Client)
+	d.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (d *FoundTransportCapacitiesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -65,7 +65,7 @@ func (d *TransportCapacitiesDataSource) Configure(_ context.Context, req datasou
 		return
 	}
 
-	d.client = req.ProviderData.(*ipm_pf.Client)
+	d.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (d *TransportCapacitiesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -141,6 +141,10 @@ func TransportCapacityDataSchemaAttributes() map[string]schema.Attribute {
 			Description: "href",
 			Computed:    true,
 		},
+		"profile": schema.StringAttribute{
+			Description: "profile, not reported by IPM",
+			Computed:    true,
+		},
 		//Config           NetworkConfig `tfsdk:"config"`
 		"config": schema.SingleNestedAttribute{
 			Description: "config",
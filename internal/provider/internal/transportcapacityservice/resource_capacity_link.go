@@ -63,7 +63,7 @@ func (r *TCCapacityLinkResource) Configure(_ context.Context, req resource.Confi
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r TCCapacityLinkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -69,7 +69,7 @@ func (r *TCEndpointResource) Configure(_ context.Context, req resource.Configure
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
 }
 
 func (r TCEndpointResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
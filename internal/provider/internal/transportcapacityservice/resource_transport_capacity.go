@@ -26,6 +26,7 @@ var (
 	_ resource.Resource                = &TransportCapacityResource{}
 	_ resource.ResourceWithConfigure   = &TransportCapacityResource{}
 	_ resource.ResourceWithImportState = &TransportCapacityResource{}
+	_ resource.ResourceWithModifyPlan  = &TransportCapacityResource{}
 )
 
 // NewTransportCapacityResource is a helper function to simplify the provider implementation.
@@ -34,7 +35,8 @@ func NewTransportCapacityResource() resource.Resource {
 }
 
 type TransportCapacityResource struct {
-	client *ipm_pf.Client
+	client   *ipm_pf.Client
+	profiles *common.Profiles
 }
 
 
@@ -47,6 +49,7 @@ type TCConfig struct {
 type TransportCapacityResourceData struct {
 	Id               types.String                  `tfsdk:"id"`
 	Href             types.String                  `tfsdk:"href"`
+	Profile          types.String                  `tfsdk:"profile"`
 	Config           *TCConfig                      `tfsdk:"config"`
 	State            types.Object                  `tfsdk:"state"`
 	Endpoints        []TCEndpointResourceData      `tfsdk:"end_points"`
@@ -73,7 +76,8 @@ func (r *TransportCapacityResource) Configure(_ context.Context, req resource.Co
 		return
 	}
 
-	r.client = req.ProviderData.(*ipm_pf.Client)
+	r.client = req.ProviderData.(*common.ProviderData).Client
+	r.profiles = req.ProviderData.(*common.ProviderData).Profiles
 }
 
 func (r TransportCapacityResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -162,6 +166,36 @@ func (r TransportCapacityResource) Delete(ctx context.Context, req resource.Dele
 	resp.State.RemoveResource(ctx)
 }
 
+// ModifyPlan validates the profile reference and the endpoints at plan time.
+func (r *TransportCapacityResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var profile types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("profile"), &profile)...)
+	if !profile.IsNull() && !profile.IsUnknown() {
+		if _, err := r.profiles.FindTCProfile(profile.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("profile"), "TransportCapacityResource: Invalid profile", err.Error())
+		}
+	}
+	if r.client == nil || resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the endpoints are validated when they are created or changed, and known
+	var plan TransportCapacityResourceData
+	if diags := req.Plan.Get(ctx, &plan); diags.HasError() {
+		return
+	}
+	if !req.State.Raw.IsNull() {
+		var state TransportCapacityResourceData
+		if diags := req.State.Get(ctx, &state); diags.HasError() || !endpointsChanged(plan.Endpoints, state.Endpoints) {
+			return
+		}
+	}
+	validateEndpoints(ctx, r.client, r.profiles, &plan, false, &resp.Diagnostics)
+}
+
 func (r *TransportCapacityResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// Retrieve import ID and save to id attribute
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
@@ -179,6 +213,19 @@ func (r *TransportCapacityResource) create(plan *TransportCapacityResourceData,
 		return
 	}
 
+	var profile common.TCProfile
+	if !plan.Profile.IsNull() {
+		var err error
+		profile, err = r.profiles.FindTCProfile(plan.Profile.ValueString())
+		if err != nil {
+			diags.AddError(
+				"TransportCapacityResource: Error create TransportCapacity",
+				"Create: Could not Create TransportCapacity, "+err.Error(),
+			)
+			return
+		}
+	}
+
 	var createRequest = make(map[string]interface{})
 	var config = make(map[string]interface{})
 
@@ -196,98 +243,48 @@ func (r *TransportCapacityResource) create(plan *TransportCapacityResourceData,
 			config["labels"] = labels
 		}
 	}
+	common.MergeProfile(config, profile.Request(ctx))
 	createRequest["config"] = config
 
 	// get TC Endpoints
 	var endpoints []map[string]interface{}
-	var queryStrings []string
-	var endpointIds []string
-	for _, v := range plan.Endpoints {
+	var selectors []*common.IfSelector
+	for i, v := range plan.Endpoints {
 		endpoint := make(map[string]interface{})
-		queryString :=  "/xr-networks?content=expanded"
-		id := ""
 		endpoint["capacity"] = v.Config.Capacity.ValueInt64()
-		selector := make(map[string]interface{})
-		aSelector := make(map[string]interface{})
-		if v.Config.Selector.ModuleIfSelectorByModuleId != nil {
-			aSelector["moduleId"] = v.Config.Selector.ModuleIfSelectorByModuleId.ModuleId.ValueString()
-			aSelector["moduleClientIfAid"] = v.Config.Selector.ModuleIfSelectorByModuleId.ModuleClientIfAid.ValueString()
-			selector["moduleIfSelectorByModuleId"] = aSelector
-			id = aSelector["moduleId"].(string)
-			queryString = queryString + "&q={\"hubModule.state.module.moduleId\":\"" + id + "\"}"
-		} else if v.Config.Selector.ModuleIfSelectorByModuleName != nil {
-			aSelector["moduleName"] = v.Config.Selector.ModuleIfSelectorByModuleName.ModuleName.ValueString()
-			aSelector["moduleClientIfAid"] = v.Config.Selector.ModuleIfSelectorByModuleName.ModuleClientIfAid.ValueString()
-			tflog.Debug(ctx, "TransportCapacityResource: create ## moduleName", map[string]interface{}{"ModuleClientIfAid": aSelector["moduleClientIfAid"]})
-			selector["moduleIfSelectorByModuleName"] = aSelector
-			id = aSelector["moduleName"].(string)
-			queryString = queryString + "&q={\"hubModule.state.module.moduleName\":\"" + id + "\"}"
-		} else if v.Config.Selector.ModuleIfSelectorByModuleMAC != nil {
-			aSelector["moduleMAC"] = v.Config.Selector.ModuleIfSelectorByModuleMAC.ModuleMAC.ValueString()
-			aSelector["moduleClientIfAid"] = v.Config.Selector.ModuleIfSelectorByModuleMAC.ModuleClientIfAid.ValueString()
-			selector["moduleIfSelectorByModuleMAC"] = aSelector
-			id = aSelector["moduleMAC"].(string)
-			queryString = queryString + "&q={\"hubModule.state.module.macAddress\":\"" + id + "\"}"
-		} else if v.Config.Selector.ModuleIfSelectorByModuleSerialNumber != nil {
-			aSelector["moduleSerialNumber"] = v.Config.Selector.ModuleIfSelectorByModuleSerialNumber.ModuleSerialNumber.ValueString()
-			aSelector["moduleClientIfAid"] = v.Config.Selector.ModuleIfSelectorByModuleSerialNumber.ModuleClientIfAid.ValueString()
-			selector["moduleIfSelectorByModuleSerialNumber"] = aSelector
-			id = aSelector["moduleSerialNumber"].(string)
-			queryString = queryString + "&q={\"hubModule.state.module.serialNumber\":\"" + id + "\"}"
-		} else if v.Config.Selector.HostPortSelectorByName != nil {
-			aSelector["hostName"] = v.Config.Selector.HostPortSelectorByName.HostName.ValueString()
-			aSelector["hostPortName"] = v.Config.Selector.HostPortSelectorByName.HostPortName.ValueString()
-			selector["hostPortSelectorByName"] = aSelector
-			id = aSelector["hostName"].(string)
-			queryString = queryString + "&q={\"$and\":[{\"hubModule.config.selector.hostPortSelectorByName.hostName\":\"" + id + "\"}, {\"hubModule.config.selector.hostPortSelectorByName.hostPortName\":\"" + aSelector["hostPortName"].(string) + "\"}]}"
-			id = id + ":" + aSelector["hostPortName"].(string)
-		}  else if v.Config.Selector.HostPortSelectorByPortId != nil {
-			aSelector["chassisIdSubtype"] = v.Config.Selector.HostPortSelectorByPortId.ChassisIdSubtype.ValueString()
-			aSelector["chassisId"] = v.Config.Selector.HostPortSelectorByPortId.ChassisId.ValueString()
-			aSelector["portIdSubtype"] = v.Config.Selector.HostPortSelectorByPortId.PortIdSubtype.ValueString()
-			aSelector["portId"] = v.Config.Selector.HostPortSelectorByPortId.PortId.ValueString()
-			selector["hostPortSelectorByPortId"] = aSelector
-			id = aSelector["chassisId"].(string)
-			queryString = queryString + "&q={\"$and\":[{\"hubModule.config.selector.hostPortSelectorByPortId.chassisIdSubtype\":\"" + aSelector["chassisIdSubtype"].(string) + "\"}, {\"hubModule.config.selector.hostPortSelectorByPortId.chassisId\":\"" + id + "\"}, {\"hubModule.config.selector.hostPortSelectorByPortId.portId\":\"" + aSelector["portId"].(string) + "\"}, {\"hubModule.config.selector.hostPortSelectorByPortId.portIdSubtype\":\"" + aSelector["portIdSubtype"].(string) + "\"}]}"
-			id = id + ":" + aSelector["portId"].(string)
-		} else if v.Config.Selector.HostPortSelectorBySysName != nil {
-			aSelector["sysName"] = v.Config.Selector.HostPortSelectorBySysName.SysName.ValueString()
-			aSelector["portIdSubtype"] = v.Config.Selector.HostPortSelectorBySysName.PortIdSubtype.ValueString()
-			aSelector["portId"] = v.Config.Selector.HostPortSelectorBySysName.PortId.ValueString()
-			selector["hostPortSelectorByPortId"] = aSelector
-			id = aSelector["sysName"].(string)
-			queryString = queryString + "&q={\"$and\":[{\"hubModule.config.selector.hostPortSelectorBySysName.sysName\":\"" + id + "\"}, {\"hubModule.config.selector.hostPortSelectorBySysName.portIdSubtype\":\"" + aSelector["portIdSubtype"].(string) + "\"}, {\"hubModule.config.selector.hostPortSelectorBySysName.portId\":\"" + aSelector["portId"].(string) + "\"}]"
-			id = id + ":" + aSelector["portId"].(string)
-		} else if v.Config.Selector.HostPortSelectorByPortSourceMAC != nil {
-			aSelector["portSourceMAC"] = v.Config.Selector.HostPortSelectorByPortSourceMAC.PortSourceMAC.ValueString()
-			selector["hostPortSelectorByName"] = aSelector
-			id = aSelector["portSourceMAC"].(string)
-			queryString = queryString + "&q={\"hubModule.config.selector.hostPortSelectorByPortSourceMAC.portSourceMAC\":\"" + id + "\"}"
-		} else {
+		if v.Config.Capacity.IsNull() && !profile.EndpointCapacity.IsNull() {
+			endpoint["capacity"] = profile.EndpointCapacity.ValueInt64()
+		}
+		variant, values := common.IfSelectorValues(&plan.Endpoints[i].Config.Selector)
+		if variant == "" {
 			diags.AddError(
 				"TransportCapacityResource: Error Create TC. No selector specify for Endpoint",
 				"Create: Could not create TransportCapacityResource, No selector specify for Endpoint",
 			)
 			return
 		}
+		selector := common.SelectorRequest(variant, values)
 		tflog.Debug(ctx, "TransportCapacityResource: create 1## ", map[string]interface{}{"Create Request selector": selector})
 		endpoint["selector"] = selector
 		endpoints = append(endpoints, endpoint)
-		queryStrings = append(queryStrings, queryString)
-		endpointIds =  append(endpointIds, id)
+		selectors = append(selectors, &plan.Endpoints[i].Config.Selector)
 	}
 
-	tflog.Debug(ctx, "TransportCapacityResource: Network QueryString ## ", map[string]interface{}{"QueryString1": queryStrings[0], "QueryString2": queryStrings[1]})
-
-	_, err := common.CheckNetworkState(ctx, r.client, queryStrings, endpointIds, 5) 
+	err := common.CheckMembersConfigured(ctx, r.client, selectors, 5)
 	if err != nil {
 		diags.AddError(
 			"Error Creating TC",
-			"Create: Could not create TC:  CheckNetworkState :" + queryStrings[0] + ":" + queryStrings[1] + err.Error(),
+			"Create: Could not create TC, the modules of the endpoints are not configured in their constellation: " + err.Error(),
 		)
 		return
 	}
 
+	// the modules are members of their constellation now, a module which is still neither the hub nor a leaf fails the TC
+	validateEndpoints(ctx, r.client, r.profiles, plan, true, diags)
+	if diags.HasError() {
+		return
+	}
+
 	createRequest["endpoints"] = endpoints
 
 	tflog.Debug(ctx, "TransportCapacityResource: create 2## ", map[string]interface{}{"Create Request": createRequest})
@@ -376,6 +373,24 @@ func (r *TransportCapacityResource) update(plan *TransportCapacityResourceData,
 			updateRequest["labels"] = labels
 		}
 	}
+	capacityMode := plan.Config.CapacityMode.ValueString()
+	if !plan.Profile.IsNull() {
+		profile, err := r.profiles.FindTCProfile(plan.Profile.ValueString())
+		if err != nil {
+			diags.AddError(
+				"TransportCapacityResource: Error Update TransportCapacity",
+				"Update: Could not Update TransportCapacity, "+err.Error(),
+			)
+			return
+		}
+		// the capacity mode is set at creation only
+		if labels, ok := profile.Request(ctx)["labels"]; ok {
+			common.MergeProfile(updateRequest, map[string]interface{}{"labels": labels})
+		}
+		if plan.Config.CapacityMode.IsNull() {
+			capacityMode = profile.CapacityMode.ValueString()
+		}
+	}
 
 	tflog.Debug(ctx, "TransportCapacityResource: update ## ", map[string]interface{}{"id": plan.Id.ValueString(),"Update Request": updateRequest})
 
@@ -412,7 +427,7 @@ func (r *TransportCapacityResource) update(plan *TransportCapacityResourceData,
 		}
 	}
 	// Check for Update existing endpoint
-	if plan.Config.CapacityMode.ValueString() != "portMode" {
+	if capacityMode != "portMode" {
 		for _, ep := range plan.Endpoints {
 			ep.Update(r.client, ctx, diags)
 			if diags.HasError() {
@@ -502,6 +517,30 @@ func (r *TransportCapacityResource) delete(plan *TransportCapacityResourceData,
 	tflog.Debug(ctx, "NetworkResource: delete ## ", map[string]interface{}{"plan": plan})
 }
 
+// CreateTransportCapacity creates the TC as ipm_transport_capacity does, for the resources composing TCs.
+func CreateTransportCapacity(client *ipm_pf.Client, plan *TransportCapacityResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	r := TransportCapacityResource{client: client}
+	r.create(plan, ctx, diags)
+}
+
+// UpdateTransportCapacity updates the TC as ipm_transport_capacity does.
+func UpdateTransportCapacity(client *ipm_pf.Client, plan *TransportCapacityResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	r := TransportCapacityResource{client: client}
+	r.update(plan, ctx, diags)
+}
+
+// ReadTransportCapacity reads the TC as ipm_transport_capacity does.
+func ReadTransportCapacity(client *ipm_pf.Client, state *TransportCapacityResourceData, ctx context.Context, diags *diag.Diagnostics, retryCount ...int) {
+	r := TransportCapacityResource{client: client}
+	r.read(state, ctx, diags, retryCount...)
+}
+
+// DeleteTransportCapacity deletes the TC as ipm_transport_capacity does.
+func DeleteTransportCapacity(client *ipm_pf.Client, state *TransportCapacityResourceData, ctx context.Context, diags *diag.Diagnostics) {
+	r := TransportCapacityResource{client: client}
+	r.delete(state, ctx, diags)
+}
+
 func (tcData *TransportCapacityResourceData) Populate(data map[string]interface{}, ctx context.Context, diags *diag.Diagnostics, computeOnly ...bool) {
 
 	computeFlag := false
@@ -589,6 +628,10 @@ func TransportCapacitySchemaAttributes() map[string]schema.Attribute {
 				stringplanmodifier.UseStateForUnknown(),
 			},
 		},
+		"profile": schema.StringAttribute{
+			Description: "Name of a TC profile of the provider profiles. The config settings and endpoint capacities which are not specified are taken from the profile.",
+			Optional:    true,
+		},
 		//Config           NetworkConfig `tfsdk:"config"`
 		"config": schema.SingleNestedAttribute{
 			Description: "config",
@@ -0,0 +1,257 @@
+package transportcapacity
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"terraform-provider-ipm/internal/ipm_pf"
+	"terraform-provider-ipm/internal/provider/internal/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// dscCapacities is the capacity in G of a digital subcarrier by modulation of the constellation.
+var dscCapacities = map[string]float64{
+	"16QAM": 25,
+	"QPSK":  12.5,
+}
+
+// endpointModule is the constellation module of a TC endpoint.
+type endpointModule struct {
+	networkId   string
+	networkName string
+	role        string
+	moduleId    string
+	moduleName  string
+	clientIfAid string
+	modulation  string
+	maxDSCs     int64
+	portSpeed   int64
+}
+
+func (m *endpointModule) String() string {
+	return m.role + " module " + m.moduleName + " of constellation " + m.networkName
+}
+
+// validateEndpoints checks the TC endpoints before the TC is created or changed: both endpoints must be modules of the same constellation,
+// their client interfaces must exist, the capacity must be within the module limits, and the client interfaces must not be used by another TC.
+// A selector which is not resolved to a module of a constellation is a warning at plan time, as the module may join the constellation during the apply.
+// Endpoints with unknown values are left to IPM.
+func validateEndpoints(ctx context.Context, client *ipm_pf.Client, profiles *common.Profiles, plan *TransportCapacityResourceData, final bool, diags *diag.Diagnostics) {
+	if len(plan.Endpoints) != 2 {
+		return
+	}
+
+	var profile common.TCProfile
+	if !plan.Profile.IsNull() && !plan.Profile.IsUnknown() {
+		profile, _ = profiles.FindTCProfile(plan.Profile.ValueString())
+	}
+
+	resolver := common.NewSelectorResolver(client)
+	modules := make([]*endpointModule, len(plan.Endpoints))
+	capacities := make([]int64, len(plan.Endpoints))
+	for i, endpoint := range plan.Endpoints {
+		selectorPath := path.Root("end_points").AtListIndex(i).AtName("config").AtName("selector")
+		if !common.IfSelectorKnown(&endpoint.Config.Selector) {
+			continue
+		}
+		summary := "TransportCapacityResource: Endpoint module not found"
+		resolved, err := resolver.ResolveIfSelector(&endpoint.Config.Selector)
+		var module *endpointModule
+		detail := ""
+		if err != nil {
+			detail = "Endpoint " + strconv.Itoa(i) + ": " + err.Error() + "."
+		} else if module = findEndpointModule(resolver, resolved); module == nil {
+			detail = "Module " + resolved.ModuleName + " of endpoint " + strconv.Itoa(i) + " is neither the hub nor a leaf of a constellation."
+		}
+		if module == nil {
+			if final {
+				diags.AddAttributeError(selectorPath, summary, detail)
+			} else {
+				diags.AddAttributeWarning(selectorPath, summary, detail+" It must be found before the TC is created.")
+			}
+			continue
+		}
+		clientIfAid := resolved.ClientIfAid
+		module.clientIfAid = clientIfAid
+
+		clients, err := common.GetResources(client, "/modules/"+module.moduleId+"/ethernetClients?content=expanded")
+		if err != nil {
+			diags.AddAttributeWarning(selectorPath, "TransportCapacityResource: Client interface is not validated", "Could not get the client interfaces of "+module.String()+": "+err.Error())
+		} else {
+			aids := []string{}
+			found := false
+			for _, c := range clients {
+				eClient, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				aid, _ := common.LookupValue(eClient, "state.clientIfAid")
+				aids = append(aids, aid)
+				if aid == clientIfAid {
+					found = true
+					speed, _ := common.LookupValue(eClient, "state.clientIfPortSpeed")
+					portSpeed, _ := strconv.ParseFloat(speed, 64)
+					module.portSpeed = int64(portSpeed)
+				}
+			}
+			if !found {
+				sort.Strings(aids)
+				diags.AddAttributeError(selectorPath, "TransportCapacityResource: Client interface not found",
+					"Client interface "+clientIfAid+" does not exist on "+module.String()+". Client interfaces: "+strings.Join(aids, ", ")+".")
+				continue
+			}
+		}
+		modules[i] = module
+
+		capacity := endpoint.Config.Capacity
+		if capacity.IsNull() {
+			capacity = profile.EndpointCapacity
+		}
+		if capacity.IsNull() || capacity.IsUnknown() {
+			continue
+		}
+		capacityPath := path.Root("end_points").AtListIndex(i).AtName("config").AtName("capacity")
+		capacities[i] = capacity.ValueInt64()
+		if detail := module.checkCapacity(capacities[i]); detail != "" {
+			diags.AddAttributeError(capacityPath, "TransportCapacityResource: Invalid capacity", detail)
+		}
+	}
+
+	if modules[0] == nil || modules[1] == nil {
+		return
+	}
+	if modules[0].networkId != modules[1].networkId {
+		diags.AddAttributeError(path.Root("end_points"), "TransportCapacityResource: Endpoints in different constellations",
+			"The endpoints are the "+modules[0].String()+" and the "+modules[1].String()+".")
+		return
+	}
+	if modules[0].role == modules[1].role {
+		diags.AddAttributeError(path.Root("end_points"), "TransportCapacityResource: Invalid endpoints",
+			"A TC connects the hub and a leaf of a constellation, the endpoints are the "+modules[0].String()+" and the "+modules[1].String()+".")
+		return
+	}
+
+	validateEndpointUsage(ctx, client, plan, modules, capacities, diags)
+}
+
+// checkCapacity returns why the capacity does not fit the module, or "". The capacity of a digital subcarrier depends on
+// the modulation of the constellation, 16QAM when it is not set. The DSC limits are not checked for other modulations.
+func (m *endpointModule) checkCapacity(capacity int64) string {
+	modulation := m.modulation
+	if modulation == "" {
+		modulation = "16QAM"
+	}
+	dscCapacity, known := dscCapacities[modulation]
+	dscs := strconv.FormatFloat(dscCapacity, 'f', -1, 64)
+	switch {
+	case capacity <= 0:
+		return "Capacity " + strconv.FormatInt(capacity, 10) + " must be greater than 0."
+	case known && math.Mod(float64(capacity), dscCapacity) != 0:
+		return "Capacity " + strconv.FormatInt(capacity, 10) + " is not a multiple of the " + dscs + "G digital subcarrier capacity of the " + modulation + " constellation " + m.networkName + "."
+	case m.portSpeed > 0 && capacity > m.portSpeed:
+		return "Capacity " + strconv.FormatInt(capacity, 10) + " exceeds the " + strconv.FormatInt(m.portSpeed, 10) + "G port speed of client interface " + m.clientIfAid + " of " + m.String() + "."
+	case known && m.maxDSCs > 0 && float64(capacity) > float64(m.maxDSCs)*dscCapacity:
+		return "Capacity " + strconv.FormatInt(capacity, 10) + " exceeds the " + strconv.FormatInt(m.maxDSCs, 10) + " " + dscs + "G DSCs of " + m.String() + "."
+	}
+	return ""
+}
+
+// validateEndpointUsage checks that no other TC connects the same client interfaces, and that the client interfaces have
+// enough port speed left for the TC.
+func validateEndpointUsage(ctx context.Context, client *ipm_pf.Client, plan *TransportCapacityResourceData, modules []*endpointModule, capacities []int64, diags *diag.Diagnostics) {
+	tcs, err := common.GetResources(client, "/transport-capacities?content=expanded")
+	if err != nil {
+		diags.AddWarning("TransportCapacityResource: Endpoints usage is not validated", "Could not get the transport capacities: "+err.Error())
+		return
+	}
+	used := make([]int64, len(modules))
+	users := make([][]string, len(modules))
+	for _, t := range tcs {
+		tc, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := tc["id"].(string)
+		if id == "" || id == plan.Id.ValueString() {
+			continue
+		}
+		name, _ := common.LookupValue(tc, "config.name")
+		tcName := name + " (" + id + ")"
+		endpoints, _ := tc["endpoints"].([]interface{})
+		matches := 0
+		for _, e := range endpoints {
+			endpoint, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			moduleId, _ := common.LookupValue(endpoint, "state.moduleIf.moduleId")
+			clientIfAid, _ := common.LookupValue(endpoint, "state.moduleIf.clientIfAid")
+			for i, module := range modules {
+				if moduleId != module.moduleId || clientIfAid != module.clientIfAid {
+					continue
+				}
+				matches++
+				capacity, found := common.LookupValue(endpoint, "state.capacity")
+				if !found {
+					capacity, _ = common.LookupValue(endpoint, "config.capacity")
+				}
+				value, _ := strconv.ParseFloat(capacity, 64)
+				used[i] += int64(value)
+				users[i] = append(users[i], tcName)
+			}
+		}
+		if matches == len(modules) {
+			diags.AddAttributeError(path.Root("end_points"), "TransportCapacityResource: Endpoints already connected",
+				"TC "+tcName+" already connects client interface "+modules[0].clientIfAid+" of the "+modules[0].String()+" and client interface "+modules[1].clientIfAid+" of the "+modules[1].String()+".")
+			return
+		}
+	}
+	for i, module := range modules {
+		if module.portSpeed == 0 || capacities[i] == 0 || used[i]+capacities[i] <= module.portSpeed {
+			continue
+		}
+		diags.AddAttributeError(path.Root("end_points").AtListIndex(i), "TransportCapacityResource: Client interface in use",
+			"Client interface "+module.clientIfAid+" of the "+module.String()+" is used by TC "+strings.Join(users[i], ", ")+" for "+strconv.FormatInt(used[i], 10)+
+				"G of its "+strconv.FormatInt(module.portSpeed, 10)+"G, "+strconv.FormatInt(capacities[i], 10)+"G are not available.")
+	}
+	tflog.Debug(ctx, "TransportCapacityResource: endpoints usage", map[string]interface{}{"used": used, "users": users})
+}
+
+// findEndpointModule returns the hub or leaf module of the resolved endpoint, or nil.
+func findEndpointModule(resolver *common.SelectorResolver, resolved *common.ResolvedIf) *endpointModule {
+	member, err := resolver.ConstellationMember(resolved.ModuleId)
+	if err != nil || member == nil {
+		return nil
+	}
+	maxDSCs, _ := common.LookupValue(member.Data, "config.module.maxDSCs")
+	dscs, _ := strconv.ParseFloat(maxDSCs, 64)
+	return &endpointModule{
+		networkId:   member.NetworkId,
+		networkName: member.NetworkName,
+		role:        member.Role,
+		moduleId:    resolved.ModuleId,
+		moduleName:  resolved.ModuleName,
+		modulation:  member.Modulation,
+		maxDSCs:     int64(dscs),
+	}
+}
+
+// endpointsChanged tells whether the configuration of the endpoints changed.
+func endpointsChanged(plan []TCEndpointResourceData, state []TCEndpointResourceData) bool {
+	if len(plan) != len(state) {
+		return true
+	}
+	for i := range plan {
+		if !plan[i].Config.Capacity.Equal(state[i].Config.Capacity) || !reflect.DeepEqual(plan[i].Config.Selector, state[i].Config.Selector) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package transportcapacity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckCapacity(t *testing.T) {
+	leaf := func(modulation string, maxDSCs int64, portSpeed int64) *endpointModule {
+		return &endpointModule{
+			networkName: "north",
+			role:        "leaf",
+			moduleName:  "leaf-1",
+			clientIfAid: "XR-T1",
+			modulation:  modulation,
+			maxDSCs:     maxDSCs,
+			portSpeed:   portSpeed,
+		}
+	}
+	tests := []struct {
+		name     string
+		module   *endpointModule
+		capacity int64
+		detail   string
+	}{
+		{"16QAM multiple", leaf("16QAM", 4, 100), 100, ""},
+		{"default modulation", leaf("", 4, 100), 75, ""},
+		{"QPSK multiple", leaf("QPSK", 4, 100), 50, ""},
+		{"no limits", leaf("16QAM", 0, 0), 400, ""},
+		{"unknown modulation", leaf("8QAM", 4, 0), 130, ""},
+		{"zero", leaf("16QAM", 4, 100), 0, "must be greater than 0"},
+		{"negative", leaf("16QAM", 4, 100), -25, "must be greater than 0"},
+		{"16QAM not a multiple", leaf("", 4, 100), 60, "not a multiple of the 25G digital subcarrier capacity of the 16QAM constellation north"},
+		{"QPSK not a multiple", leaf("QPSK", 8, 100), 30, "not a multiple of the 12.5G digital subcarrier capacity of the QPSK constellation north"},
+		{"port speed", leaf("16QAM", 16, 100), 200, "exceeds the 100G port speed of client interface XR-T1 of leaf module leaf-1 of constellation north"},
+		{"16QAM DSCs", leaf("16QAM", 4, 400), 125, "exceeds the 4 25G DSCs of leaf module leaf-1 of constellation north"},
+		{"QPSK DSCs", leaf("QPSK", 4, 400), 75, "exceeds the 4 12.5G DSCs"},
+	}
+	for _, test := range tests {
+		detail := test.module.checkCapacity(test.capacity)
+		if (detail == "") != (test.detail == "") || !strings.Contains(detail, test.detail) {
+			t.Errorf("%s: checkCapacity(%d) = %q, want %q", test.name, test.capacity, detail, test.detail)
+		}
+	}
+}
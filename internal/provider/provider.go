@@ -5,7 +5,7 @@ import (
 	"os"
 
 	"terraform-provider-ipm/internal/ipm_pf"
-	//"terraform-provider-ipm/internal/provider/internal/common"
+	"terraform-provider-ipm/internal/provider/internal/common"
 	network "terraform-provider-ipm/internal/provider/internal/networkservice"
 	networkconnection "terraform-provider-ipm/internal/provider/internal/networkconnectionservice"
 	transportcapacity "terraform-provider-ipm/internal/provider/internal/transportcapacityservice"
@@ -15,19 +15,24 @@ import (
 	event "terraform-provider-ipm/internal/provider/internal/eventservice"
 	mqttServer "terraform-provider-ipm/internal/provider/internal/mqttserverservice"
 	actions "terraform-provider-ipm/internal/provider/internal/actionservice"
+	serviceintent "terraform-provider-ipm/internal/provider/internal/serviceintentservice"
 
 
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure ScaffoldingProvider satisfies various provider interfaces.
 var _ provider.Provider = &XRProvider{}
+var _ provider.ProviderWithActions = &XRProvider{}
+var _ provider.ProviderWithValidateConfig = &XRProvider{}
 
 // New is a helper function to simplify provider server and testing implementation.
 func New() provider.Provider {
@@ -45,6 +50,7 @@ type XRProviderModel struct {
 	Username types.String `tfsdk:"username"`
 	Host     types.String `tfsdk:"host"`
 	Password types.String `tfsdk:"password"`
+	Profiles types.Object `tfsdk:"profiles"`
 }
 
 func (p *XRProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -70,10 +76,28 @@ func (p *XRProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *p
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"profiles": common.ProfilesSchema(),
 		},
 	}
 }
 
+// ValidateConfig checks the references between the profiles.
+func (p *XRProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var config XRProviderModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// profiles with unknown values are validated when the provider is configured
+	profiles, known := getProfiles(ctx, config.Profiles)
+	if !known {
+		return
+	}
+	resp.Diagnostics.Append(profiles.Validate(path.Root("profiles"))...)
+}
+
 func (p *XRProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var config XRProviderModel
 
@@ -85,6 +109,21 @@ func (p *XRProvider) Configure(ctx context.Context, req provider.ConfigureReques
 		return
 	}
 
+	// resources refer to the profiles by name
+	profiles, known := getProfiles(ctx, config.Profiles)
+	if !known {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("profiles"),
+			"Unknown profiles",
+			"The provider cannot use the profiles as they have unknown configuration values. Set the profiles statically in the configuration.",
+		)
+		return
+	}
+	resp.Diagnostics.Append(profiles.Validate(path.Root("profiles"))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// User must provide a user to the provider
 	if config.Host.IsUnknown() {
 		// Cannot connect to client with an unknown Host value
@@ -191,8 +230,10 @@ func (p *XRProvider) Configure(ctx context.Context, req provider.ConfigureReques
 
 	// Make the XR client available during DataSource and Resource
 	// type Configure methods.
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	providerData := &common.ProviderData{Client: client, Profiles: profiles}
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
+	resp.ActionData = providerData
 
 // listen to wss 
 /*
@@ -216,6 +257,21 @@ wssClient, error := common.NewWebSocketClient(os.Getenv("IPM_HOST"), os.Getenv("
 	tflog.Debug(ctx, "provider: ipm - successful connection request")
 }
 
+// getProfiles converts the profiles block. It returns false when the profiles are not known yet.
+func getProfiles(ctx context.Context, value types.Object) (*common.Profiles, bool) {
+	profiles := &common.Profiles{}
+	if value.IsNull() {
+		return profiles, true
+	}
+	if value.IsUnknown() {
+		return nil, false
+	}
+	if diags := value.As(ctx, profiles, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return nil, false
+	}
+	return profiles, true
+}
+
 // DataSources defines the data sources implemented in the provider.
 func (p *XRProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
@@ -224,6 +280,9 @@ func (p *XRProvider) DataSources(_ context.Context) []func() datasource.DataSour
 		network.NewHubModuleDataSource,
 		network.NewLeafModulesDataSource,
 		network.NewReachableModulesDataSource,
+		network.NewConstellationCapacityDataSource,
+		network.NewResolveSelectorDataSource,
+		network.NewTopologyDataSource,
 		networkconnection.NewNetworkConnectionsDataSource,
 		networkconnection.NewFoundNetworkConnectionsDataSource,
 		networkconnection.NewACsDataSource,
@@ -231,6 +290,7 @@ func (p *XRProvider) DataSources(_ context.Context) []func() datasource.DataSour
 		networkconnection.NewNCEndpointsDataSource,
 		host.NewHostsDataSource,
 		host.NewHostPortsDataSource,
+		host.NewHostDiscoveryDataSource,
 		transportcapacity.NewCapacityLinksDataSource,
 		transportcapacity.NewTCEndpointsDataSource,
 		transportcapacity.NewTransportCapacitiesDataSource,
@@ -243,24 +303,33 @@ func (p *XRProvider) DataSources(_ context.Context) []func() datasource.DataSour
 		module.NewDSCsDataSource,
 		module.NewEClientsDataSource,
 		module.NewModulesDataSource,
+		module.NewModulePMDataSource,
 		module.NewODUsDataSource,
 		module.NewOTUsDataSource,
 		ndu.NewCarriersDataSource,
 		ndu.NewEClientsDataSource,
 		ndu.NewEDFAsDataSource,
+		ndu.NewFanUnitDataSource,
 		ndu.NewLCsDataSource,
+		ndu.NewLEDsDataSource,
 		ndu.NewLinePTPsDataSource,
 		ndu.NewNDUsDataSource,
+		ndu.NewNDUHealthDataSource,
+		ndu.NewNDUInventoryDataSource,
+		ndu.NewNDUPMDataSource,
 		ndu.NewOTUsDataSource,
+		ndu.NewPEMDataSource,
 		ndu.NewPolPTPsDataSource,
 		ndu.NewPortsDataSource,
 		ndu.NewTOMsDataSource,
 		ndu.NewTribPTPsDataSource,
 		ndu.NewVOAsDataSource,
 		ndu.NewXRsDataSource,
+		event.NewAlarmsDataSource,
 		event.NewEventsDataSource,
 		event.NewFoundEventsDataSource,
 		mqttServer.NewMQTTServerDataSource,
+		actions.NewActionCatalogDataSource,
 	}
 }
 
@@ -270,22 +339,29 @@ func (p *XRProvider) Resources(_ context.Context) []func() resource.Resource {
 		network.NewNetworkResource,
 		network.NewHubModuleResource,
 		network.NewLeafModuleResource,
+		network.NewConstellationResource,
+		network.NewLeafModulesResource,
+		network.NewLeafAdoptionResource,
 		networkconnection.NewACResource,
 		networkconnection.NewLCResource,
 		networkconnection.NewNetworkConnectionResource,
 		networkconnection.NewNCEndpointResource,
 		transportcapacity.NewTransportCapacityResource,
 		transportcapacity.NewTCCapacityLinkResource,
+		serviceintent.NewServiceResource,
 		host.NewHostResource,
 		host.NewHostPortResource,
+		host.NewHostsFromLLDPResource,
 		module.NewACResource,
 		module.NewCarrierResource,
 		module.NewDSCGResource,
 		module.NewDSCResource,
 		module.NewEClientResource,
 		module.NewLCResource,
+		module.NewLinkTestResource,
 		module.NewLinePTPResource,
 		module.NewModuleResource,
+		module.NewModulePolicyResource,
 		module.NewODUResource,
 		module.NewOTUResource,
 		ndu.NewCarrierResource,
@@ -297,6 +373,8 @@ func (p *XRProvider) Resources(_ context.Context) []func() resource.Resource {
 		ndu.NewEDFAResource,
 		ndu.NewVOAResource,
 		ndu.NewNDUResource,
+		ndu.NewNDUChassisResource,
+		ndu.NewNDUPowerBalanceResource,
 		ndu.NewOTUResource,
 		ndu.NewPEMResource,
 		ndu.NewPolPTPResource,
@@ -307,5 +385,15 @@ func (p *XRProvider) Resources(_ context.Context) []func() resource.Resource {
 		event.NewEventResource,
 		mqttServer.NewMQTTResource,
 		actions.NewActionsResource,
+		actions.NewSoftwareUpgradeResource,
+	}
+}
+
+// Actions defines the actions implemented in the provider.
+func (p *XRProvider) Actions(_ context.Context) []func() action.Action {
+	return []func() action.Action{
+		actions.NewModuleRestartAction,
+		actions.NewNDUAdoptAction,
+		actions.NewEClientClearLLDPAction,
 	}
 }